@@ -0,0 +1,276 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+type EntryType string
+
+const (
+	EntryTypeFunction    EntryType = "function"
+	EntryTypeConstructor EntryType = "constructor"
+	EntryTypeL1Handler   EntryType = "l1_handler"
+	EntryTypeEvent       EntryType = "event"
+	EntryTypeStruct      EntryType = "struct"
+	EntryTypeEnum        EntryType = "enum"
+	EntryTypeInterface   EntryType = "interface"
+	EntryTypeImpl        EntryType = "impl"
+)
+
+type StateMutability string
+
+const (
+	StateMutabilityView     StateMutability = "view"
+	StateMutabilityExternal StateMutability = "external"
+)
+
+// Param is a named and typed input of a function, a struct member or an enum variant
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Output is the type returned by a function
+type Output struct {
+	Type string `json:"type"`
+}
+
+type Function struct {
+	Type            EntryType       `json:"type"`
+	Name            string          `json:"name"`
+	Inputs          []Param         `json:"inputs"`
+	Outputs         []Output        `json:"outputs"`
+	StateMutability StateMutability `json:"state_mutability,omitempty"`
+}
+
+// IsView returns true if the function does not modify the contract state and can be called with starknet_call.
+func (f Function) IsView() bool {
+	return f.StateMutability == StateMutabilityView
+}
+
+type Struct struct {
+	Type    EntryType `json:"type"`
+	Name    string    `json:"name"`
+	Members []Param   `json:"members"`
+}
+
+type Enum struct {
+	Type     EntryType `json:"type"`
+	Name     string    `json:"name"`
+	Variants []Param   `json:"variants"`
+}
+
+type EventKind string
+
+const (
+	EventKindStruct EventKind = "struct"
+	EventKindEnum   EventKind = "enum"
+)
+
+type EventFieldKind string
+
+const (
+	EventFieldKindKey    EventFieldKind = "key"
+	EventFieldKindData   EventFieldKind = "data"
+	EventFieldKindNested EventFieldKind = "nested"
+	EventFieldKindFlat   EventFieldKind = "flat"
+)
+
+type EventField struct {
+	Name string         `json:"name"`
+	Type string         `json:"type"`
+	Kind EventFieldKind `json:"kind"`
+}
+
+type Event struct {
+	Type     EntryType    `json:"type"`
+	Name     string       `json:"name"`
+	Kind     EventKind    `json:"kind"`
+	Members  []EventField `json:"members,omitempty"`
+	Variants []EventField `json:"variants,omitempty"`
+}
+
+type Interface struct {
+	Type  EntryType  `json:"type"`
+	Name  string     `json:"name"`
+	Items []Function `json:"items"`
+}
+
+type Impl struct {
+	Type          EntryType `json:"type"`
+	Name          string    `json:"name"`
+	InterfaceName string    `json:"interface_name"`
+}
+
+// ABI is a parsed Cairo 1 contract ABI. Entries keep the order in which they
+// appear in the source so that anything derived from them is deterministic.
+type ABI struct {
+	// Functions holds every function of the contract, including the ones declared in interfaces
+	Functions   []Function
+	Constructor *Function
+	L1Handlers  []Function
+	Structs     []Struct
+	Enums       []Enum
+	Events      []Event
+	Interfaces  []Interface
+	Impls       []Impl
+
+	structs map[string]int
+	enums   map[string]int
+	events  map[string]int
+}
+
+// Parse parses a Cairo 1 ABI from its JSON representation.
+//
+// Parameters:
+// - data: the JSON array of ABI entries
+// Returns:
+// - *ABI: the parsed ABI
+// - error: an error if the JSON is invalid or contains an unknown entry type
+func Parse(data []byte) (*ABI, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid abi: %w", err)
+	}
+
+	abi := &ABI{
+		structs: map[string]int{},
+		enums:   map[string]int{},
+		events:  map[string]int{},
+	}
+	for i, entry := range raw {
+		var header struct {
+			Type EntryType `json:"type"`
+		}
+		if err := json.Unmarshal(entry, &header); err != nil {
+			return nil, fmt.Errorf("invalid abi entry %d: %w", i, err)
+		}
+
+		var err error
+		switch header.Type {
+		case EntryTypeFunction:
+			var f Function
+			if err = json.Unmarshal(entry, &f); err == nil {
+				abi.Functions = append(abi.Functions, f)
+			}
+		case EntryTypeConstructor:
+			var f Function
+			if err = json.Unmarshal(entry, &f); err == nil {
+				abi.Constructor = &f
+			}
+		case EntryTypeL1Handler:
+			var f Function
+			if err = json.Unmarshal(entry, &f); err == nil {
+				abi.L1Handlers = append(abi.L1Handlers, f)
+			}
+		case EntryTypeStruct:
+			var s Struct
+			if err = json.Unmarshal(entry, &s); err == nil {
+				abi.structs[normalizeType(s.Name)] = len(abi.Structs)
+				abi.Structs = append(abi.Structs, s)
+			}
+		case EntryTypeEnum:
+			var e Enum
+			if err = json.Unmarshal(entry, &e); err == nil {
+				abi.enums[normalizeType(e.Name)] = len(abi.Enums)
+				abi.Enums = append(abi.Enums, e)
+			}
+		case EntryTypeEvent:
+			var e Event
+			if err = json.Unmarshal(entry, &e); err == nil {
+				abi.events[normalizeType(e.Name)] = len(abi.Events)
+				abi.Events = append(abi.Events, e)
+			}
+		case EntryTypeInterface:
+			var it Interface
+			if err = json.Unmarshal(entry, &it); err == nil {
+				abi.Interfaces = append(abi.Interfaces, it)
+				abi.Functions = append(abi.Functions, it.Items...)
+			}
+		case EntryTypeImpl:
+			var im Impl
+			if err = json.Unmarshal(entry, &im); err == nil {
+				abi.Impls = append(abi.Impls, im)
+			}
+		default:
+			return nil, fmt.Errorf("unknown abi entry type %q", header.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s abi entry %d: %w", header.Type, i, err)
+		}
+	}
+	return abi, nil
+}
+
+// FromContractClass parses the ABI string of a Sierra contract class.
+//
+// Parameters:
+// - class: the contract class holding the ABI
+// Returns:
+// - *ABI: the parsed ABI
+// - error: an error if the class has no ABI or the ABI cannot be parsed
+func FromContractClass(class *rpc.ContractClass) (*ABI, error) {
+	if class.ABI == "" {
+		return nil, fmt.Errorf("contract class has no abi")
+	}
+	return Parse([]byte(class.ABI))
+}
+
+// Function returns the function with the given name.
+func (a *ABI) Function(name string) (*Function, bool) {
+	for i := range a.Functions {
+		if a.Functions[i].Name == name {
+			return &a.Functions[i], true
+		}
+	}
+	if a.Constructor != nil && a.Constructor.Name == name {
+		return a.Constructor, true
+	}
+	for i := range a.L1Handlers {
+		if a.L1Handlers[i].Name == name {
+			return &a.L1Handlers[i], true
+		}
+	}
+	return nil, false
+}
+
+// Struct returns the struct declared with the given fully qualified name.
+func (a *ABI) Struct(name string) (*Struct, bool) {
+	i, ok := a.structs[normalizeType(name)]
+	if !ok {
+		return nil, false
+	}
+	return &a.Structs[i], true
+}
+
+// Enum returns the enum declared with the given fully qualified name.
+func (a *ABI) Enum(name string) (*Enum, bool) {
+	i, ok := a.enums[normalizeType(name)]
+	if !ok {
+		return nil, false
+	}
+	return &a.Enums[i], true
+}
+
+// Event returns the event declared with the given fully qualified name.
+func (a *ABI) Event(name string) (*Event, bool) {
+	i, ok := a.events[normalizeType(name)]
+	if !ok {
+		return nil, false
+	}
+	return &a.Events[i], true
+}
+
+// normalizeType returns the canonical spelling of a type expression, so that
+// lookups do not depend on the spacing used by the compiler that emitted the ABI.
+func normalizeType(name string) string {
+	t, err := ParseType(name)
+	if err != nil {
+		return name
+	}
+	t.Snapshot = false
+	return t.String()
+}
@@ -0,0 +1,223 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+)
+
+// TestParse tests that every kind of entry of a Cairo 2 ABI is parsed and indexed.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestParse(t *testing.T) {
+	content, err := os.ReadFile("./tests/example_abi.json")
+	require.NoError(t, err)
+
+	a, err := Parse(content)
+	require.NoError(t, err)
+
+	require.Len(t, a.Impls, 1)
+	require.Len(t, a.Interfaces, 1)
+	require.Len(t, a.Functions, 5)
+	require.NotNil(t, a.Constructor)
+	require.Len(t, a.Constructor.Inputs, 2)
+
+	fn, ok := a.Function("get_order")
+	require.True(t, ok)
+	require.True(t, fn.IsView())
+	fn, ok = a.Function("place_order")
+	require.True(t, ok)
+	require.False(t, fn.IsView())
+
+	order, ok := a.Struct("example::example::Order")
+	require.True(t, ok)
+	require.Len(t, order.Members, 9)
+
+	// lookups do not depend on the spacing of generic arguments
+	_, ok = a.Struct("core::array::Span::<example::example::Point>")
+	require.True(t, ok)
+	_, ok = a.Enum("core::option::Option::<core::integer::u8>")
+	require.True(t, ok)
+
+	ev, ok := a.Event("example::example::Example::Event")
+	require.True(t, ok)
+	require.Equal(t, EventKindEnum, ev.Kind)
+	ev, ok = a.Event("example::example::Example::OrderPlaced")
+	require.True(t, ok)
+	require.Equal(t, EventFieldKindKey, ev.Members[0].Kind)
+
+	_, err = Parse([]byte(`[{"type": "unknown"}]`))
+	require.Error(t, err)
+}
+
+// TestFromContractClass tests parsing the ABI string of a Sierra contract class.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestFromContractClass(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+
+	a, err := FromContractClass(&class)
+	require.NoError(t, err)
+
+	fn, ok := a.Function("increase_balance")
+	require.True(t, ok)
+	require.Equal(t, StateMutabilityExternal, fn.StateMutability)
+	fn, ok = a.Function("get_balance")
+	require.True(t, ok)
+	require.True(t, fn.IsView())
+	require.Equal(t, TypeFelt252, fn.Outputs[0].Type)
+}
+
+// TestParseType tests parsing Cairo type expressions and printing them back.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestParseType(t *testing.T) {
+	type testSetType struct {
+		Type     string
+		Expected string
+		Name     string
+		Params   int
+		Tuple    bool
+	}
+	testSet := []testSetType{
+		{Type: "core::felt252", Expected: "core::felt252", Name: TypeFelt252},
+		{Type: "core::array::Span::<core::felt252>", Expected: "core::array::Span::<core::felt252>", Name: TypeSpan, Params: 1},
+		{Type: "@core::array::Array::<core::integer::u8>", Expected: "@core::array::Array::<core::integer::u8>", Name: TypeArray, Params: 1},
+		{Type: "(core::integer::u256,core::bool)", Expected: "(core::integer::u256, core::bool)", Params: 2, Tuple: true},
+		{Type: "()", Expected: "()", Tuple: true},
+		{
+			Type:     "core::array::Array::<(core::felt252, core::option::Option::<core::integer::u8>)>",
+			Expected: "core::array::Array::<(core::felt252, core::option::Option::<core::integer::u8>)>",
+			Name:     TypeArray,
+			Params:   1,
+		},
+	}
+	for _, test := range testSet {
+		typ, err := ParseType(test.Type)
+		require.NoError(t, err, test.Type)
+		require.Equal(t, test.Expected, typ.String())
+		require.Equal(t, test.Name, typ.Name)
+		require.Len(t, typ.Params, test.Params)
+		require.Equal(t, test.Tuple, typ.Tuple)
+	}
+
+	for _, invalid := range []string{"", "core::array::Array::<core::felt252", "(core::felt252", "a b"} {
+		_, err := ParseType(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+// TestSerde tests that the Encoder output matches the Cairo Serde layout and is read back by the Decoder.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSerde(t *testing.T) {
+	u256, ok := new(big.Int).SetString("340282366920938463463374607431768211457", 10) // 2^128 + 1
+	require.True(t, ok)
+
+	enc := NewEncoder()
+	enc.Felt(utils.TestHexToFelt(t, "0x1234"))
+	enc.Bool(true)
+	enc.Uint(42)
+	enc.Int(-1)
+	enc.BigInt(big.NewInt(-5), 128)
+	enc.U256(u256)
+	enc.ByteArray("Long string, more than 31 characters.")
+	require.NoError(t, enc.Err())
+
+	expected := utils.TestHexArrToFelt(t, []string{
+		"0x1234",
+		"0x1",
+		"0x2a",
+		"0x800000000000011000000000000000000000000000000000000000000000000",
+		"0x800000000000010fffffffffffffffffffffffffffffffffffffffffffffffc",
+		"0x1",
+		"0x1",
+		"0x1",
+		"0x4c6f6e6720737472696e672c206d6f7265207468616e203331206368617261",
+		"0x63746572732e",
+		"0x6",
+	})
+	require.Equal(t, expected, enc.Data())
+
+	dec := NewDecoder(enc.Data())
+	f, err := dec.Felt()
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", f.String())
+	b, err := dec.Bool()
+	require.NoError(t, err)
+	require.True(t, b)
+	u, err := dec.Uint(8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), u)
+	i, err := dec.Int(64)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), i)
+	bi, err := dec.BigInt(128)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(-5), bi)
+	bu, err := dec.U256()
+	require.NoError(t, err)
+	require.Equal(t, u256, bu)
+	s, err := dec.ByteArray()
+	require.NoError(t, err)
+	require.Equal(t, "Long string, more than 31 characters.", s)
+	require.Equal(t, 0, dec.Remaining())
+
+	_, err = dec.Felt()
+	require.True(t, errors.Is(err, ErrUnexpectedEnd))
+}
+
+// TestSerdeOutOfRange tests that values which do not fit their Cairo type are rejected.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSerdeOutOfRange(t *testing.T) {
+	enc := NewEncoder()
+	enc.BigUint(new(big.Int).Lsh(big.NewInt(1), 128), 128)
+	require.True(t, errors.Is(enc.Err(), ErrOutOfRange))
+	// the first error is kept
+	enc.Felt(nil)
+	require.True(t, errors.Is(enc.Err(), ErrOutOfRange))
+	require.Empty(t, enc.Data())
+
+	enc = NewEncoder()
+	enc.BigInt(big.NewInt(128), 8)
+	require.True(t, errors.Is(enc.Err(), ErrOutOfRange))
+
+	dec := NewDecoder(utils.TestHexArrToFelt(t, []string{"0x100"}))
+	_, err := dec.Uint(8)
+	require.True(t, errors.Is(err, ErrOutOfRange))
+
+	dec = NewDecoder(utils.TestHexArrToFelt(t, []string{"0x5", "0x1"}))
+	_, err = dec.Len()
+	require.True(t, errors.Is(err, ErrUnexpectedEnd))
+}
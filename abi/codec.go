@@ -0,0 +1,1142 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrUnknownFunction = errors.New("unknown function")
+	ErrUnknownType     = errors.New("unknown type")
+	ErrUnknownEvent    = errors.New("unknown event")
+	ErrInvalidValue    = errors.New("invalid value")
+	ErrTrailingData    = errors.New("trailing data")
+)
+
+// Marshaler is implemented by Go types that encode themselves following the
+// Cairo Serde layout, such as the types generated by abigen.
+type Marshaler interface {
+	MarshalCairo(enc *Encoder)
+}
+
+// Unmarshaler is implemented by Go types that decode themselves following the
+// Cairo Serde layout, such as the types generated by abigen.
+type Unmarshaler interface {
+	UnmarshalCairo(dec *Decoder) error
+}
+
+// FieldError is returned when a value cannot be encoded or decoded. Field is
+// the path of the offending value, e.g. `order.path[1].x`.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodedEvent is an event decoded with the contract ABI.
+type DecodedEvent struct {
+	// Name is the fully qualified name of the event struct, e.g. `example::Example::Transfer`
+	Name string
+	// Fields holds the decoded members of the event, keys and data alike
+	Fields map[string]interface{}
+}
+
+var (
+	feltType    = reflect.TypeOf(felt.Felt{})
+	feltPtrType = reflect.TypeOf(&felt.Felt{})
+	bigType     = reflect.TypeOf(big.Int{})
+	bigPtrType  = reflect.TypeOf(&big.Int{})
+	bytesType   = reflect.TypeOf([]byte{})
+
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// EncodeCalldata encodes the arguments of a function call.
+//
+// Values are matched to the Cairo types as follows:
+//   - felt252, ContractAddress, ClassHash...: *felt.Felt, felt.Felt, *big.Int, Go integers or numeric strings
+//   - bool: bool
+//   - integers and u256: Go integers, *big.Int, *felt.Felt or numeric strings
+//   - ByteArray: string or []byte
+//   - Array and Span: slices and arrays
+//   - Option: nil for None, any other value for Some
+//   - tuples: slices, arrays or Go structs whose fields are in the tuple order
+//   - structs: Go structs (fields matched by `cairo` tag or by name) or map[string]interface{}
+//   - enums: the variant name or index for unit variants, map[string]interface{}{variant: value},
+//     or Go structs with one field per variant, the set one being encoded
+//
+// Any value implementing Marshaler is encoded with its MarshalCairo method.
+//
+// Parameters:
+// - function: the name of the function
+// - args: the function arguments, in the ABI order
+// Returns:
+// - []*felt.Felt: the encoded calldata
+// - error: a *FieldError naming the offending argument if a value cannot be encoded
+func (a *ABI) EncodeCalldata(function string, args ...interface{}) ([]*felt.Felt, error) {
+	fn, ok := a.Function(function)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownFunction, function)
+	}
+	if len(args) != len(fn.Inputs) {
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", function, len(fn.Inputs), len(args))
+	}
+	enc := NewEncoder()
+	for i, in := range fn.Inputs {
+		t, err := ParseType(in.Type)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.encode(enc, t, args[i], in.Name); err != nil {
+			return nil, err
+		}
+	}
+	return enc.Data(), nil
+}
+
+// FunctionCall builds the call of a function of the contract deployed at contractAddress.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - function: the name of the function
+// - args: the function arguments, see EncodeCalldata
+// Returns:
+// - rpc.FunctionCall: the call, usable with Call or an account invoke
+// - error: an error if the arguments cannot be encoded
+func (a *ABI) FunctionCall(contractAddress *felt.Felt, function string, args ...interface{}) (rpc.FunctionCall, error) {
+	calldata, err := a.EncodeCalldata(function, args...)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    contractAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           calldata,
+	}, nil
+}
+
+// DecodeOutputs decodes the result of a function call into generic Go values.
+//
+// Cairo values are decoded to:
+//   - felt252, ContractAddress, ClassHash...: *felt.Felt
+//   - bool: bool
+//   - u8 to u64: uint64, i8 to i64: int64, u128, i128 and u256: *big.Int
+//   - ByteArray: string
+//   - Array, Span and tuples: []interface{}
+//   - Option: nil for None, the value for Some
+//   - structs: map[string]interface{}
+//   - enums: map[string]interface{} holding the variant name and its value (nil for unit variants)
+//
+// Parameters:
+// - function: the name of the function
+// - data: the felts returned by Call
+// Returns:
+// - []interface{}: one value per function output
+// - error: a *FieldError if the data does not match the outputs
+func (a *ABI) DecodeOutputs(function string, data []*felt.Felt) ([]interface{}, error) {
+	fn, ok := a.Function(function)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownFunction, function)
+	}
+	dec := NewDecoder(data)
+	out := make([]interface{}, len(fn.Outputs))
+	for i, o := range fn.Outputs {
+		t, err := ParseType(o.Type)
+		if err != nil {
+			return nil, err
+		}
+		if out[i], err = a.decodeGeneric(dec, t, fmt.Sprintf("output[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+	if dec.Remaining() != 0 {
+		return nil, fmt.Errorf("%s: %w: %d felts", function, ErrTrailingData, dec.Remaining())
+	}
+	return out, nil
+}
+
+// DecodeOutputsInto decodes the result of a function call into the given Go values.
+//
+// Parameters:
+// - function: the name of the function
+// - data: the felts returned by Call
+// - out: one non-nil pointer per function output, see Decode
+// Returns:
+// - error: a *FieldError if the data does not match the outputs or the Go values
+func (a *ABI) DecodeOutputsInto(function string, data []*felt.Felt, out ...interface{}) error {
+	fn, ok := a.Function(function)
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownFunction, function)
+	}
+	if len(out) != len(fn.Outputs) {
+		return fmt.Errorf("%s has %d outputs, got %d values", function, len(fn.Outputs), len(out))
+	}
+	dec := NewDecoder(data)
+	for i, o := range fn.Outputs {
+		t, err := ParseType(o.Type)
+		if err != nil {
+			return err
+		}
+		if err := a.decodeTo(dec, t, out[i], fmt.Sprintf("output[%d]", i)); err != nil {
+			return err
+		}
+	}
+	if dec.Remaining() != 0 {
+		return fmt.Errorf("%s: %w: %d felts", function, ErrTrailingData, dec.Remaining())
+	}
+	return nil
+}
+
+// Encode encodes a single value of the given Cairo type, see EncodeCalldata.
+//
+// Parameters:
+// - typ: the Cairo type, e.g. `core::array::Array::<core::integer::u256>`
+// - v: the Go value
+// Returns:
+// - []*felt.Felt: the encoded felts
+// - error: a *FieldError if the value cannot be encoded
+func (a *ABI) Encode(typ string, v interface{}) ([]*felt.Felt, error) {
+	t, err := ParseType(typ)
+	if err != nil {
+		return nil, err
+	}
+	enc := NewEncoder()
+	if err := a.encode(enc, t, v, "value"); err != nil {
+		return nil, err
+	}
+	return enc.Data(), nil
+}
+
+// Decode decodes a single value of the given Cairo type into out, which must be
+// a non-nil pointer. Besides the generic values listed in DecodeOutputs, out may
+// point to Go integers, *big.Int, felt.Felt, strings, slices, Go structs matching
+// Cairo structs, integers or strings for unit-only enums, Go structs with one
+// pointer field per variant for enums, or any Unmarshaler.
+//
+// Parameters:
+// - typ: the Cairo type
+// - data: the encoded felts
+// - out: a pointer to the destination
+// Returns:
+// - error: a *FieldError if the data does not match the type or the destination
+func (a *ABI) Decode(typ string, data []*felt.Felt, out interface{}) error {
+	t, err := ParseType(typ)
+	if err != nil {
+		return err
+	}
+	dec := NewDecoder(data)
+	if err := a.decodeTo(dec, t, out, "value"); err != nil {
+		return err
+	}
+	if dec.Remaining() != 0 {
+		return fmt.Errorf("%w: %d felts", ErrTrailingData, dec.Remaining())
+	}
+	return nil
+}
+
+// DecodeEvent decodes an event emitted by the contract. The event is identified
+// by its first key, the selector of its name, following the nested and flat
+// variants of the contract event enum.
+//
+// Parameters:
+// - event: the emitted event
+// Returns:
+// - *DecodedEvent: the event name and decoded members
+// - error: an error if the event is unknown or its keys and data do not match the ABI
+func (a *ABI) DecodeEvent(event rpc.Event) (*DecodedEvent, error) {
+	if len(event.Keys) == 0 {
+		return nil, fmt.Errorf("%w: event has no keys", ErrUnknownEvent)
+	}
+
+	// root events are the enums that are not a variant of another event
+	nested := map[string]bool{}
+	for _, ev := range a.Events {
+		for _, v := range ev.Variants {
+			nested[normalizeType(v.Type)] = true
+		}
+	}
+	for i := range a.Events {
+		ev := &a.Events[i]
+		if ev.Kind != EventKindEnum || nested[normalizeType(ev.Name)] {
+			continue
+		}
+		st, keys, ok := a.matchEventVariant(ev, event.Keys)
+		if ok {
+			return a.decodeEventStruct(st, keys, event.Data)
+		}
+	}
+
+	// contracts without an event enum identify the events by their short name
+	for i := range a.Events {
+		ev := &a.Events[i]
+		if ev.Kind != EventKindStruct {
+			continue
+		}
+		name := ev.Name[strings.LastIndex(ev.Name, ":")+1:]
+		if utils.GetSelectorFromNameFelt(name).Equal(event.Keys[0]) {
+			return a.decodeEventStruct(ev, event.Keys[1:], event.Data)
+		}
+	}
+	return nil, fmt.Errorf("%w with key %s", ErrUnknownEvent, event.Keys[0])
+}
+
+// matchEventVariant finds the struct event selected by keys in an enum event.
+// It returns the event and the keys following its selectors.
+func (a *ABI) matchEventVariant(ev *Event, keys []*felt.Felt) (*Event, []*felt.Felt, bool) {
+	for _, v := range ev.Variants {
+		inner, ok := a.Event(v.Type)
+		if !ok {
+			continue
+		}
+		rest := keys
+		if v.Kind != EventFieldKindFlat {
+			if len(keys) == 0 || !utils.GetSelectorFromNameFelt(v.Name).Equal(keys[0]) {
+				continue
+			}
+			rest = keys[1:]
+		}
+		if inner.Kind == EventKindStruct {
+			if v.Kind == EventFieldKindFlat {
+				// a flat struct is selected by its own name
+				name := inner.Name[strings.LastIndex(inner.Name, ":")+1:]
+				if len(keys) == 0 || !utils.GetSelectorFromNameFelt(name).Equal(keys[0]) {
+					continue
+				}
+				rest = keys[1:]
+			}
+			return inner, rest, true
+		}
+		if st, rest, ok := a.matchEventVariant(inner, rest); ok {
+			return st, rest, true
+		}
+	}
+	return nil, nil, false
+}
+
+func (a *ABI) decodeEventStruct(ev *Event, keys, data []*felt.Felt) (*DecodedEvent, error) {
+	keyDec, dataDec := NewDecoder(keys), NewDecoder(data)
+	fields := make(map[string]interface{}, len(ev.Members))
+	for _, m := range ev.Members {
+		t, err := ParseType(m.Type)
+		if err != nil {
+			return nil, err
+		}
+		dec := dataDec
+		if m.Kind == EventFieldKindKey {
+			dec = keyDec
+		}
+		if fields[m.Name], err = a.decodeGeneric(dec, t, m.Name); err != nil {
+			return nil, fmt.Errorf("event %s: %w", ev.Name, err)
+		}
+	}
+	if keyDec.Remaining() != 0 || dataDec.Remaining() != 0 {
+		return nil, fmt.Errorf("event %s: %w", ev.Name, ErrTrailingData)
+	}
+	return &DecodedEvent{Name: ev.Name, Fields: fields}, nil
+}
+
+// isFeltType returns true for the types encoded as a single felt252.
+func isFeltType(name string) bool {
+	switch name {
+	case TypeFelt252, TypeContractAddress, TypeClassHash, TypeEthAddress, TypeStorageAddress, TypeBytes31:
+		return true
+	}
+	return false
+}
+
+// intBits returns the size and signedness of the integer types up to 128 bits.
+func intBits(name string) (uint, bool, bool) {
+	switch name {
+	case TypeU8:
+		return 8, false, true
+	case TypeU16:
+		return 16, false, true
+	case TypeU32:
+		return 32, false, true
+	case TypeU64:
+		return 64, false, true
+	case TypeU128:
+		return 128, false, true
+	case TypeI8:
+		return 8, true, true
+	case TypeI16:
+		return 16, true, true
+	case TypeI32:
+		return 32, true, true
+	case TypeI64:
+		return 64, true, true
+	case TypeI128:
+		return 128, true, true
+	}
+	return 0, false, false
+}
+
+func fieldErr(path string, err error) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return err
+	}
+	return &FieldError{Field: path, Err: err}
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		if v.Type() == feltPtrType || v.Type() == bigPtrType {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// toBigInt converts a Go number, felt or numeric string to a big.Int.
+func toBigInt(v interface{}) (*big.Int, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("%w: nil", ErrInvalidValue)
+	}
+	switch rv.Type() {
+	case feltPtrType:
+		return utils.FeltToBigInt(rv.Interface().(*felt.Felt)), nil
+	case feltType:
+		f := rv.Interface().(felt.Felt)
+		return utils.FeltToBigInt(&f), nil
+	case bigPtrType:
+		return new(big.Int).Set(rv.Interface().(*big.Int)), nil
+	case bigType:
+		b := rv.Interface().(big.Int)
+		return new(big.Int).Set(&b), nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	case reflect.String:
+		b, ok := new(big.Int).SetString(rv.String(), 0)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidValue, rv.String())
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("%w: cannot use %s as a number", ErrInvalidValue, rv.Type())
+}
+
+// toFelt converts a Go value to a felt, checking that it is in the field.
+func toFelt(v interface{}) (*felt.Felt, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if rv.IsValid() && rv.Type() == feltPtrType {
+		return rv.Interface().(*felt.Felt), nil
+	}
+	b, err := toBigInt(v)
+	if err != nil {
+		return nil, err
+	}
+	if b.Sign() < 0 || b.Cmp(fieldPrime) >= 0 {
+		return nil, fmt.Errorf("felt252 %v: %w", b, ErrOutOfRange)
+	}
+	return utils.BigIntToFelt(b), nil
+}
+
+func (a *ABI) encode(enc *Encoder, t *TypeRef, v interface{}, path string) error {
+	if m, ok := v.(Marshaler); ok && !isNil(v) {
+		m.MarshalCairo(enc)
+		if err := enc.Err(); err != nil {
+			return fieldErr(path, err)
+		}
+		return nil
+	}
+
+	if t.Tuple {
+		return a.encodeTuple(enc, t, v, path)
+	}
+
+	// the encoder methods keep their error, which is reported with the path below
+	switch {
+	case isFeltType(t.Name):
+		f, err := toFelt(v)
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		enc.Felt(f)
+	case t.Name == TypeBool:
+		rv := indirect(reflect.ValueOf(v))
+		if !rv.IsValid() || rv.Kind() != reflect.Bool {
+			return fieldErr(path, fmt.Errorf("%w: expected a bool, got %T", ErrInvalidValue, v))
+		}
+		enc.Bool(rv.Bool())
+	case t.Name == TypeU256:
+		b, err := toBigInt(v)
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		enc.U256(b)
+	case t.Name == TypeByteArray:
+		rv := indirect(reflect.ValueOf(v))
+		switch {
+		case rv.IsValid() && rv.Kind() == reflect.String:
+			enc.ByteArray(rv.String())
+		case rv.IsValid() && rv.Type() == bytesType:
+			enc.ByteArray(string(rv.Bytes()))
+		default:
+			return fieldErr(path, fmt.Errorf("%w: expected a string, got %T", ErrInvalidValue, v))
+		}
+	case t.Name == TypeArray || t.Name == TypeSpan:
+		rv := indirect(reflect.ValueOf(v))
+		if !rv.IsValid() {
+			enc.Len(0)
+			break
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fieldErr(path, fmt.Errorf("%w: expected a slice, got %T", ErrInvalidValue, v))
+		}
+		enc.Len(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if err := a.encode(enc, t.Params[0], rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case t.Name == TypeOption:
+		if isNil(v) {
+			enc.Uint(1)
+			break
+		}
+		enc.Uint(0)
+		return a.encode(enc, t.Params[0], v, path)
+	case t.Name == TypeNonZero:
+		return a.encode(enc, t.Params[0], v, path)
+	default:
+		if bits, signed, ok := intBits(t.Name); ok {
+			b, err := toBigInt(v)
+			if err != nil {
+				return fieldErr(path, err)
+			}
+			if signed {
+				enc.BigInt(b, bits)
+			} else {
+				enc.BigUint(b, bits)
+			}
+			break
+		}
+		if s, ok := a.Struct(t.String()); ok {
+			return a.encodeStruct(enc, s, v, path)
+		}
+		if e, ok := a.Enum(t.String()); ok {
+			return a.encodeEnum(enc, e, v, path)
+		}
+		return fieldErr(path, fmt.Errorf("%w %s", ErrUnknownType, t))
+	}
+	if err := enc.Err(); err != nil {
+		return fieldErr(path, err)
+	}
+	return nil
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func (a *ABI) encodeTuple(enc *Encoder, t *TypeRef, v interface{}, path string) error {
+	rv := indirect(reflect.ValueOf(v))
+	if t.IsUnit() {
+		return nil
+	}
+	if !rv.IsValid() {
+		return fieldErr(path, fmt.Errorf("%w: nil tuple", ErrInvalidValue))
+	}
+	var elems []interface{}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).IsExported() {
+				elems = append(elems, rv.Field(i).Interface())
+			}
+		}
+	default:
+		return fieldErr(path, fmt.Errorf("%w: expected a tuple, got %T", ErrInvalidValue, v))
+	}
+	if len(elems) != len(t.Params) {
+		return fieldErr(path, fmt.Errorf("%w: expected %d tuple elements, got %d", ErrInvalidValue, len(t.Params), len(elems)))
+	}
+	for i, p := range t.Params {
+		if err := a.encode(enc, p, elems[i], fmt.Sprintf("%s.%d", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structField returns the field of a Go struct holding the Cairo member name,
+// either tagged `cairo:"name"` or with the same name once underscores are removed.
+func structField(rv reflect.Value, name string) (reflect.Value, bool) {
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if tag, ok := typ.Field(i).Tag.Lookup("cairo"); ok && tag == name {
+			return rv.Field(i), true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if _, tagged := f.Tag.Lookup("cairo"); tagged || !f.IsExported() {
+			continue
+		}
+		if strings.EqualFold(f.Name, strings.ReplaceAll(name, "_", "")) {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func (a *ABI) encodeStruct(enc *Encoder, s *Struct, v interface{}, path string) error {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return fieldErr(path, fmt.Errorf("%w: nil struct %s", ErrInvalidValue, s.Name))
+	}
+	for _, m := range s.Members {
+		mt, err := ParseType(m.Type)
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		switch {
+		case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+			mv := rv.MapIndex(reflect.ValueOf(m.Name).Convert(rv.Type().Key()))
+			if !mv.IsValid() {
+				return fieldErr(path+"."+m.Name, fmt.Errorf("%w: missing member", ErrInvalidValue))
+			}
+			value = mv.Interface()
+		case rv.Kind() == reflect.Struct:
+			fv, ok := structField(rv, m.Name)
+			if !ok {
+				return fieldErr(path+"."+m.Name, fmt.Errorf("%w: %s has no matching field", ErrInvalidValue, rv.Type()))
+			}
+			value = fv.Interface()
+		default:
+			return fieldErr(path, fmt.Errorf("%w: expected a struct or a map for %s, got %T", ErrInvalidValue, s.Name, v))
+		}
+		if err := a.encode(enc, mt, value, path+"."+m.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ABI) encodeEnum(enc *Encoder, e *Enum, v interface{}, path string) error {
+	variant, value, err := enumVariant(e, v)
+	if err != nil {
+		return fieldErr(path, err)
+	}
+	vt, err := ParseType(e.Variants[variant].Type)
+	if err != nil {
+		return err
+	}
+	if !vt.IsUnit() && value == nil && vt.Name != TypeOption {
+		return fieldErr(path, fmt.Errorf("%w: variant %s needs a value", ErrInvalidValue, e.Variants[variant].Name))
+	}
+	enc.Uint(uint64(variant))
+	return a.encode(enc, vt, value, path+"."+e.Variants[variant].Name)
+}
+
+// enumVariant returns the index and the value of the variant selected by v.
+func enumVariant(e *Enum, v interface{}) (int, interface{}, error) {
+	byName := func(name string) (int, error) {
+		for i, variant := range e.Variants {
+			if variant.Name == name {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: %s has no variant %s", ErrInvalidValue, e.Name, name)
+	}
+
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return 0, nil, fmt.Errorf("%w: nil enum %s", ErrInvalidValue, e.Name)
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		i, err := byName(rv.String())
+		return i, nil, err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b, _ := toBigInt(rv.Interface())
+		if b.Sign() < 0 || b.Cmp(big.NewInt(int64(len(e.Variants)))) >= 0 {
+			return 0, nil, fmt.Errorf("%w: %s has no variant %v", ErrInvalidValue, e.Name, b)
+		}
+		return int(b.Int64()), nil, nil
+	case reflect.Map:
+		if rv.Len() != 1 || rv.Type().Key().Kind() != reflect.String {
+			return 0, nil, fmt.Errorf("%w: expected a single variant for %s", ErrInvalidValue, e.Name)
+		}
+		key := rv.MapKeys()[0]
+		i, err := byName(key.String())
+		return i, rv.MapIndex(key).Interface(), err
+	case reflect.Struct:
+		selected := -1
+		var value interface{}
+		for i, variant := range e.Variants {
+			fv, ok := structField(rv, variant.Name)
+			if !ok || fv.IsZero() {
+				continue
+			}
+			if selected >= 0 {
+				return 0, nil, fmt.Errorf("%w: several variants of %s are set", ErrInvalidValue, e.Name)
+			}
+			selected = i
+			if fv.Kind() != reflect.Bool {
+				value = fv.Interface()
+			}
+		}
+		if selected < 0 {
+			return 0, nil, fmt.Errorf("%w: no variant of %s is set", ErrInvalidValue, e.Name)
+		}
+		return selected, value, nil
+	}
+	return 0, nil, fmt.Errorf("%w: cannot use %T as enum %s", ErrInvalidValue, v, e.Name)
+}
+
+func (a *ABI) decodeGeneric(dec *Decoder, t *TypeRef, path string) (interface{}, error) {
+	if t.Tuple {
+		out := make([]interface{}, len(t.Params))
+		for i, p := range t.Params {
+			v, err := a.decodeGeneric(dec, p, fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+
+	var (
+		v   interface{}
+		err error
+	)
+	switch {
+	case isFeltType(t.Name):
+		v, err = dec.Felt()
+	case t.Name == TypeBool:
+		v, err = dec.Bool()
+	case t.Name == TypeU256:
+		v, err = dec.U256()
+	case t.Name == TypeByteArray:
+		v, err = dec.ByteArray()
+	case t.Name == TypeArray || t.Name == TypeSpan:
+		var n int
+		if n, err = dec.Len(); err != nil {
+			break
+		}
+		out := make([]interface{}, n)
+		for i := range out {
+			if out[i], err = a.decodeGeneric(dec, t.Params[0], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case t.Name == TypeOption:
+		var variant uint64
+		if variant, err = dec.Uint(1); err != nil {
+			break
+		}
+		if variant == 1 {
+			return nil, nil
+		}
+		return a.decodeGeneric(dec, t.Params[0], path)
+	case t.Name == TypeNonZero:
+		return a.decodeGeneric(dec, t.Params[0], path)
+	default:
+		if bits, signed, ok := intBits(t.Name); ok {
+			switch {
+			case bits == 128 && signed:
+				v, err = dec.BigInt(bits)
+			case bits == 128:
+				v, err = dec.BigUint(bits)
+			case signed:
+				v, err = dec.Int(bits)
+			default:
+				v, err = dec.Uint(bits)
+			}
+			break
+		}
+		if s, ok := a.Struct(t.String()); ok {
+			out := make(map[string]interface{}, len(s.Members))
+			for _, m := range s.Members {
+				mt, err := ParseType(m.Type)
+				if err != nil {
+					return nil, err
+				}
+				if out[m.Name], err = a.decodeGeneric(dec, mt, path+"."+m.Name); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+		if e, ok := a.Enum(t.String()); ok {
+			variant, vt, err := decodeVariant(dec, e)
+			if err != nil {
+				return nil, fieldErr(path, err)
+			}
+			value, err := a.decodeGeneric(dec, vt, path+"."+e.Variants[variant].Name)
+			if err != nil {
+				return nil, err
+			}
+			if vt.IsUnit() {
+				value = nil
+			}
+			return map[string]interface{}{e.Variants[variant].Name: value}, nil
+		}
+		err = fmt.Errorf("%w %s", ErrUnknownType, t)
+	}
+	if err != nil {
+		return nil, fieldErr(path, err)
+	}
+	return v, nil
+}
+
+// decodeVariant reads the variant index of an enum and returns it with the variant type.
+func decodeVariant(dec *Decoder, e *Enum) (int, *TypeRef, error) {
+	variant, err := dec.Uint(32)
+	if err != nil {
+		return 0, nil, err
+	}
+	if variant >= uint64(len(e.Variants)) {
+		return 0, nil, fmt.Errorf("%w: %s has no variant %d", ErrOutOfRange, e.Name, variant)
+	}
+	vt, err := ParseType(e.Variants[variant].Type)
+	if err != nil {
+		return 0, nil, err
+	}
+	return int(variant), vt, nil
+}
+
+func (a *ABI) decodeTo(dec *Decoder, t *TypeRef, out interface{}, path string) error {
+	rv := reflect.ValueOf(out)
+	if !rv.IsValid() || rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fieldErr(path, fmt.Errorf("%w: expected a non-nil pointer, got %T", ErrInvalidValue, out))
+	}
+	return a.decodeValue(dec, t, rv.Elem(), path)
+}
+
+// decodeValue decodes a value of type t into the settable rv.
+func (a *ABI) decodeValue(dec *Decoder, t *TypeRef, rv reflect.Value, path string) error {
+	if rv.Kind() == reflect.Ptr && rv.Type().Implements(unmarshalerType) {
+		elem := reflect.New(rv.Type().Elem())
+		if err := elem.Interface().(Unmarshaler).UnmarshalCairo(dec); err != nil {
+			return fieldErr(path, err)
+		}
+		rv.Set(elem)
+		return nil
+	}
+	if rv.CanAddr() {
+		if u, ok := rv.Addr().Interface().(Unmarshaler); ok {
+			if err := u.UnmarshalCairo(dec); err != nil {
+				return fieldErr(path, err)
+			}
+			return nil
+		}
+	}
+	if rv.Kind() == reflect.Interface {
+		v, err := a.decodeGeneric(dec, t, path)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			rv.Set(reflect.ValueOf(v))
+		}
+		return nil
+	}
+
+	if t.Tuple {
+		return a.decodeTuple(dec, t, rv, path)
+	}
+
+	switch {
+	case isFeltType(t.Name):
+		f, err := dec.Felt()
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		return setNumber(rv, utils.FeltToBigInt(f), f, path)
+	case t.Name == TypeBool:
+		b, err := dec.Bool()
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		if rv.Kind() != reflect.Bool {
+			return fieldErr(path, fmt.Errorf("%w: cannot decode a bool into %s", ErrInvalidValue, rv.Type()))
+		}
+		rv.SetBool(b)
+		return nil
+	case t.Name == TypeU256:
+		b, err := dec.U256()
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		return setNumber(rv, b, nil, path)
+	case t.Name == TypeByteArray:
+		s, err := dec.ByteArray()
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		switch {
+		case rv.Kind() == reflect.String:
+			rv.SetString(s)
+		case rv.Type() == bytesType:
+			rv.SetBytes([]byte(s))
+		default:
+			return fieldErr(path, fmt.Errorf("%w: cannot decode a ByteArray into %s", ErrInvalidValue, rv.Type()))
+		}
+		return nil
+	case t.Name == TypeArray || t.Name == TypeSpan:
+		n, err := dec.Len()
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		switch rv.Kind() {
+		case reflect.Slice:
+			rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+		case reflect.Array:
+			if rv.Len() != n {
+				return fieldErr(path, fmt.Errorf("%w: cannot decode %d elements into %s", ErrInvalidValue, n, rv.Type()))
+			}
+		default:
+			return fieldErr(path, fmt.Errorf("%w: cannot decode an array into %s", ErrInvalidValue, rv.Type()))
+		}
+		for i := 0; i < n; i++ {
+			if err := a.decodeValue(dec, t.Params[0], rv.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case t.Name == TypeOption:
+		variant, err := dec.Uint(1)
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		if rv.Kind() != reflect.Ptr {
+			return fieldErr(path, fmt.Errorf("%w: cannot decode an Option into %s", ErrInvalidValue, rv.Type()))
+		}
+		if variant == 1 {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		if rv.Type() == feltPtrType || rv.Type() == bigPtrType {
+			return a.decodeValue(dec, t.Params[0], rv, path)
+		}
+		elem := reflect.New(rv.Type().Elem())
+		if err := a.decodeValue(dec, t.Params[0], elem.Elem(), path); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	case t.Name == TypeNonZero:
+		return a.decodeValue(dec, t.Params[0], rv, path)
+	}
+
+	if bits, signed, ok := intBits(t.Name); ok {
+		var (
+			b   *big.Int
+			err error
+		)
+		if signed {
+			b, err = dec.BigInt(bits)
+		} else {
+			b, err = dec.BigUint(bits)
+		}
+		if err != nil {
+			return fieldErr(path, err)
+		}
+		return setNumber(rv, b, nil, path)
+	}
+	if s, ok := a.Struct(t.String()); ok {
+		return a.decodeStruct(dec, s, rv, path)
+	}
+	if e, ok := a.Enum(t.String()); ok {
+		return a.decodeEnum(dec, e, rv, path)
+	}
+	return fieldErr(path, fmt.Errorf("%w %s", ErrUnknownType, t))
+}
+
+// setNumber stores b into a Go integer, big.Int, felt or string (hex) value.
+func setNumber(rv reflect.Value, b *big.Int, f *felt.Felt, path string) error {
+	if rv.Kind() == reflect.Ptr && rv.Type() != feltPtrType && rv.Type() != bigPtrType {
+		elem := reflect.New(rv.Type().Elem())
+		if err := setNumber(elem.Elem(), b, f, path); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	}
+	if f == nil {
+		f = utils.BigIntToFelt(b)
+	}
+	switch rv.Type() {
+	case feltPtrType:
+		rv.Set(reflect.ValueOf(f))
+		return nil
+	case feltType:
+		rv.Set(reflect.ValueOf(*f))
+		return nil
+	case bigPtrType:
+		rv.Set(reflect.ValueOf(b))
+		return nil
+	case bigType:
+		rv.Set(reflect.ValueOf(*b))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !b.IsInt64() || rv.OverflowInt(b.Int64()) {
+			return fieldErr(path, fmt.Errorf("%v does not fit %s: %w", b, rv.Type(), ErrOutOfRange))
+		}
+		rv.SetInt(b.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !b.IsUint64() || rv.OverflowUint(b.Uint64()) {
+			return fieldErr(path, fmt.Errorf("%v does not fit %s: %w", b, rv.Type(), ErrOutOfRange))
+		}
+		rv.SetUint(b.Uint64())
+	case reflect.String:
+		rv.SetString(f.String())
+	default:
+		return fieldErr(path, fmt.Errorf("%w: cannot decode a number into %s", ErrInvalidValue, rv.Type()))
+	}
+	return nil
+}
+
+func (a *ABI) decodeTuple(dec *Decoder, t *TypeRef, rv reflect.Value, path string) error {
+	if t.IsUnit() {
+		return nil
+	}
+	var elems []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice:
+		rv.Set(reflect.MakeSlice(rv.Type(), len(t.Params), len(t.Params)))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).IsExported() {
+				elems = append(elems, rv.Field(i))
+			}
+		}
+	default:
+		return fieldErr(path, fmt.Errorf("%w: cannot decode a tuple into %s", ErrInvalidValue, rv.Type()))
+	}
+	if len(elems) != len(t.Params) {
+		return fieldErr(path, fmt.Errorf("%w: cannot decode %d tuple elements into %s", ErrInvalidValue, len(t.Params), rv.Type()))
+	}
+	for i, p := range t.Params {
+		if err := a.decodeValue(dec, p, elems[i], fmt.Sprintf("%s.%d", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ABI) decodeStruct(dec *Decoder, s *Struct, rv reflect.Value, path string) error {
+	if rv.Kind() == reflect.Ptr {
+		elem := reflect.New(rv.Type().Elem())
+		if err := a.decodeStruct(dec, s, elem.Elem(), path); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return fieldErr(path, fmt.Errorf("%w: cannot decode %s into %s", ErrInvalidValue, s.Name, rv.Type()))
+	}
+	for _, m := range s.Members {
+		mt, err := ParseType(m.Type)
+		if err != nil {
+			return err
+		}
+		fv, ok := structField(rv, m.Name)
+		if !ok {
+			return fieldErr(path+"."+m.Name, fmt.Errorf("%w: %s has no matching field", ErrInvalidValue, rv.Type()))
+		}
+		if err := a.decodeValue(dec, mt, fv, path+"."+m.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ABI) decodeEnum(dec *Decoder, e *Enum, rv reflect.Value, path string) error {
+	variant, vt, err := decodeVariant(dec, e)
+	if err != nil {
+		return fieldErr(path, err)
+	}
+	name := e.Variants[variant].Name
+	switch rv.Kind() {
+	case reflect.String:
+		if !vt.IsUnit() {
+			return fieldErr(path, fmt.Errorf("%w: cannot decode the variant %s into a string", ErrInvalidValue, name))
+		}
+		rv.SetString(name)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !vt.IsUnit() {
+			return fieldErr(path, fmt.Errorf("%w: cannot decode the variant %s into an integer", ErrInvalidValue, name))
+		}
+		return setNumber(rv, big.NewInt(int64(variant)), nil, path)
+	case reflect.Struct:
+		rv.Set(reflect.Zero(rv.Type()))
+		fv, ok := structField(rv, name)
+		if !ok {
+			return fieldErr(path+"."+name, fmt.Errorf("%w: %s has no matching field", ErrInvalidValue, rv.Type()))
+		}
+		if fv.Kind() == reflect.Bool && vt.IsUnit() {
+			fv.SetBool(true)
+			return nil
+		}
+		return a.decodeValue(dec, vt, fv, path+"."+name)
+	case reflect.Map:
+		value, err := a.decodeGeneric(dec, vt, path+"."+name)
+		if err != nil {
+			return err
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		if value == nil {
+			rv.SetMapIndex(reflect.ValueOf(name), reflect.Zero(rv.Type().Elem()))
+		} else {
+			rv.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(value))
+		}
+		return nil
+	}
+	return fieldErr(path, fmt.Errorf("%w: cannot decode %s into %s", ErrInvalidValue, e.Name, rv.Type()))
+}
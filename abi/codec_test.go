@@ -0,0 +1,285 @@
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+)
+
+type testPoint struct {
+	X uint64
+	Y int32
+}
+
+type testShape struct {
+	Circle  *uint32
+	Polygon []testPoint
+	Empty   bool
+}
+
+type testOrder struct {
+	ID        *big.Int `cairo:"id"`
+	Owner     *felt.Felt
+	Amount    *big.Int
+	Path      []testPoint
+	Direction string
+	Shape     testShape
+	Note      string
+	Discount  *uint8
+	Active    bool
+}
+
+func loadExampleABI(t *testing.T) *ABI {
+	content, err := os.ReadFile("./tests/example_abi.json")
+	require.NoError(t, err)
+	a, err := Parse(content)
+	require.NoError(t, err)
+	return a
+}
+
+// exampleOrderFelts is the Serde encoding of the order used by the codec tests.
+func exampleOrderFelts(t *testing.T) []*felt.Felt {
+	return utils.TestHexArrToFelt(t, []string{
+		"0x7",   // id
+		"0xabc", // owner
+		"0x1",   // amount.low
+		"0x1",   // amount.high
+		"0x2",   // path length
+		"0x1",   // path[0].x
+		"0x800000000000011000000000000000000000000000000000000000000000000", // path[0].y = -1
+		"0x3", // path[1].x
+		"0x4", // path[1].y
+		"0x2", // direction = South
+		"0x0", // shape = Circle
+		"0x5", // radius
+		"0x0", // note: no full word
+		"0x6869",
+		"0x2",
+		"0x0", // discount = Some
+		"0xa",
+		"0x1", // active
+	})
+}
+
+// TestEncodeCalldata tests encoding the same struct from a Go struct and from a generic map.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEncodeCalldata(t *testing.T) {
+	a := loadExampleABI(t)
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	radius := uint32(5)
+	discount := uint8(10)
+
+	order := testOrder{
+		ID:        big.NewInt(7),
+		Owner:     utils.TestHexToFelt(t, "0xabc"),
+		Amount:    amount,
+		Path:      []testPoint{{X: 1, Y: -1}, {X: 3, Y: 4}},
+		Direction: "South",
+		Shape:     testShape{Circle: &radius},
+		Note:      "hi",
+		Discount:  &discount,
+		Active:    true,
+	}
+	calldata, err := a.EncodeCalldata("place_order", order)
+	require.NoError(t, err)
+	require.Equal(t, exampleOrderFelts(t), calldata)
+
+	generic := map[string]interface{}{
+		"id":        7,
+		"owner":     "0xabc",
+		"amount":    amount,
+		"path":      []interface{}{map[string]interface{}{"x": 1, "y": -1}, map[string]interface{}{"x": 3, "y": 4}},
+		"direction": 2,
+		"shape":     map[string]interface{}{"Circle": 5},
+		"note":      "hi",
+		"discount":  10,
+		"active":    true,
+	}
+	calldata, err = a.EncodeCalldata("place_order", generic)
+	require.NoError(t, err)
+	require.Equal(t, exampleOrderFelts(t), calldata)
+
+	call, err := a.FunctionCall(utils.TestHexToFelt(t, "0x1"), "set_name", "Long string, more than 31 characters.", 1)
+	require.NoError(t, err)
+	require.Equal(t, utils.GetSelectorFromNameFelt("set_name"), call.EntryPointSelector)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{
+		"0x1",
+		"0x4c6f6e6720737472696e672c206d6f7265207468616e203331206368617261",
+		"0x63746572732e",
+		"0x6",
+		"0x1",
+	}), call.Calldata)
+
+	calldata, err = a.Encode("(core::integer::u256, core::option::Option::<core::felt252>)", []interface{}{1, nil})
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{"0x1", "0x0", "0x1"}), calldata)
+}
+
+// TestEncodeErrors tests that encoding errors name the offending field.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEncodeErrors(t *testing.T) {
+	a := loadExampleABI(t)
+
+	type testSetType struct {
+		Order map[string]interface{}
+		Field string
+		Err   error
+	}
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"id": 1, "owner": 2, "amount": 3,
+			"path":      []interface{}{},
+			"direction": "North",
+			"shape":     map[string]interface{}{"Empty": nil},
+			"note":      "", "discount": nil, "active": false,
+		}
+	}
+	testSet := []testSetType{}
+	for field, value := range map[string]interface{}{
+		"id":        "-1",
+		"path":      []interface{}{map[string]interface{}{"x": 1, "y": 1 << 40}},
+		"direction": "Up",
+		"shape":     map[string]interface{}{"Polygon": nil},
+		"active":    1,
+	} {
+		order := valid()
+		order[field] = value
+		path := "order." + field
+		if field == "path" {
+			path = "order.path[0].y"
+		}
+		testSet = append(testSet, testSetType{Order: order, Field: path})
+	}
+	missing := valid()
+	delete(missing, "note")
+	testSet = append(testSet, testSetType{Order: missing, Field: "order.note"})
+
+	_, err := a.EncodeCalldata("place_order", valid())
+	require.NoError(t, err)
+
+	for _, test := range testSet {
+		_, err := a.EncodeCalldata("place_order", test.Order)
+		require.Error(t, err)
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr), err.Error())
+		require.Equal(t, test.Field, fieldErr.Field)
+	}
+
+	_, err = a.EncodeCalldata("unknown")
+	require.True(t, errors.Is(err, ErrUnknownFunction))
+	_, err = a.EncodeCalldata("place_order")
+	require.Error(t, err)
+}
+
+// TestDecodeOutputs tests decoding call results into generic values and into Go values.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestDecodeOutputs(t *testing.T) {
+	a := loadExampleABI(t)
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+	out, err := a.DecodeOutputs("get_order", exampleOrderFelts(t))
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	order := out[0].(map[string]interface{})
+	require.Equal(t, big.NewInt(7), order["id"])
+	require.Equal(t, "0xabc", order["owner"].(*felt.Felt).String())
+	require.Equal(t, amount, order["amount"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"x": uint64(1), "y": int64(-1)},
+		map[string]interface{}{"x": uint64(3), "y": int64(4)},
+	}, order["path"])
+	require.Equal(t, map[string]interface{}{"South": nil}, order["direction"])
+	require.Equal(t, map[string]interface{}{"Circle": uint64(5)}, order["shape"])
+	require.Equal(t, "hi", order["note"])
+	require.Equal(t, uint64(10), order["discount"])
+	require.Equal(t, true, order["active"])
+
+	var typed testOrder
+	require.NoError(t, a.DecodeOutputsInto("get_order", exampleOrderFelts(t), &typed))
+	require.Equal(t, big.NewInt(7), typed.ID)
+	require.Equal(t, amount, typed.Amount)
+	require.Equal(t, []testPoint{{X: 1, Y: -1}, {X: 3, Y: 4}}, typed.Path)
+	require.Equal(t, "South", typed.Direction)
+	require.NotNil(t, typed.Shape.Circle)
+	require.Equal(t, uint32(5), *typed.Shape.Circle)
+	require.Equal(t, "hi", typed.Note)
+	require.NotNil(t, typed.Discount)
+	require.Equal(t, uint8(10), *typed.Discount)
+	require.True(t, typed.Active)
+
+	var (
+		supply   *big.Int
+		balances struct {
+			Balance *big.Int
+			Ok      bool
+		}
+	)
+	require.NoError(t, a.DecodeOutputsInto("total_supply", utils.TestHexArrToFelt(t, []string{"0x2", "0x0"}), &supply))
+	require.Equal(t, big.NewInt(2), supply)
+	require.NoError(t, a.DecodeOutputsInto("balances", utils.TestHexArrToFelt(t, []string{"0x2", "0x0", "0x1"}), &balances))
+	require.Equal(t, big.NewInt(2), balances.Balance)
+	require.True(t, balances.Ok)
+
+	// the order is truncated in its note
+	_, err = a.DecodeOutputs("get_order", exampleOrderFelts(t)[:13])
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr))
+	require.Equal(t, "output[0].note", fieldErr.Field)
+	require.True(t, errors.Is(err, ErrUnexpectedEnd))
+
+	// a u64 cannot hold a negative y
+	var small struct{ X, Y uint64 }
+	err = a.Decode("example::example::Point", utils.TestHexArrToFelt(t, []string{"0x1", "0x800000000000011000000000000000000000000000000000000000000000000"}), &small)
+	require.True(t, errors.As(err, &fieldErr))
+	require.Equal(t, "value.y", fieldErr.Field)
+
+	_, err = a.DecodeOutputs("total_supply", utils.TestHexArrToFelt(t, []string{"0x2", "0x0", "0x0"}))
+	require.True(t, errors.Is(err, ErrTrailingData))
+}
+
+// TestDecodeEvent tests decoding an event nested in the contract event enum.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestDecodeEvent(t *testing.T) {
+	a := loadExampleABI(t)
+
+	event := rpc.Event{
+		Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("OrderPlaced"), utils.TestHexToFelt(t, "0xabc")},
+		Data: utils.TestHexArrToFelt(t, []string{"0x7", "0x5", "0x0"}),
+	}
+	decoded, err := a.DecodeEvent(event)
+	require.NoError(t, err)
+	require.Equal(t, "example::example::Example::OrderPlaced", decoded.Name)
+	require.Equal(t, "0xabc", decoded.Fields["owner"].(*felt.Felt).String())
+	require.Equal(t, big.NewInt(7), decoded.Fields["id"])
+	require.Equal(t, big.NewInt(5), decoded.Fields["amount"])
+
+	event.Keys[0] = utils.GetSelectorFromNameFelt("Unknown")
+	_, err = a.DecodeEvent(event)
+	require.True(t, errors.Is(err, ErrUnknownEvent))
+}
@@ -0,0 +1,319 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrUnexpectedEnd = errors.New("unexpected end of data")
+	ErrOutOfRange    = errors.New("value out of range")
+)
+
+const bytes31Len = 31
+
+var (
+	maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxU256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	// fieldPrime is the Starknet field prime, 2^251 + 17*2^192 + 1
+	fieldPrime, _ = new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+)
+
+// Encoder serializes Go values into felts following the Cairo Serde layout.
+// The first error encountered is kept and every following write is a no-op,
+// so that callers only have to check Err once they are done.
+type Encoder struct {
+	data []*felt.Felt
+	err  error
+}
+
+// NewEncoder returns an empty Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{data: []*felt.Felt{}}
+}
+
+// Data returns the encoded felts.
+func (e *Encoder) Data() []*felt.Felt {
+	return e.data
+}
+
+// Err returns the first error encountered while encoding, if any.
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+// SetErr records err unless an error has already been recorded.
+func (e *Encoder) SetErr(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// Felt appends a felt252 (or any felt-based type such as ContractAddress).
+func (e *Encoder) Felt(f *felt.Felt) {
+	if e.err != nil {
+		return
+	}
+	if f == nil {
+		e.err = fmt.Errorf("nil felt")
+		return
+	}
+	e.data = append(e.data, f)
+}
+
+// Felts appends the given felts as they are, without a length prefix.
+func (e *Encoder) Felts(fs []*felt.Felt) {
+	for _, f := range fs {
+		e.Felt(f)
+	}
+}
+
+// Bool appends a core::bool.
+func (e *Encoder) Bool(b bool) {
+	if b {
+		e.Uint(1)
+		return
+	}
+	e.Uint(0)
+}
+
+// Uint appends an unsigned integer of up to 64 bits.
+func (e *Encoder) Uint(v uint64) {
+	if e.err != nil {
+		return
+	}
+	e.data = append(e.data, new(felt.Felt).SetUint64(v))
+}
+
+// Int appends a signed integer of up to 64 bits. Negative values are encoded as P - |v|.
+func (e *Encoder) Int(v int64) {
+	e.BigInt(big.NewInt(v), 64)
+}
+
+// BigUint appends an unsigned integer of at most the given number of bits (up to 252).
+func (e *Encoder) BigUint(v *big.Int, bits uint) {
+	if e.err != nil {
+		return
+	}
+	if v == nil || v.Sign() < 0 || uint(v.BitLen()) > bits {
+		e.err = fmt.Errorf("u%d %v: %w", bits, v, ErrOutOfRange)
+		return
+	}
+	e.data = append(e.data, utils.BigIntToFelt(v))
+}
+
+// BigInt appends a signed integer of at most the given number of bits. Negative values are encoded as P - |v|.
+func (e *Encoder) BigInt(v *big.Int, bits uint) {
+	if e.err != nil {
+		return
+	}
+	if v == nil {
+		e.err = fmt.Errorf("i%d nil: %w", bits, ErrOutOfRange)
+		return
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	if v.Cmp(limit) >= 0 || v.Cmp(new(big.Int).Neg(limit)) < 0 {
+		e.err = fmt.Errorf("i%d %v: %w", bits, v, ErrOutOfRange)
+		return
+	}
+	if v.Sign() >= 0 {
+		e.data = append(e.data, utils.BigIntToFelt(v))
+		return
+	}
+	abs := utils.BigIntToFelt(new(big.Int).Neg(v))
+	e.data = append(e.data, new(felt.Felt).Sub(&felt.Zero, abs))
+}
+
+// U256 appends a core::integer::u256 as its low and high 128 bit limbs.
+func (e *Encoder) U256(v *big.Int) {
+	if e.err != nil {
+		return
+	}
+	if v == nil || v.Sign() < 0 || v.Cmp(maxU256) > 0 {
+		e.err = fmt.Errorf("u256 %v: %w", v, ErrOutOfRange)
+		return
+	}
+	low := new(big.Int).And(v, maxU128)
+	high := new(big.Int).Rsh(v, 128)
+	e.data = append(e.data, utils.BigIntToFelt(low), utils.BigIntToFelt(high))
+}
+
+// Len appends the length prefix of an Array or a Span.
+func (e *Encoder) Len(n int) {
+	e.Uint(uint64(n))
+}
+
+// ByteArray appends a core::byte_array::ByteArray: the full 31 bytes words,
+// the pending word and the pending word length.
+func (e *Encoder) ByteArray(s string) {
+	b := []byte(s)
+	full := len(b) / bytes31Len
+	e.Len(full)
+	for i := 0; i < full; i++ {
+		e.Felt(new(felt.Felt).SetBytes(b[i*bytes31Len : (i+1)*bytes31Len]))
+	}
+	pending := b[full*bytes31Len:]
+	e.Felt(new(felt.Felt).SetBytes(pending))
+	e.Uint(uint64(len(pending)))
+}
+
+// Decoder deserializes felts following the Cairo Serde layout.
+type Decoder struct {
+	data []*felt.Felt
+	pos  int
+}
+
+// NewDecoder returns a Decoder reading the given felts.
+func NewDecoder(data []*felt.Felt) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Remaining returns the number of felts that have not been read yet.
+func (d *Decoder) Remaining() int {
+	return len(d.data) - d.pos
+}
+
+// Pos returns the index of the next felt to be read.
+func (d *Decoder) Pos() int {
+	return d.pos
+}
+
+// Felt reads a felt252 (or any felt-based type such as ContractAddress).
+func (d *Decoder) Felt() (*felt.Felt, error) {
+	if d.pos >= len(d.data) {
+		return nil, ErrUnexpectedEnd
+	}
+	f := d.data[d.pos]
+	d.pos++
+	return f, nil
+}
+
+// Felts reads n felts.
+func (d *Decoder) Felts(n int) ([]*felt.Felt, error) {
+	if n < 0 || d.Remaining() < n {
+		return nil, ErrUnexpectedEnd
+	}
+	fs := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return fs, nil
+}
+
+// Bool reads a core::bool.
+func (d *Decoder) Bool() (bool, error) {
+	v, err := d.Uint(1)
+	if err != nil {
+		return false, err
+	}
+	return v == 1, nil
+}
+
+// Uint reads an unsigned integer of at most the given number of bits (up to 64).
+func (d *Decoder) Uint(bits uint) (uint64, error) {
+	v, err := d.BigUint(bits)
+	if err != nil {
+		return 0, err
+	}
+	return v.Uint64(), nil
+}
+
+// Int reads a signed integer of at most the given number of bits (up to 64).
+func (d *Decoder) Int(bits uint) (int64, error) {
+	v, err := d.BigInt(bits)
+	if err != nil {
+		return 0, err
+	}
+	return v.Int64(), nil
+}
+
+// BigUint reads an unsigned integer of at most the given number of bits (up to 252).
+func (d *Decoder) BigUint(bits uint) (*big.Int, error) {
+	f, err := d.Felt()
+	if err != nil {
+		return nil, err
+	}
+	v := utils.FeltToBigInt(f)
+	if uint(v.BitLen()) > bits {
+		return nil, fmt.Errorf("u%d %s: %w", bits, f, ErrOutOfRange)
+	}
+	return v, nil
+}
+
+// BigInt reads a signed integer of at most the given number of bits, mapping P - |v| back to -|v|.
+func (d *Decoder) BigInt(bits uint) (*big.Int, error) {
+	f, err := d.Felt()
+	if err != nil {
+		return nil, err
+	}
+	v := utils.FeltToBigInt(f)
+	limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	if v.Cmp(limit) < 0 {
+		return v, nil
+	}
+	neg := new(big.Int).Sub(v, fieldPrime)
+	if neg.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("i%d %s: %w", bits, f, ErrOutOfRange)
+	}
+	return neg, nil
+}
+
+// U256 reads a core::integer::u256 from its low and high 128 bit limbs.
+func (d *Decoder) U256() (*big.Int, error) {
+	low, err := d.BigUint(128)
+	if err != nil {
+		return nil, err
+	}
+	high, err := d.BigUint(128)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Or(new(big.Int).Lsh(high, 128), low), nil
+}
+
+// Len reads the length prefix of an Array or a Span.
+func (d *Decoder) Len() (int, error) {
+	n, err := d.Uint(32)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 || int(n) > d.Remaining() {
+		// every element takes at least one felt
+		return 0, fmt.Errorf("length %d: %w", n, ErrUnexpectedEnd)
+	}
+	return int(n), nil
+}
+
+// ByteArray reads a core::byte_array::ByteArray.
+func (d *Decoder) ByteArray() (string, error) {
+	full, err := d.Len()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 0, (full+1)*bytes31Len)
+	for i := 0; i < full; i++ {
+		f, err := d.Felt()
+		if err != nil {
+			return "", err
+		}
+		word := f.Bytes()
+		b = append(b, word[32-bytes31Len:]...)
+	}
+	pending, err := d.Felt()
+	if err != nil {
+		return "", err
+	}
+	pendingLen, err := d.Uint(8)
+	if err != nil {
+		return "", err
+	}
+	if pendingLen >= bytes31Len {
+		return "", fmt.Errorf("pending word length %d: %w", pendingLen, ErrOutOfRange)
+	}
+	word := pending.Bytes()
+	b = append(b, word[32-pendingLen:]...)
+	return string(b), nil
+}
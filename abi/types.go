@@ -0,0 +1,202 @@
+package abi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fully qualified names of the Cairo core types that have a dedicated Serde layout
+const (
+	TypeFelt252         = "core::felt252"
+	TypeBool            = "core::bool"
+	TypeU8              = "core::integer::u8"
+	TypeU16             = "core::integer::u16"
+	TypeU32             = "core::integer::u32"
+	TypeU64             = "core::integer::u64"
+	TypeU128            = "core::integer::u128"
+	TypeU256            = "core::integer::u256"
+	TypeI8              = "core::integer::i8"
+	TypeI16             = "core::integer::i16"
+	TypeI32             = "core::integer::i32"
+	TypeI64             = "core::integer::i64"
+	TypeI128            = "core::integer::i128"
+	TypeContractAddress = "core::starknet::contract_address::ContractAddress"
+	TypeClassHash       = "core::starknet::class_hash::ClassHash"
+	TypeEthAddress      = "core::starknet::eth_address::EthAddress"
+	TypeStorageAddress  = "core::starknet::storage_access::StorageAddress"
+	TypeByteArray       = "core::byte_array::ByteArray"
+	TypeBytes31         = "core::bytes_31::bytes31"
+	TypeArray           = "core::array::Array"
+	TypeSpan            = "core::array::Span"
+	TypeOption          = "core::option::Option"
+	TypeResult          = "core::result::Result"
+	TypeNonZero         = "core::zeroable::NonZero"
+)
+
+// TypeRef is a parsed Cairo type expression such as
+// `core::array::Array::<core::integer::u8>` or `(core::felt252, core::bool)`.
+type TypeRef struct {
+	// Name is the fully qualified path without generic arguments. It is empty for tuples.
+	Name string
+	// Params holds the generic arguments, or the elements of a tuple.
+	Params []*TypeRef
+	// Tuple is true if the type is a tuple, `()` being the empty tuple (unit).
+	Tuple bool
+	// Snapshot is true if the type is a snapshot (`@T`), which shares the Serde layout of T.
+	Snapshot bool
+}
+
+// ParseType parses a Cairo type expression.
+//
+// Parameters:
+// - s: the type expression as found in the ABI
+// Returns:
+// - *TypeRef: the parsed type
+// - error: an error if the expression is malformed
+func ParseType(s string) (*TypeRef, error) {
+	p := typeParser{s: s}
+	t, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("invalid type %q: unexpected %q at %d", s, p.s[p.pos:], p.pos)
+	}
+	return t, nil
+}
+
+// String returns the type expression in the ABI notation.
+func (t *TypeRef) String() string {
+	var sb strings.Builder
+	if t.Snapshot {
+		sb.WriteString("@")
+	}
+	if t.Tuple {
+		sb.WriteString("(")
+		for i, p := range t.Params {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(p.String())
+		}
+		sb.WriteString(")")
+		return sb.String()
+	}
+	sb.WriteString(t.Name)
+	if len(t.Params) > 0 {
+		sb.WriteString("::<")
+		for i, p := range t.Params {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(p.String())
+		}
+		sb.WriteString(">")
+	}
+	return sb.String()
+}
+
+// IsUnit returns true if the type is the empty tuple `()`.
+func (t *TypeRef) IsUnit() bool {
+	return t.Tuple && len(t.Params) == 0
+}
+
+// ShortName returns the last segment of the type path, e.g. `u256` for `core::integer::u256`.
+func (t *TypeRef) ShortName() string {
+	if i := strings.LastIndex(t.Name, "::"); i >= 0 {
+		return t.Name[i+2:]
+	}
+	return t.Name
+}
+
+type typeParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *typeParser) parse() (*TypeRef, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("unexpected end of type")
+	}
+
+	if p.s[p.pos] == '@' {
+		p.pos++
+		t, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		t.Snapshot = true
+		return t, nil
+	}
+
+	if p.s[p.pos] == '(' {
+		p.pos++
+		t := &TypeRef{Tuple: true}
+		params, err := p.parseList(')')
+		if err != nil {
+			return nil, err
+		}
+		t.Params = params
+		return t, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) {
+		if strings.HasPrefix(p.s[p.pos:], "::<") {
+			t := &TypeRef{Name: p.s[start:p.pos]}
+			p.pos += len("::<")
+			params, err := p.parseList('>')
+			if err != nil {
+				return nil, err
+			}
+			t.Params = params
+			return t, nil
+		}
+		c := p.s[p.pos]
+		if c == ',' || c == '>' || c == ')' || c == ' ' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("empty type name at %d", start)
+	}
+	return &TypeRef{Name: p.s[start:p.pos]}, nil
+}
+
+func (p *typeParser) parseList(closing byte) ([]*TypeRef, error) {
+	params := []*TypeRef{}
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == closing {
+		p.pos++
+		return params, nil
+	}
+	for {
+		t, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, t)
+		p.skipSpaces()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("missing %q", closing)
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case closing:
+			p.pos++
+			return params, nil
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+		}
+	}
+}
@@ -0,0 +1,804 @@
+package abigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/starknet.go/abi"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Config holds the options of the generated binding.
+type Config struct {
+	// Package is the name of the generated Go package
+	Package string
+	// Type is the name of the generated contract binding type
+	Type string
+}
+
+// GenerateFromFile generates a Go binding from a Sierra contract class
+// (e.g. `*.sierra.json`) or from a raw ABI JSON array.
+//
+// Parameters:
+// - path: the path of the Sierra class or ABI file
+// - cfg: the generation options
+// Returns:
+// - []byte: the gofmt-ed Go source of the binding
+// - error: an error if the file cannot be read or the ABI cannot be bound
+func GenerateFromFile(path string, cfg Config) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var contractABI *abi.ABI
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		contractABI, err = abi.Parse(trimmed)
+	} else {
+		var class rpc.ContractClass
+		if err = json.Unmarshal(content, &class); err != nil {
+			return nil, fmt.Errorf("%s is neither an abi nor a contract class: %w", path, err)
+		}
+		contractABI, err = abi.FromContractClass(&class)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Generate(contractABI, cfg)
+}
+
+// Generate generates a Go binding for the given ABI. View functions are bound
+// to `Call`, external functions to Account.Execute, and every struct and enum
+// reachable from the functions gets a Go type with Serde (un)marshalling methods.
+//
+// Parameters:
+// - contractABI: the parsed contract ABI
+// - cfg: the generation options
+// Returns:
+// - []byte: the gofmt-ed Go source of the binding
+// - error: an error if a type is not supported or the configuration is invalid
+func Generate(contractABI *abi.ABI, cfg Config) ([]byte, error) {
+	if cfg.Package == "" || cfg.Type == "" {
+		return nil, fmt.Errorf("package and type names are required")
+	}
+	g := &generator{
+		abi:     contractABI,
+		cfg:     cfg,
+		names:   map[string]string{},
+		used:    map[string]bool{cfg.Type: true},
+		methods: map[string]string{},
+	}
+	return g.generate()
+}
+
+type generator struct {
+	abi *abi.ABI
+	cfg Config
+
+	// names maps a Cairo type to the Go type declared for it
+	names map[string]string
+	// used holds the declared package-level Go identifiers
+	used map[string]bool
+	// methods maps the binding method names to the Cairo function they were generated for
+	methods map[string]string
+
+	types  bytes.Buffer
+	funcs  bytes.Buffer
+	tmpIdx int
+}
+
+func (g *generator) generate() ([]byte, error) {
+	seen := map[string]bool{}
+	for _, fn := range g.abi.Functions {
+		if seen[fn.Name] {
+			continue
+		}
+		seen[fn.Name] = true
+		var err error
+		if fn.IsView() {
+			err = g.genView(fn)
+		} else {
+			err = g.genExternal(fn)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("function %s: %w", fn.Name, err)
+		}
+	}
+	if g.abi.Constructor != nil {
+		if err := g.genConstructor(*g.abi.Constructor); err != nil {
+			return nil, fmt.Errorf("constructor: %w", err)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by abigen. DO NOT EDIT.\n\npackage %s\n\n", g.cfg.Package)
+	body := g.types.String() + g.funcs.String()
+	out.WriteString(g.imports(body))
+	fmt.Fprintf(&out, `
+// %[1]s is a typed binding of a deployed contract.
+type %[1]s struct {
+	Address  *felt.Felt
+	provider rpc.RpcProvider
+}
+
+// New%[1]s returns a binding of the contract deployed at address.
+func New%[1]s(address *felt.Felt, provider rpc.RpcProvider) *%[1]s {
+	return &%[1]s{Address: address, provider: provider}
+}
+`, g.cfg.Type)
+	out.WriteString(body)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w", err)
+	}
+	return src, nil
+}
+
+// imports returns the import block needed by the generated body.
+func (g *generator) imports(body string) string {
+	std := map[string]bool{}
+	pkgs := map[string]bool{
+		"github.com/NethermindEth/juno/core/felt":  true,
+		"github.com/NethermindEth/starknet.go/rpc": true,
+	}
+	for ident, path := range map[string]string{
+		"context.": "context",
+		"fmt.":     "fmt",
+		"big.":     "math/big",
+	} {
+		if strings.Contains(body, ident) {
+			std[path] = true
+		}
+	}
+	for ident, path := range map[string]string{
+		"abi.":     "github.com/NethermindEth/starknet.go/abi",
+		"account.": "github.com/NethermindEth/starknet.go/account",
+		"utils.":   "github.com/NethermindEth/starknet.go/utils",
+	} {
+		if strings.Contains(body, ident) {
+			pkgs[path] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("import (\n")
+	for _, group := range []map[string]bool{std, pkgs} {
+		paths := make([]string, 0, len(group))
+		for path := range group {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Fprintf(&sb, "\t%q\n", path)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(")\n")
+	return sb.String()
+}
+
+// reserved holds the identifiers used by the generated method bodies
+var reserved = map[string]bool{
+	"c": true, "ctx": true, "blockID": true, "acnt": true, "opts": true,
+	"enc": true, "dec": true, "res": true, "out": true, "err": true, "call": true,
+}
+
+// params returns the Go parameter list and the encoding statements of the inputs.
+func (g *generator) params(inputs []abi.Param) (string, string, []string, error) {
+	var decl, body strings.Builder
+	names := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		t, err := abi.ParseType(in.Type)
+		if err != nil {
+			return "", "", nil, err
+		}
+		goType, err := g.goType(t)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("input %s: %w", in.Name, err)
+		}
+		name := lowerCamel(in.Name)
+		if reserved[name] || isKeyword(name) {
+			name += "_"
+		}
+		names = append(names, name)
+		fmt.Fprintf(&decl, ", %s %s", name, goType)
+		enc, err := g.encode(t, name)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("input %s: %w", in.Name, err)
+		}
+		body.WriteString(enc)
+	}
+	return strings.TrimPrefix(decl.String(), ", "), body.String(), names, nil
+}
+
+func (g *generator) method(fnName, suffix string) (string, error) {
+	name := upperCamel(fnName) + suffix
+	if other, ok := g.methods[name]; ok {
+		return "", fmt.Errorf("method %s clashes with the one generated for %s", name, other)
+	}
+	g.methods[name] = fnName
+	return name, nil
+}
+
+func (g *generator) genView(fn abi.Function) error {
+	name, err := g.method(fn.Name, "")
+	if err != nil {
+		return err
+	}
+	decl, encBody, _, err := g.params(fn.Inputs)
+	if err != nil {
+		return err
+	}
+	if decl != "" {
+		decl = ", " + decl
+	}
+
+	var outType, decBody string
+	switch len(fn.Outputs) {
+	case 0:
+	case 1:
+		t, err := abi.ParseType(fn.Outputs[0].Type)
+		if err != nil {
+			return err
+		}
+		if outType, err = g.goType(t); err != nil {
+			return fmt.Errorf("output: %w", err)
+		}
+		if decBody, err = g.decode(t, "out"); err != nil {
+			return fmt.Errorf("output: %w", err)
+		}
+	default:
+		return fmt.Errorf("functions with %d outputs are not supported", len(fn.Outputs))
+	}
+
+	if decBody != "" {
+		decBody = "dec := abi.NewDecoder(res)\n" + decBody
+	} else {
+		decBody = "_ = res\n"
+	}
+	results := "(err error)"
+	ret := "return nil"
+	if outType != "" {
+		results = fmt.Sprintf("(out %s, err error)", outType)
+		ret = "return out, nil"
+	}
+	fmt.Fprintf(&g.funcs, `
+// %s calls the `+"`%s`"+` view function.
+func (c *%s) %s(ctx context.Context, blockID rpc.BlockID%s) %s {
+	%s
+	if err = enc.Err(); err != nil {
+		return
+	}
+	var res []*felt.Felt
+	res, err = c.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    c.Address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(%q),
+		Calldata:           enc.Data(),
+	}, blockID)
+	if err != nil {
+		return
+	}
+	%s
+	%s
+}
+`, name, fn.Name, g.cfg.Type, name, decl, results, block("enc := abi.NewEncoder()\n"+encBody), fn.Name, block(decBody), ret)
+	return nil
+}
+
+func (g *generator) genExternal(fn abi.Function) error {
+	name, err := g.method(fn.Name, "")
+	if err != nil {
+		return err
+	}
+	callName, err := g.method(fn.Name, "Call")
+	if err != nil {
+		return err
+	}
+	decl, encBody, names, err := g.params(fn.Inputs)
+	if err != nil {
+		return err
+	}
+	args := strings.Join(names, ", ")
+	invokeDecl := decl
+	if invokeDecl != "" {
+		invokeDecl = ", " + invokeDecl
+	}
+	fmt.Fprintf(&g.funcs, `
+// %[1]s builds the call to the `+"`%[2]s`"+` external function.
+func (c *%[3]s) %[1]s(%[4]s) (rpc.FunctionCall, error) {
+	enc := abi.NewEncoder()
+	%[5]s
+	return rpc.FunctionCall{
+		ContractAddress:    c.Address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(%[2]q),
+		Calldata:           enc.Data(),
+	}, enc.Err()
+}
+
+// %[6]s invokes the `+"`%[2]s`"+` external function from acnt.
+func (c *%[3]s) %[6]s(ctx context.Context, acnt *account.Account, opts *account.ExecuteOptions%[7]s) (*account.ExecuteResponse, error) {
+	call, err := c.%[1]s(%[8]s)
+	if err != nil {
+		return nil, err
+	}
+	return acnt.Execute(ctx, []rpc.FunctionCall{call}, opts)
+}
+`, callName, fn.Name, g.cfg.Type, decl, block(encBody), name, invokeDecl, args)
+	return nil
+}
+
+func (g *generator) genConstructor(fn abi.Function) error {
+	decl, encBody, _, err := g.params(fn.Inputs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.funcs, `
+// %[1]sConstructorCalldata encodes the constructor arguments of the contract.
+func %[1]sConstructorCalldata(%[2]s) ([]*felt.Felt, error) {
+	enc := abi.NewEncoder()
+	%[3]s
+	return enc.Data(), enc.Err()
+}
+`, g.cfg.Type, decl, block(encBody))
+	return nil
+}
+
+// goType returns the Go type used to represent a Cairo type, declaring it if needed.
+func (g *generator) goType(t *abi.TypeRef) (string, error) {
+	if t.Tuple {
+		if t.IsUnit() {
+			return "struct{}", nil
+		}
+		var sb strings.Builder
+		sb.WriteString("struct {\n")
+		for i, p := range t.Params {
+			elem, err := g.goType(p)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&sb, "V%d %s\n", i, elem)
+		}
+		sb.WriteString("}")
+		return sb.String(), nil
+	}
+
+	switch t.Name {
+	case abi.TypeFelt252, abi.TypeContractAddress, abi.TypeClassHash, abi.TypeEthAddress, abi.TypeStorageAddress, abi.TypeBytes31:
+		return "*felt.Felt", nil
+	case abi.TypeBool:
+		return "bool", nil
+	case abi.TypeU8, abi.TypeU16, abi.TypeU32, abi.TypeU64:
+		return "uint" + strings.TrimPrefix(t.ShortName(), "u"), nil
+	case abi.TypeI8, abi.TypeI16, abi.TypeI32, abi.TypeI64:
+		return "int" + strings.TrimPrefix(t.ShortName(), "i"), nil
+	case abi.TypeU128, abi.TypeU256, abi.TypeI128:
+		return "*big.Int", nil
+	case abi.TypeByteArray:
+		return "string", nil
+	case abi.TypeArray, abi.TypeSpan:
+		elem, err := g.goType(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case abi.TypeOption:
+		inner, err := g.goType(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		return pointerTo(inner), nil
+	case abi.TypeNonZero:
+		return g.goType(t.Params[0])
+	}
+
+	key := t.String()
+	if name, ok := g.names[key]; ok {
+		return name, nil
+	}
+	if s, ok := g.abi.Struct(key); ok {
+		return g.declareStruct(t, s)
+	}
+	if e, ok := g.abi.Enum(key); ok {
+		return g.declareEnum(t, e)
+	}
+	return "", fmt.Errorf("unsupported type %s", key)
+}
+
+// pointerTo returns a Go type able to hold an optional value of the given type.
+func pointerTo(goType string) string {
+	if strings.HasPrefix(goType, "*") {
+		return goType
+	}
+	return "*" + goType
+}
+
+// typeName returns an unused Go identifier for a named Cairo type.
+func (g *generator) typeName(t *abi.TypeRef) string {
+	var sb strings.Builder
+	sb.WriteString(upperCamel(t.ShortName()))
+	for _, p := range t.Params {
+		if p.Tuple {
+			sb.WriteString("Tuple")
+		}
+		for _, inner := range append([]*abi.TypeRef{p}, p.Params...) {
+			if inner.Name != "" {
+				sb.WriteString(upperCamel(inner.ShortName()))
+			}
+		}
+	}
+	base := sb.String()
+	name := base
+	for i := 2; g.used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.used[name] = true
+	g.names[t.String()] = name
+	return name
+}
+
+func (g *generator) declareStruct(t *abi.TypeRef, s *abi.Struct) (string, error) {
+	name := g.typeName(t)
+
+	var fields, enc, dec strings.Builder
+	for _, m := range s.Members {
+		mt, err := abi.ParseType(m.Type)
+		if err != nil {
+			return "", err
+		}
+		goType, err := g.goType(mt)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", s.Name, m.Name, err)
+		}
+		field := upperCamel(m.Name)
+		fmt.Fprintf(&fields, "%s %s\n", field, goType)
+		e, err := g.encode(mt, "s."+field)
+		if err != nil {
+			return "", err
+		}
+		enc.WriteString(e)
+		d, err := g.decode(mt, "s."+field)
+		if err != nil {
+			return "", err
+		}
+		dec.WriteString(d)
+	}
+
+	fmt.Fprintf(&g.types, `
+// %[1]s is the Go representation of the Cairo struct `+"`%[2]s`"+`.
+type %[1]s struct {
+	%[3]s
+}
+
+// MarshalCairo appends the Serde encoding of the struct to enc.
+func (s %[1]s) MarshalCairo(enc *abi.Encoder) {
+	%[4]s
+}
+
+// UnmarshalCairo reads the struct from dec.
+func (s *%[1]s) UnmarshalCairo(dec *abi.Decoder) (err error) {
+	%[5]s
+	return nil
+}
+`, name, s.Name, block(fields.String()), block(enc.String()), block(dec.String()))
+	return name, nil
+}
+
+func (g *generator) declareEnum(t *abi.TypeRef, e *abi.Enum) (string, error) {
+	name := g.typeName(t)
+
+	unitOnly := true
+	for _, v := range e.Variants {
+		if v.Type != "()" {
+			unitOnly = false
+		}
+	}
+
+	if unitOnly {
+		var consts strings.Builder
+		for i, v := range e.Variants {
+			if i == 0 {
+				fmt.Fprintf(&consts, "%s%s %s = iota\n", name, upperCamel(v.Name), name)
+				continue
+			}
+			fmt.Fprintf(&consts, "%s%s\n", name, upperCamel(v.Name))
+		}
+		fmt.Fprintf(&g.types, `
+// %[1]s is the Go representation of the Cairo enum `+"`%[2]s`"+`.
+type %[1]s uint64
+
+const (
+	%[3]s
+)
+
+// MarshalCairo appends the Serde encoding of the enum to enc.
+func (e %[1]s) MarshalCairo(enc *abi.Encoder) {
+	enc.Uint(uint64(e))
+}
+
+// UnmarshalCairo reads the enum from dec.
+func (e *%[1]s) UnmarshalCairo(dec *abi.Decoder) error {
+	v, err := dec.Uint(64)
+	if err != nil {
+		return err
+	}
+	if v >= %[4]d {
+		return fmt.Errorf("invalid %[1]s variant %%d", v)
+	}
+	*e = %[1]s(v)
+	return nil
+}
+`, name, e.Name, block(consts.String()), len(e.Variants))
+		return name, nil
+	}
+
+	var fields, enc, dec strings.Builder
+	for i, v := range e.Variants {
+		field := upperCamel(v.Name)
+		if v.Type == "()" {
+			fmt.Fprintf(&fields, "%s bool\n", field)
+			fmt.Fprintf(&enc, "case e.%s:\nenc.Uint(%d)\n", field, i)
+			fmt.Fprintf(&dec, "case %d:\ne.%s = true\n", i, field)
+			continue
+		}
+		vt, err := abi.ParseType(v.Type)
+		if err != nil {
+			return "", err
+		}
+		goType, err := g.goType(vt)
+		if err != nil {
+			return "", fmt.Errorf("%s::%s: %w", e.Name, v.Name, err)
+		}
+		fieldType := pointerTo(goType)
+		fmt.Fprintf(&fields, "%s %s\n", field, fieldType)
+
+		value := "e." + field
+		if fieldType != goType {
+			value = "*" + value
+		}
+		encV, err := g.encode(vt, value)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&enc, "case e.%s != nil:\nenc.Uint(%d)\n%s", field, i, encV)
+
+		tmp := g.tmp("v")
+		decV, err := g.decode(vt, tmp)
+		if err != nil {
+			return "", err
+		}
+		assign := "&" + tmp
+		if fieldType == goType {
+			assign = tmp
+		}
+		fmt.Fprintf(&dec, "case %d:\nvar %s %s\n%se.%s = %s\n", i, tmp, goType, decV, field, assign)
+	}
+
+	fmt.Fprintf(&g.types, `
+// %[1]s is the Go representation of the Cairo enum `+"`%[2]s`"+`.
+// Exactly one of its fields must be set.
+type %[1]s struct {
+	%[3]s
+}
+
+// MarshalCairo appends the Serde encoding of the enum to enc.
+func (e %[1]s) MarshalCairo(enc *abi.Encoder) {
+	switch {
+	%[4]s
+	default:
+		enc.SetErr(fmt.Errorf("%[1]s has no variant set"))
+	}
+}
+
+// UnmarshalCairo reads the enum from dec.
+func (e *%[1]s) UnmarshalCairo(dec *abi.Decoder) (err error) {
+	var variant uint64
+	variant, err = dec.Uint(64)
+	if err != nil {
+		return
+	}
+	switch variant {
+	%[5]s
+	default:
+		return fmt.Errorf("invalid %[1]s variant %%d", variant)
+	}
+	return nil
+}
+`, name, e.Name, block(fields.String()), block(enc.String()), block(dec.String()))
+	return name, nil
+}
+
+// block strips the trailing newline of generated statements before they are
+// spliced into a template.
+func block(s string) string {
+	return strings.TrimSuffix(s, "\n")
+}
+
+func (g *generator) tmp(prefix string) string {
+	g.tmpIdx++
+	return fmt.Sprintf("%s%d", prefix, g.tmpIdx)
+}
+
+// encode returns the statements appending the Serde encoding of expr to `enc`.
+func (g *generator) encode(t *abi.TypeRef, expr string) (string, error) {
+	if t.Tuple {
+		var sb strings.Builder
+		for i, p := range t.Params {
+			s, err := g.encode(p, fmt.Sprintf("%s.V%d", expr, i))
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+		}
+		return sb.String(), nil
+	}
+
+	switch t.Name {
+	case abi.TypeFelt252, abi.TypeContractAddress, abi.TypeClassHash, abi.TypeEthAddress, abi.TypeStorageAddress, abi.TypeBytes31:
+		return fmt.Sprintf("enc.Felt(%s)\n", expr), nil
+	case abi.TypeBool:
+		return fmt.Sprintf("enc.Bool(%s)\n", expr), nil
+	case abi.TypeU8, abi.TypeU16, abi.TypeU32, abi.TypeU64:
+		return fmt.Sprintf("enc.Uint(uint64(%s))\n", expr), nil
+	case abi.TypeI8, abi.TypeI16, abi.TypeI32, abi.TypeI64:
+		return fmt.Sprintf("enc.Int(int64(%s))\n", expr), nil
+	case abi.TypeU128:
+		return fmt.Sprintf("enc.BigUint(%s, 128)\n", expr), nil
+	case abi.TypeI128:
+		return fmt.Sprintf("enc.BigInt(%s, 128)\n", expr), nil
+	case abi.TypeU256:
+		return fmt.Sprintf("enc.U256(%s)\n", expr), nil
+	case abi.TypeByteArray:
+		return fmt.Sprintf("enc.ByteArray(%s)\n", expr), nil
+	case abi.TypeArray, abi.TypeSpan:
+		elem := g.tmp("elem")
+		inner, err := g.encode(t.Params[0], elem)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("enc.Len(len(%[1]s))\nfor _, %[2]s := range %[1]s {\n%[3]s}\n", expr, elem, inner), nil
+	case abi.TypeOption:
+		goType, err := g.goType(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		value := expr
+		if pointerTo(goType) != goType {
+			value = "*" + expr
+		}
+		inner, err := g.encode(t.Params[0], value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("if %[1]s != nil {\nenc.Uint(0)\n%[2]s} else {\nenc.Uint(1)\n}\n", expr, inner), nil
+	case abi.TypeNonZero:
+		return g.encode(t.Params[0], expr)
+	}
+
+	if _, err := g.goType(t); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.MarshalCairo(enc)\n", expr), nil
+}
+
+// decode returns the statements reading target from `dec`. The statements
+// assign the named result `err` and return on failure.
+func (g *generator) decode(t *abi.TypeRef, target string) (string, error) {
+	check := "if err != nil {\nreturn\n}\n"
+	if t.Tuple {
+		var sb strings.Builder
+		for i, p := range t.Params {
+			s, err := g.decode(p, fmt.Sprintf("%s.V%d", target, i))
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+		}
+		return sb.String(), nil
+	}
+
+	switch t.Name {
+	case abi.TypeFelt252, abi.TypeContractAddress, abi.TypeClassHash, abi.TypeEthAddress, abi.TypeStorageAddress, abi.TypeBytes31:
+		return fmt.Sprintf("%s, err = dec.Felt()\n%s", target, check), nil
+	case abi.TypeBool:
+		return fmt.Sprintf("%s, err = dec.Bool()\n%s", target, check), nil
+	case abi.TypeU8, abi.TypeU16, abi.TypeU32, abi.TypeU64:
+		goType, _ := g.goType(t)
+		tmp := g.tmp("v")
+		return fmt.Sprintf("var %[1]s uint64\n%[1]s, err = dec.Uint(%[2]s)\n%[3]s%[4]s = %[5]s(%[1]s)\n",
+			tmp, strings.TrimPrefix(t.ShortName(), "u"), check, target, goType), nil
+	case abi.TypeI8, abi.TypeI16, abi.TypeI32, abi.TypeI64:
+		goType, _ := g.goType(t)
+		tmp := g.tmp("v")
+		return fmt.Sprintf("var %[1]s int64\n%[1]s, err = dec.Int(%[2]s)\n%[3]s%[4]s = %[5]s(%[1]s)\n",
+			tmp, strings.TrimPrefix(t.ShortName(), "i"), check, target, goType), nil
+	case abi.TypeU128:
+		return fmt.Sprintf("%s, err = dec.BigUint(128)\n%s", target, check), nil
+	case abi.TypeI128:
+		return fmt.Sprintf("%s, err = dec.BigInt(128)\n%s", target, check), nil
+	case abi.TypeU256:
+		return fmt.Sprintf("%s, err = dec.U256()\n%s", target, check), nil
+	case abi.TypeByteArray:
+		return fmt.Sprintf("%s, err = dec.ByteArray()\n%s", target, check), nil
+	case abi.TypeArray, abi.TypeSpan:
+		elemType, err := g.goType(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		n, i := g.tmp("n"), g.tmp("i")
+		inner, err := g.decode(t.Params[0], fmt.Sprintf("%s[%s]", target, i))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("var %[1]s int\n%[1]s, err = dec.Len()\n%[2]s%[3]s = make([]%[4]s, %[1]s)\nfor %[5]s := range %[3]s {\n%[6]s}\n",
+			n, check, target, elemType, i, inner), nil
+	case abi.TypeOption:
+		goType, err := g.goType(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		variant, value := g.tmp("variant"), g.tmp("v")
+		inner, err := g.decode(t.Params[0], value)
+		if err != nil {
+			return "", err
+		}
+		assign := "&" + value
+		if pointerTo(goType) == goType {
+			assign = value
+		}
+		return fmt.Sprintf("var %[1]s uint64\n%[1]s, err = dec.Uint(1)\n%[2]sif %[1]s == 0 {\nvar %[3]s %[4]s\n%[5]s%[6]s = %[7]s\n}\n",
+			variant, check, value, goType, inner, target, assign), nil
+	case abi.TypeNonZero:
+		return g.decode(t.Params[0], target)
+	}
+
+	if _, err := g.goType(t); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("err = %s.UnmarshalCairo(dec)\n%s", target, check), nil
+}
+
+// upperCamel converts a snake_case Cairo identifier to an exported Go identifier.
+func upperCamel(s string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// lowerCamel converts a snake_case Cairo identifier to an unexported Go identifier.
+func lowerCamel(s string) string {
+	u := []rune(upperCamel(s))
+	if len(u) == 0 {
+		return "arg"
+	}
+	u[0] = unicode.ToLower(u[0])
+	return string(u)
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+		"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+		"return", "select", "struct", "switch", "type", "var",
+		"abi", "account", "big", "felt", "fmt", "rpc", "utils", "context":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package abigen
+
+import (
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/abi"
+	"github.com/test-go/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestGenerateFromFile tests the bindings generated for a Sierra class and a raw ABI against golden files.
+// Run with -update to regenerate them.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGenerateFromFile(t *testing.T) {
+	type testSetType struct {
+		Path   string
+		Config Config
+		Golden string
+	}
+	testSet := []testSetType{
+		{
+			Path:   "./tests/hello_starknet_compiled.sierra.json",
+			Config: Config{Package: "hello", Type: "HelloStarknet"},
+			Golden: "./tests/hello_starknet.go.golden",
+		},
+		{
+			Path:   "./tests/example_abi.json",
+			Config: Config{Package: "example", Type: "Example"},
+			Golden: "./tests/example.go.golden",
+		},
+	}
+	for _, test := range testSet {
+		src, err := GenerateFromFile(test.Path, test.Config)
+		require.NoError(t, err, test.Path)
+
+		_, err = parser.ParseFile(token.NewFileSet(), test.Golden, src, parser.AllErrors)
+		require.NoError(t, err, test.Path)
+
+		if *update {
+			require.NoError(t, os.WriteFile(test.Golden, src, 0o644))
+		}
+		expected, err := os.ReadFile(test.Golden)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(src), test.Path)
+	}
+}
+
+// TestGenerateErrors tests that ABIs which cannot be bound are reported.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGenerateErrors(t *testing.T) {
+	type testSetType struct {
+		ABI    string
+		Config Config
+	}
+	testSet := []testSetType{
+		{
+			ABI:    `[]`,
+			Config: Config{Package: "example"},
+		},
+		{
+			ABI:    `[{"type": "function", "name": "get", "inputs": [{"name": "a", "type": "example::Unknown"}], "outputs": [], "state_mutability": "view"}]`,
+			Config: Config{Package: "example", Type: "Example"},
+		},
+		{
+			ABI: `[
+				{"type": "function", "name": "transfer_call", "inputs": [], "outputs": [], "state_mutability": "view"},
+				{"type": "function", "name": "transfer", "inputs": [], "outputs": [], "state_mutability": "external"}
+			]`,
+			Config: Config{Package: "example", Type: "Example"},
+		},
+	}
+	for _, test := range testSet {
+		a, err := abi.Parse([]byte(test.ABI))
+		require.NoError(t, err)
+		_, err = Generate(a, test.Config)
+		require.Error(t, err, test.ABI)
+	}
+}
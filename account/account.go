@@ -7,9 +7,11 @@ import (
 
 	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typed"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
@@ -18,6 +20,7 @@ var (
 	ErrTxnTypeUnSupported    = errors.New("Unsupported transction type")
 	ErrTxnVersionUnSupported = errors.New("Unsupported transction version")
 	ErrFeltToBigInt          = errors.New("Felt to BigInt error")
+	ErrTypedDataMsgNotSet    = errors.New("Typed data message is not set")
 )
 
 var (
@@ -33,9 +36,11 @@ type AccountInterface interface {
 	TransactionHashInvoke(invokeTxn rpc.InvokeTxnType) (*felt.Felt, error)
 	TransactionHashDeployAccount(tx rpc.DeployAccountType, contractAddress *felt.Felt) (*felt.Felt, error)
 	TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Felt, error)
-	SignInvokeTransaction(ctx context.Context, tx *rpc.InvokeTxnV1) error
-	SignDeployAccountTransaction(ctx context.Context, tx *rpc.DeployAccountTxn, precomputeAddress *felt.Felt) error
-	SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error
+	SignInvokeTransaction(ctx context.Context, tx rpc.InvokeTxnType) error
+	SignDeployAccountTransaction(ctx context.Context, tx rpc.DeployAccountType, precomputeAddress *felt.Felt) error
+	SignDeclareTransaction(ctx context.Context, tx rpc.DeclareTxnType) error
+	SignTypedData(ctx context.Context, td typed.TypedData) ([]*felt.Felt, error)
+	VerifyTypedData(ctx context.Context, td typed.TypedData, signature []*felt.Felt) (bool, error)
 	PrecomputeAddress(deployerAddress *felt.Felt, salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error)
 	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceipt, error)
 }
@@ -102,68 +107,147 @@ func (account *Account) Sign(ctx context.Context, msg *felt.Felt) ([]*felt.Felt,
 	return []*felt.Felt{s1Felt, s2Felt}, nil
 }
 
-// SignInvokeTransaction signs and invokes a transaction.
+// SignInvokeTransaction signs an invoke transaction and sets its signature.
 //
 // Parameters:
 // - ctx: the context.Context for the function execution.
-// - invokeTx: the InvokeTxnV1 struct representing the transaction to be invoked.
+// - invokeTx: a pointer to the transaction to sign, one of *rpc.InvokeTxnV1, *rpc.InvokeTxnV3,
+// *rpc.BroadcastInvokev1Txn or *rpc.BroadcastInvokev3Txn
 // Returns:
-// - error: an error if there was an error in the signing or invoking process
-func (account *Account) SignInvokeTransaction(ctx context.Context, invokeTx *rpc.InvokeTxnV1) error {
-
-	txHash, err := account.TransactionHashInvoke(*invokeTx)
-	if err != nil {
+// - error: an error if the transaction type is unsupported or the signing fails
+func (account *Account) SignInvokeTransaction(ctx context.Context, invokeTx rpc.InvokeTxnType) error {
+	switch txn := invokeTx.(type) {
+	case *rpc.InvokeTxnV1:
+		txHash, err := account.TransactionHashInvoke(*txn)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
-	}
-	signature, err := account.Sign(ctx, txHash)
-	if err != nil {
+	case *rpc.InvokeTxnV3:
+		txHash, err := account.TransactionHashInvoke(*txn)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
+	case *rpc.BroadcastInvokev1Txn:
+		return account.SignInvokeTransaction(ctx, &txn.InvokeTxnV1)
+	case *rpc.BroadcastInvokev3Txn:
+		return account.SignInvokeTransaction(ctx, &txn.InvokeTxnV3)
+	default:
+		return ErrTxnTypeUnSupported
 	}
-	invokeTx.Signature = signature
-	return nil
 }
 
-// SignDeployAccountTransaction signs a deploy account transaction.
+// SignDeployAccountTransaction signs a deploy account transaction and sets its signature.
 //
 // Parameters:
 // - ctx: the context.Context for the function execution
-// - tx: the *rpc.DeployAccountTxn struct representing the transaction to be signed
-// - precomputeAddress: the precomputed address for the transaction
+// - tx: a pointer to the transaction to sign, one of *rpc.DeployAccountTxn, *rpc.DeployAccountTxnV3,
+// *rpc.BroadcastDeployAccountTxn or *rpc.BroadcastDeployAccountTxnV3
+// - precomputeAddress: the precomputed address of the deployed account
 // Returns:
-// - error: an error if any
-func (account *Account) SignDeployAccountTransaction(ctx context.Context, tx *rpc.DeployAccountTxn, precomputeAddress *felt.Felt) error {
-
-	hash, err := account.TransactionHashDeployAccount(*tx, precomputeAddress)
-	if err != nil {
+// - error: an error if the transaction type is unsupported or the signing fails
+func (account *Account) SignDeployAccountTransaction(ctx context.Context, tx rpc.DeployAccountType, precomputeAddress *felt.Felt) error {
+	switch txn := tx.(type) {
+	case *rpc.DeployAccountTxn:
+		txHash, err := account.TransactionHashDeployAccount(*txn, precomputeAddress)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
-	}
-	signature, err := account.Sign(ctx, hash)
-	if err != nil {
+	case *rpc.DeployAccountTxnV3:
+		txHash, err := account.TransactionHashDeployAccount(*txn, precomputeAddress)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
+	case *rpc.BroadcastDeployAccountTxn:
+		return account.SignDeployAccountTransaction(ctx, &txn.DeployAccountTxn, precomputeAddress)
+	case *rpc.BroadcastDeployAccountTxnV3:
+		return account.SignDeployAccountTransaction(ctx, &txn.DeployAccountTxnV3, precomputeAddress)
+	default:
+		return ErrTxnTypeUnSupported
 	}
-	tx.Signature = signature
-	return nil
 }
 
-// SignDeclareTransaction signs a DeclareTxnV2 transaction using the provided Account.
+// SignDeclareTransaction signs a declare transaction and sets its signature.
+// The class hash of the broadcast transactions is computed from their contract class.
 //
 // Parameters:
 // - ctx: the context.Context
-// - tx: the *rpc.DeclareTxnV2
+// - tx: a pointer to the transaction to sign, one of *rpc.DeclareTxnV2, *rpc.DeclareTxnV3,
+// *rpc.BroadcastDeclareTxnV2 or *rpc.BroadcastDeclareTxnV3
 // Returns:
-// - error: an error if any
-func (account *Account) SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error {
-
-	hash, err := account.TransactionHashDeclare(*tx)
-	if err != nil {
+// - error: an error if the transaction type is unsupported or the signing fails
+func (account *Account) SignDeclareTransaction(ctx context.Context, tx rpc.DeclareTxnType) error {
+	switch txn := tx.(type) {
+	case *rpc.DeclareTxnV2:
+		txHash, err := account.TransactionHashDeclare(*txn)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
-	}
-	signature, err := account.Sign(ctx, hash)
-	if err != nil {
+	case *rpc.DeclareTxnV3:
+		txHash, err := account.TransactionHashDeclare(*txn)
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
 		return err
+	case *rpc.BroadcastDeclareTxnV2:
+		classHash, err := hash.ClassHash(txn.ContractClass)
+		if err != nil {
+			return err
+		}
+		txHash, err := account.TransactionHashDeclare(rpc.DeclareTxnV2{
+			Type:              txn.Type,
+			SenderAddress:     txn.SenderAddress,
+			CompiledClassHash: txn.CompiledClassHash,
+			MaxFee:            txn.MaxFee,
+			Version:           rpc.TransactionVersion(txn.Version),
+			Nonce:             txn.Nonce,
+			ClassHash:         classHash,
+		})
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
+		return err
+	case *rpc.BroadcastDeclareTxnV3:
+		if txn.ContractClass == nil {
+			return ErrNotAllParametersSet
+		}
+		classHash, err := hash.ClassHash(*txn.ContractClass)
+		if err != nil {
+			return err
+		}
+		txHash, err := account.TransactionHashDeclare(rpc.DeclareTxnV3{
+			Type:                  txn.Type,
+			SenderAddress:         txn.SenderAddress,
+			CompiledClassHash:     txn.CompiledClassHash,
+			Version:               rpc.TransactionVersion(txn.Version),
+			Nonce:                 txn.Nonce,
+			ClassHash:             classHash,
+			ResourceBounds:        txn.ResourceBounds,
+			Tip:                   txn.Tip,
+			PayMasterData:         txn.PayMasterData,
+			AccountDeploymentData: txn.AccountDeploymentData,
+			NonceDataMode:         txn.NonceDataMode,
+			FeeMode:               txn.FeeMode,
+		})
+		if err != nil {
+			return err
+		}
+		txn.Signature, err = account.Sign(ctx, txHash)
+		return err
+	default:
+		return ErrTxnTypeUnSupported
 	}
-	tx.Signature = signature
-	return nil
 }
 
 // TransactionHashDeployAccount calculates the transaction hash for a deploy account transaction.
@@ -534,7 +618,7 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 		case <-t.C:
 			receipt, err := account.TransactionReceipt(ctx, transactionHash)
 			if err != nil {
-				if err.Error() == rpc.ErrHashNotFound.Error() {
+				if errors.Is(err, rpc.ErrHashNotFound) {
 					continue
 				} else {
 					return nil, err
@@ -621,21 +705,33 @@ func (account *Account) BlockTransactionCount(ctx context.Context, blockID rpc.B
 // - ctx: the context.Context object for the request.
 // - blockID: the rpc.BlockID object specifying the block to retrieve.
 // Returns:
-// - interface{}: an interface{} representing the retrieved block
+// - *rpc.BlockTxHashesResult: the retrieved block, either a block or a pending block
 // - error: an error if there was any issue retrieving the block
-func (account *Account) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+func (account *Account) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
 	return account.provider.BlockWithTxHashes(ctx, blockID)
 }
 
+// BlockWithReceipts retrieves the specified block along with its transactions and their receipts.
+//
+// Parameters:
+// - ctx: The context.Context object for the function.
+// - blockID: The rpc.BlockID parameter for the function.
+// Returns:
+// - *rpc.BlockWithReceiptsResult: The retrieved block, either a block or a pending block
+// - error: An error
+func (account *Account) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+	return account.provider.BlockWithReceipts(ctx, blockID)
+}
+
 // BlockWithTxs retrieves the specified block along with its transactions.
 //
 // Parameters:
 // - ctx: The context.Context object for the function.
 // - blockID: The rpc.BlockID parameter for the function.
 // Returns:
-// - interface{}: An interface{}
+// - *rpc.BlockWithTxsResult: The retrieved block, either a block or a pending block
 // - error: An error
-func (account *Account) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+func (account *Account) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithTxsResult, error) {
 	return account.provider.BlockWithTxs(ctx, blockID)
 }
 
@@ -703,6 +799,18 @@ func (account *Account) ClassHashAt(ctx context.Context, blockID rpc.BlockID, co
 	return account.provider.ClassHashAt(ctx, blockID, contractAddress)
 }
 
+// CompiledCasm returns the CASM compiled by the node from a declared Sierra class.
+//
+// Parameters:
+// - ctx: The context to use for the function call.
+// - classHash: The hash of the Sierra class.
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: an error if any occurred.
+func (account *Account) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	return account.provider.CompiledCasm(ctx, classHash)
+}
+
 // EstimateFee estimates the fee for a set of requests in the given block ID.
 //
 // Parameters:
@@ -874,6 +982,30 @@ func (account *Account) TransactionByHash(ctx context.Context, hash *felt.Felt)
 	return account.provider.TransactionByHash(ctx, hash)
 }
 
+// GetMessagesStatus returns the status of the L1 to L2 messages sent by an L1 transaction.
+//
+// Parameters:
+// - ctx: The context.Context
+// - transactionHash: The hash of the L1 transaction.
+// Returns:
+// - []rpc.MessageStatus: the status of the messages
+// - error: an error if any
+func (account *Account) GetMessagesStatus(ctx context.Context, transactionHash rpc.NumAsHex) ([]rpc.MessageStatus, error) {
+	return account.provider.GetMessagesStatus(ctx, transactionHash)
+}
+
+// GetStorageProof returns the Merkle paths proving classes, contracts and storage keys in a block.
+//
+// Parameters:
+// - ctx: The context.Context
+// - input: The block and the items to prove.
+// Returns:
+// - *rpc.StorageProofResult: the proofs and the roots of the tries
+// - error: an error if any
+func (account *Account) GetStorageProof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProofResult, error) {
+	return account.provider.GetStorageProof(ctx, input)
+}
+
 // GetTransactionStatus returns the transaction status.
 //
 // Parameters:
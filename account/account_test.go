@@ -14,10 +14,12 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/devnet"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/mocks"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typed"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/golang/mock/gomock"
 	"github.com/joho/godotenv"
@@ -1115,7 +1117,8 @@ func TestAddDeclareTxn(t *testing.T) {
 	var casmClass contracts.CasmClass
 	err = json.Unmarshal(content2, &casmClass)
 	require.NoError(t, err)
-	compClassHash := hash.CompiledClassHash(casmClass)
+	compClassHash, err := hash.CompiledClassHash(casmClass)
+	require.NoError(t, err)
 
 	nonce, err := acnt.Nonce(context.Background(), rpc.BlockID{Tag: "latest"}, acnt.AccountAddress)
 	require.NoError(t, err)
@@ -1158,3 +1161,632 @@ func newDevnet(t *testing.T, url string) (*devnet.DevNet, []devnet.TestAccount,
 	acnts, err := devnet.Accounts()
 	return devnet, acnts, err
 }
+
+// TestExecuteMOCK tests that Execute estimates the fee, signs and sends V1 and V3 invoke transactions.
+//
+// Parameters:
+//   - t: The testing.T object for running the test
+//
+// Returns:
+//
+//	none
+func TestExecuteMOCK(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_GOERLI", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	type testSetType struct {
+		Options     *account.ExecuteOptions
+		Estimate    rpc.FeeEstimate
+		ExpectedFee *felt.Felt
+		ExpectedRB  rpc.ResourceBoundsMapping
+	}
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				Options:     nil,
+				Estimate:    rpc.FeeEstimate{OverallFee: new(felt.Felt).SetUint64(1000)},
+				ExpectedFee: new(felt.Felt).SetUint64(1500),
+			},
+			{
+				Options:     &account.ExecuteOptions{FeeMultiplier: 2},
+				Estimate:    rpc.FeeEstimate{OverallFee: new(felt.Felt).SetUint64(1000)},
+				ExpectedFee: new(felt.Felt).SetUint64(2000),
+			},
+			{
+				Options: &account.ExecuteOptions{Version: rpc.TransactionV3},
+				Estimate: rpc.FeeEstimate{
+					GasConsumed: new(felt.Felt).SetUint64(100),
+					GasPrice:    new(felt.Felt).SetUint64(10),
+					OverallFee:  new(felt.Felt).SetUint64(1000),
+					FeeUnit:     rpc.UnitStrk,
+				},
+				ExpectedRB: rpc.ResourceBoundsMapping{
+					L1Gas: rpc.ResourceBounds{MaxAmount: "0x96", MaxPricePerUnit: "0xf"},
+					L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+				},
+			},
+		},
+	}[testEnv]
+
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    utils.TestHexToFelt(t, "0x5678"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("increase_balance"),
+		Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(1)},
+	}}
+	for _, test := range testSet {
+		ctx := context.Background()
+		nonce := new(felt.Felt).SetUint64(3)
+		mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), address).Return(nonce, nil)
+		mockRpcProvider.EXPECT().EstimateFee(ctx, gomock.Any(), []rpc.SimulationFlag{rpc.SKIP_VALIDATE}, rpc.WithBlockTag("pending")).Return([]rpc.FeeEstimate{test.Estimate}, nil)
+		mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, gomock.Any()).Return(&rpc.AddInvokeTransactionResponse{TransactionHash: new(felt.Felt).SetUint64(42)}, nil)
+
+		resp, err := acnt.Execute(ctx, calls, test.Options)
+		require.NoError(t, err)
+		require.Equal(t, new(felt.Felt).SetUint64(42), resp.TransactionHash)
+
+		var signature []*felt.Felt
+		switch tx := resp.Transaction.(type) {
+		case rpc.InvokeTxnV1:
+			require.Equal(t, test.ExpectedFee, tx.MaxFee)
+			require.Equal(t, nonce, tx.Nonce)
+			signature = tx.Signature
+		case rpc.InvokeTxnV3:
+			require.Equal(t, test.ExpectedRB, tx.ResourceBounds)
+			require.Equal(t, rpc.DAModeL1, tx.FeeMode)
+			signature = tx.Signature
+		default:
+			t.Fatalf("unexpected transaction %T", resp.Transaction)
+		}
+		txHash, err := acnt.TransactionHashInvoke(resp.Transaction)
+		require.NoError(t, err)
+		require.Len(t, signature, 2)
+		pubX := utils.FeltToBigInt(pub)
+		require.True(t, curve.Curve.Verify(utils.FeltToBigInt(txHash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, curve.Curve.GetYCoordinate(pubX)))
+	}
+}
+
+// TestSignV3TransactionsMOCK tests that V3 invoke, declare and deploy account transactions,
+// and their broadcast versions, are signed with the key of the account.
+//
+// Parameters:
+//   - t: The testing.T object for running the test
+//
+// Returns:
+//
+//	none
+func TestSignV3TransactionsMOCK(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, utils.TestHexToFelt(t, "0x1234"), pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	verify := func(txHash *felt.Felt, signature []*felt.Felt) {
+		require.Len(t, signature, 2)
+		pubX := utils.FeltToBigInt(pub)
+		require.True(t, curve.Curve.Verify(utils.FeltToBigInt(txHash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, curve.Curve.GetYCoordinate(pubX)))
+	}
+	bounds := account.FeeEstimateToResourceBounds(rpc.FeeEstimate{
+		GasConsumed: new(felt.Felt).SetUint64(1000),
+		GasPrice:    new(felt.Felt).SetUint64(200),
+		OverallFee:  new(felt.Felt).SetUint64(200000),
+	}, 1.5)
+	require.Equal(t, rpc.U64("0x5dc"), bounds.L1Gas.MaxAmount)
+	require.Equal(t, rpc.U128("0x12c"), bounds.L1Gas.MaxPricePerUnit)
+	require.Equal(t, rpc.U64("0x0"), bounds.L2Gas.MaxAmount)
+
+	// a 0.7 estimate, whose overall fee includes the L1 data gas:
+	// 1000 * 200 + 300 * 50 = 215000, covered by ceil(215000 / 200) = 1075 L1 gas
+	bounds = account.FeeEstimateToResourceBounds(rpc.FeeEstimate{
+		GasConsumed:     new(felt.Felt).SetUint64(1000),
+		GasPrice:        new(felt.Felt).SetUint64(200),
+		DataGasConsumed: new(felt.Felt).SetUint64(300),
+		DataGasPrice:    new(felt.Felt).SetUint64(50),
+		OverallFee:      new(felt.Felt).SetUint64(215000),
+	}, 1)
+	require.Equal(t, rpc.U64("0x433"), bounds.L1Gas.MaxAmount)
+	require.Equal(t, rpc.U128("0xc8"), bounds.L1Gas.MaxPricePerUnit)
+	// the rounding up covers a fee that is not a multiple of the gas price
+	bounds = account.FeeEstimateToResourceBounds(rpc.FeeEstimate{
+		GasConsumed:     new(felt.Felt).SetUint64(1000),
+		GasPrice:        new(felt.Felt).SetUint64(200),
+		DataGasConsumed: new(felt.Felt).SetUint64(3),
+		DataGasPrice:    new(felt.Felt).SetUint64(7),
+		OverallFee:      new(felt.Felt).SetUint64(200021),
+	}, 1)
+	require.Equal(t, rpc.U64("0x3e9"), bounds.L1Gas.MaxAmount)
+	// without an overall fee, the L1 data gas cost is added to the L1 gas cost
+	bounds = account.FeeEstimateToResourceBounds(rpc.FeeEstimate{
+		GasConsumed:     new(felt.Felt).SetUint64(1000),
+		GasPrice:        new(felt.Felt).SetUint64(200),
+		DataGasConsumed: new(felt.Felt).SetUint64(300),
+		DataGasPrice:    new(felt.Felt).SetUint64(50),
+	}, 2)
+	require.Equal(t, rpc.U64("0x866"), bounds.L1Gas.MaxAmount)
+	require.Equal(t, rpc.U128("0x190"), bounds.L1Gas.MaxPricePerUnit)
+
+	invoke := rpc.BroadcastInvokev3Txn{InvokeTxnV3: rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         acnt.AccountAddress,
+		Calldata:              []*felt.Felt{new(felt.Felt).SetUint64(1)},
+		Version:               rpc.TransactionV3,
+		Nonce:                 new(felt.Felt).SetUint64(1),
+		ResourceBounds:        bounds,
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}}
+	require.NoError(t, acnt.SignInvokeTransaction(context.Background(), &invoke))
+	txHash, err := acnt.TransactionHashInvoke(invoke.InvokeTxnV3)
+	require.NoError(t, err)
+	verify(txHash, invoke.Signature)
+
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	classHash, err := hash.ClassHash(class)
+	require.NoError(t, err)
+	declare := rpc.BroadcastDeclareTxnV3{
+		Type:                  rpc.TransactionType_Declare,
+		SenderAddress:         acnt.AccountAddress,
+		CompiledClassHash:     new(felt.Felt).SetUint64(2),
+		Version:               rpc.NumAsHex(rpc.TransactionV3),
+		Nonce:                 new(felt.Felt).SetUint64(2),
+		ContractClass:         &class,
+		ResourceBounds:        bounds,
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	require.NoError(t, acnt.SignDeclareTransaction(context.Background(), &declare))
+	txHash, err = acnt.TransactionHashDeclare(rpc.DeclareTxnV3{
+		Type:                  declare.Type,
+		SenderAddress:         declare.SenderAddress,
+		CompiledClassHash:     declare.CompiledClassHash,
+		Version:               rpc.TransactionV3,
+		Nonce:                 declare.Nonce,
+		ClassHash:             classHash,
+		ResourceBounds:        bounds,
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	})
+	require.NoError(t, err)
+	verify(txHash, declare.Signature)
+
+	deploy := rpc.BroadcastDeployAccountTxnV3{DeployAccountTxnV3: rpc.DeployAccountTxnV3{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV3,
+		Nonce:               &felt.Zero,
+		ContractAddressSalt: pub,
+		ConstructorCalldata: []*felt.Felt{pub},
+		ClassHash:           classHash,
+		ResourceBounds:      bounds,
+		Tip:                 "0x0",
+		PayMasterData:       []*felt.Felt{},
+		NonceDataMode:       rpc.DAModeL1,
+		FeeMode:             rpc.DAModeL1,
+	}}
+	precomputedAddress, err := acnt.PrecomputeAddress(&felt.Zero, pub, classHash, []*felt.Felt{pub})
+	require.NoError(t, err)
+	require.NoError(t, acnt.SignDeployAccountTransaction(context.Background(), &deploy, precomputedAddress))
+	txHash, err = acnt.TransactionHashDeployAccount(deploy.DeployAccountTxnV3, precomputedAddress)
+	require.NoError(t, err)
+	verify(txHash, deploy.Signature)
+
+	// transactions are signed in place, values cannot be signed
+	require.Equal(t, account.ErrTxnTypeUnSupported, acnt.SignInvokeTransaction(context.Background(), invoke.InvokeTxnV3))
+}
+
+// TestDeclareMOCK tests that Declare computes the hashes of the hello_starknet class,
+// signs the declare transaction and reports classes that are already declared.
+//
+// Parameters:
+//   - t: The testing.T object for running the test
+//
+// Returns:
+//
+//	none
+func TestDeclareMOCK(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	expectedClassHash, err := hash.ClassHash(class)
+	require.NoError(t, err)
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	expectedCompiledClassHash, err := hash.CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+
+	type testSetType struct {
+		Options *account.DeclareOptions
+		// ClassErr is the error of the class lookup, the class is declared if nil
+		ClassErr        error
+		EstimateErr     error
+		AddErr          error
+		AlreadyDeclared bool
+		ExpectedErr     error
+	}
+	testSet := map[string][]testSetType{
+		"mock": {
+			{Options: nil, ClassErr: rpc.ErrClassHashNotFound},
+			{Options: &account.DeclareOptions{Version: rpc.TransactionV3}, ClassErr: rpc.ErrClassHashNotFound},
+			{Options: nil, ClassErr: nil, AlreadyDeclared: true},
+			// the class is declared by another transaction after the lookup
+			{Options: nil, ClassErr: rpc.ErrClassHashNotFound, AddErr: rpc.ErrClassAlreadyDeclared, AlreadyDeclared: true},
+			{Options: nil, ClassErr: rpc.ErrClassHashNotFound, EstimateErr: rpc.ErrTxnExec, ExpectedErr: rpc.ErrTxnExec},
+			{Options: nil, ClassErr: rpc.ErrBlockNotFound, ExpectedErr: rpc.ErrBlockNotFound},
+		},
+	}[testEnv]
+
+	for _, test := range testSet {
+		ctx := context.Background()
+		nonce := new(felt.Felt).SetUint64(7)
+		var class rpc.ClassOutput
+		if test.ClassErr == nil {
+			class = &rpc.ContractClass{}
+		}
+		mockRpcProvider.EXPECT().Class(ctx, rpc.WithBlockTag("pending"), expectedClassHash).Return(class, test.ClassErr)
+		if !errors.Is(test.ClassErr, rpc.ErrClassHashNotFound) {
+			resp, err := acnt.Declare(ctx, "./tests/hello_starknet_compiled.sierra.json", "./tests/hello_starknet_compiled.casm.json", test.Options)
+			if test.ExpectedErr != nil {
+				require.True(t, errors.Is(err, test.ExpectedErr))
+				continue
+			}
+			require.NoError(t, err)
+			require.True(t, resp.AlreadyDeclared)
+			require.Equal(t, expectedClassHash, resp.ClassHash)
+			require.Nil(t, resp.TransactionHash)
+			continue
+		}
+		mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), address).Return(nonce, nil)
+		mockRpcProvider.EXPECT().EstimateFee(ctx, gomock.Any(), []rpc.SimulationFlag{rpc.SKIP_VALIDATE}, rpc.WithBlockTag("pending")).Return([]rpc.FeeEstimate{{
+			GasConsumed: new(felt.Felt).SetUint64(100),
+			GasPrice:    new(felt.Felt).SetUint64(10),
+			OverallFee:  new(felt.Felt).SetUint64(1000),
+		}}, test.EstimateErr)
+		if test.EstimateErr == nil {
+			mockRpcProvider.EXPECT().AddDeclareTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, tx rpc.BroadcastDeclareTxnType) (*rpc.AddDeclareTransactionResponse, error) {
+					var txHash *felt.Felt
+					var signature []*felt.Felt
+					switch tx := tx.(type) {
+					case rpc.BroadcastDeclareTxnV2:
+						require.Equal(t, new(felt.Felt).SetUint64(1500), tx.MaxFee)
+						require.Equal(t, expectedCompiledClassHash, tx.CompiledClassHash)
+						txHash, err = acnt.TransactionHashDeclare(rpc.DeclareTxnV2{
+							Type:              tx.Type,
+							SenderAddress:     tx.SenderAddress,
+							CompiledClassHash: tx.CompiledClassHash,
+							MaxFee:            tx.MaxFee,
+							Version:           rpc.TransactionV2,
+							Nonce:             tx.Nonce,
+							ClassHash:         expectedClassHash,
+						})
+						signature = tx.Signature
+					case rpc.BroadcastDeclareTxnV3:
+						require.Equal(t, rpc.U64("0x96"), tx.ResourceBounds.L1Gas.MaxAmount)
+						txHash, err = acnt.TransactionHashDeclare(rpc.DeclareTxnV3{
+							Type:                  tx.Type,
+							SenderAddress:         tx.SenderAddress,
+							CompiledClassHash:     tx.CompiledClassHash,
+							Version:               rpc.TransactionV3,
+							Nonce:                 tx.Nonce,
+							ClassHash:             expectedClassHash,
+							ResourceBounds:        tx.ResourceBounds,
+							Tip:                   tx.Tip,
+							PayMasterData:         tx.PayMasterData,
+							AccountDeploymentData: tx.AccountDeploymentData,
+							NonceDataMode:         tx.NonceDataMode,
+							FeeMode:               tx.FeeMode,
+						})
+						signature = tx.Signature
+					default:
+						t.Fatalf("unexpected transaction %T", tx)
+					}
+					require.NoError(t, err)
+					require.Len(t, signature, 2)
+					pubX := utils.FeltToBigInt(pub)
+					require.True(t, curve.Curve.Verify(utils.FeltToBigInt(txHash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, curve.Curve.GetYCoordinate(pubX)))
+					if test.AddErr != nil {
+						return nil, test.AddErr
+					}
+					return &rpc.AddDeclareTransactionResponse{TransactionHash: txHash, ClassHash: expectedClassHash}, nil
+				})
+		}
+
+		resp, err := acnt.Declare(ctx, "./tests/hello_starknet_compiled.sierra.json", "./tests/hello_starknet_compiled.casm.json", test.Options)
+		if test.ExpectedErr != nil {
+			require.True(t, errors.Is(err, test.ExpectedErr))
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, expectedClassHash, resp.ClassHash)
+		require.Equal(t, expectedCompiledClassHash, resp.CompiledClassHash)
+		require.Equal(t, test.AlreadyDeclared, resp.AlreadyDeclared)
+		if test.AlreadyDeclared {
+			require.Nil(t, resp.TransactionHash)
+		} else {
+			require.NotNil(t, resp.TransactionHash)
+		}
+	}
+
+	_, err = acnt.Declare(context.Background(), "./tests/missing.sierra.json", "./tests/hello_starknet_compiled.casm.json", nil)
+	require.Error(t, err)
+}
+
+// TestSignAndVerifyTypedData tests the signing of typed data and the off-chain
+// and on-chain verification of the signatures.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSignAndVerifyTypedData(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	content, err := os.ReadFile("../typed/tests/mail_StructArray.json")
+	require.NoError(t, err)
+	var td typed.TypedData
+	require.NoError(t, json.Unmarshal(content, &td))
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x5b5e9f6f6fb7d2647d81a8b2c2b99cbc9cc9d03d705576d7061812324dca5c0")
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil).AnyTimes()
+
+	acnt, err := account.NewAccount(mockRpcProvider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+	signature, err := acnt.SignTypedData(context.Background(), td)
+	require.NoError(t, err)
+	require.Len(t, signature, 2)
+
+	// verified off-chain with the public key of the account
+	valid, err := acnt.VerifyTypedData(context.Background(), td, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	hash, err := acnt.TypedDataHash(td)
+	require.NoError(t, err)
+	calldata := append([]*felt.Felt{hash, new(felt.Felt).SetUint64(2)}, signature...)
+	snakeCall := rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("is_valid_signature"),
+		Calldata:           calldata,
+	}
+	camelCall := rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("isValidSignature"),
+		Calldata:           calldata,
+	}
+
+	type testSetType struct {
+		Name          string
+		SetupMock     func()
+		ExpectedValid bool
+		ExpectedErr   error
+	}
+	testSet := []testSetType{
+		{
+			Name: "snip-6 account",
+			SetupMock: func() {
+				mockRpcProvider.EXPECT().Call(context.Background(), snakeCall, rpc.WithBlockTag("latest")).Return([]*felt.Felt{account.VALID}, nil)
+			},
+			ExpectedValid: true,
+		},
+		{
+			Name: "camel case account",
+			SetupMock: func() {
+				mockRpcProvider.EXPECT().Call(context.Background(), snakeCall, rpc.WithBlockTag("latest")).Return(nil, rpc.ErrContractError)
+				mockRpcProvider.EXPECT().Call(context.Background(), camelCall, rpc.WithBlockTag("latest")).Return([]*felt.Felt{new(felt.Felt).SetUint64(1)}, nil)
+			},
+			ExpectedValid: true,
+		},
+		{
+			Name: "rejected signature",
+			SetupMock: func() {
+				mockRpcProvider.EXPECT().Call(context.Background(), snakeCall, rpc.WithBlockTag("latest")).Return(nil, rpc.ErrContractError)
+				mockRpcProvider.EXPECT().Call(context.Background(), camelCall, rpc.WithBlockTag("latest")).Return(nil, rpc.ErrContractError)
+			},
+		},
+		{
+			Name: "account not deployed",
+			SetupMock: func() {
+				mockRpcProvider.EXPECT().Call(context.Background(), snakeCall, rpc.WithBlockTag("latest")).Return(nil, rpc.ErrContractNotFound)
+			},
+			ExpectedErr: rpc.ErrContractNotFound,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			test.SetupMock()
+
+			// the public key of the account doesn't match, the signature is verified on-chain
+			otherAcnt, err := account.NewAccount(mockRpcProvider, address, "0x1", ks, 2)
+			require.NoError(t, err)
+			valid, err := otherAcnt.VerifyTypedData(context.Background(), td, signature)
+			require.Equal(t, test.ExpectedErr, err)
+			require.Equal(t, test.ExpectedValid, valid)
+		})
+	}
+
+	_, err = acnt.SignTypedData(context.Background(), typed.TypedData{})
+	require.Equal(t, account.ErrTypedDataMsgNotSet, err)
+}
+
+// TestDecryptKey tests the decryption of the keystore test vectors of the
+// Ethereum v3 format and of a keystore written by EncryptKey with the scrypt
+// parameters of starkli. The keys of the Ethereum vectors are above the curve
+// order, they are decrypted but rejected.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDecryptKey(t *testing.T) {
+	type testSetType struct {
+		File        string
+		Password    string
+		ExpectedKey string
+		ExpectedErr error
+	}
+	testSet := []testSetType{
+		{
+			File:        "./tests/keystore/v3_scrypt.json",
+			Password:    "testpassword",
+			ExpectedErr: account.ErrPrivateKeyOutOfRange,
+		},
+		{
+			File:        "./tests/keystore/v3_scrypt.json",
+			Password:    "wrong password",
+			ExpectedErr: account.ErrDecrypt,
+		},
+		{
+			File:        "./tests/keystore/v3_pbkdf2.json",
+			Password:    "testpassword",
+			ExpectedErr: account.ErrPrivateKeyOutOfRange,
+		},
+		{
+			File:        "./tests/keystore/v3_31_byte_key.json",
+			Password:    "foo",
+			ExpectedKey: "0xfa7b3db73dc7dfdf8c5fbdb796d741e4488628c41fc4febd9160a866ba0f35",
+		},
+		{
+			File:        "./tests/keystore/light_scrypt.json",
+			Password:    "starknet",
+			ExpectedKey: "0x43b7fe9d91942c98cd5fd37579bd99ec74f879c4c79d886633eecae9dad35fa",
+		},
+		{
+			File:        "./tests/keystore/light_scrypt.json",
+			Password:    "wrong password",
+			ExpectedErr: account.ErrDecrypt,
+		},
+	}
+
+	for _, test := range testSet {
+		keyJSON, err := os.ReadFile(test.File)
+		require.NoError(t, err)
+
+		key, err := account.DecryptKey(keyJSON, test.Password)
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+			continue
+		}
+		require.NoError(t, err, test.File)
+		require.Equal(t, test.ExpectedKey, fmt.Sprintf("%#x", key))
+	}
+
+	// the KDF parameters are bounded before the key derivation
+	for _, params := range []string{
+		`"kdf":"scrypt","kdfparams":{"dklen":32,"n":4194304,"p":1,"r":8,"salt":"00"}`,
+		`"kdf":"scrypt","kdfparams":{"dklen":32,"n":8192,"p":1000,"r":8,"salt":"00"}`,
+		`"kdf":"scrypt","kdfparams":{"dklen":32,"n":1048576,"p":1,"r":32,"salt":"00"}`,
+		`"kdf":"scrypt","kdfparams":{"dklen":32,"n":1e300,"p":1,"r":8,"salt":"00"}`,
+		`"kdf":"scrypt","kdfparams":{"dklen":4096,"n":8192,"p":1,"r":8,"salt":"00"}`,
+		`"kdf":"pbkdf2","kdfparams":{"dklen":32,"c":1000000000,"prf":"hmac-sha256","salt":"00"}`,
+	} {
+		keyJSON := `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"00000000000000000000000000000000"},"ciphertext":"00",` +
+			params + `,"mac":"00"},"id":"00000000-0000-4000-8000-000000000000","version":3}`
+		_, err := account.DecryptKey([]byte(keyJSON), "password")
+		require.True(t, errors.Is(err, account.ErrKeystoreUnsupported), params)
+	}
+}
+
+// TestFileKeystore tests the creation, import, export, password change,
+// unlocking and signing of the keys of a FileKeystore.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestFileKeystore(t *testing.T) {
+	ks := account.NewFileKeystore(t.TempDir(), account.LightScryptN, account.LightScryptP)
+	msgHash := utils.TestHexToFelt(t, "0x73cf79c4bfa0c7a41f473c07e1be5ac25faa7c2fdf9edcbd12c1438f40f13d8")
+
+	// the key of TestSignMOCK, imported from a keystore with the scrypt parameters of starkli
+	keyJSON, err := os.ReadFile("./tests/keystore/light_scrypt.json")
+	require.NoError(t, err)
+	pub, err := ks.Import(keyJSON, "starknet", "password")
+	require.NoError(t, err)
+
+	_, _, err = ks.Sign(context.Background(), pub.String(), utils.FeltToBigInt(msgHash))
+	require.True(t, errors.Is(err, account.ErrKeyLocked))
+
+	require.Equal(t, account.ErrDecrypt, ks.Unlock(pub.String(), "starknet", 0))
+	require.NoError(t, ks.Unlock(pub.String(), "password", 0))
+	r, s, err := ks.Sign(context.Background(), pub.String(), utils.FeltToBigInt(msgHash))
+	require.NoError(t, err)
+	require.Equal(t, "0x10d405427040655f118bc8b897e2f2f8147858bbcb0e3d6bc6dfbc6d0205e8", fmt.Sprintf("%#x", r))
+	require.Equal(t, "0x5cdfe4a3d5b63002e9011ec0ba59ae2b75a43cb2a3bc1699b35aa64cb9ca3cf", fmt.Sprintf("%#x", s))
+	require.NoError(t, ks.Lock(pub.String()))
+	_, _, err = ks.Sign(context.Background(), pub.String(), utils.FeltToBigInt(msgHash))
+	require.True(t, errors.Is(err, account.ErrKeyLocked))
+
+	// the password can be changed and the key exported
+	require.NoError(t, ks.ChangePassword(pub.String(), "password", "new password"))
+	require.Equal(t, account.ErrDecrypt, ks.Unlock(pub.String(), "password", 0))
+	exported, err := ks.Export(pub.String(), "new password", "export password")
+	require.NoError(t, err)
+	key, err := account.DecryptKey(exported, "export password")
+	require.NoError(t, err)
+	require.Equal(t, "0x43b7fe9d91942c98cd5fd37579bd99ec74f879c4c79d886633eecae9dad35fa", fmt.Sprintf("%#x", key))
+
+	// a new key signs for the account until the timeout
+	newPub, err := ks.Create("password")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(newPub.String(), "password", 100*time.Millisecond))
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, utils.TestHexToFelt(t, "0x1"), newPub.String(), ks, 2)
+	require.NoError(t, err)
+	sig, err := acnt.Sign(context.Background(), msgHash)
+	require.NoError(t, err)
+	pubY := curve.Curve.GetYCoordinate(utils.FeltToBigInt(newPub))
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(sig[0]), utils.FeltToBigInt(sig[1]), utils.FeltToBigInt(newPub), pubY) ||
+		curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(sig[0]), utils.FeltToBigInt(sig[1]), utils.FeltToBigInt(newPub), new(big.Int).Sub(curve.Curve.P, pubY)))
+
+	time.Sleep(200 * time.Millisecond)
+	_, err = acnt.Sign(context.Background(), msgHash)
+	require.True(t, errors.Is(err, account.ErrKeyLocked))
+
+	_, err = ks.Export("0x1234", "password", "password")
+	require.True(t, errors.Is(err, account.ErrKeyNotFound))
+}
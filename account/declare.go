@@ -0,0 +1,223 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// DeclareOptions configures how Declare builds the transaction.
+type DeclareOptions struct {
+	// Version is the transaction version, rpc.TransactionV2 (fee paid in ETH with MaxFee)
+	// or rpc.TransactionV3 (fee paid in STRK with ResourceBounds). Defaults to rpc.TransactionV2.
+	Version rpc.TransactionVersion
+	// Nonce overrides the nonce fetched from the pending block
+	Nonce *felt.Felt
+	// FeeMultiplier is applied to the estimated fee. Defaults to DefaultFeeMultiplier.
+	FeeMultiplier float64
+
+	// MaxFee skips the fee estimation of V2 transactions
+	MaxFee *felt.Felt
+
+	// ResourceBounds skips the fee estimation of V3 transactions
+	ResourceBounds *rpc.ResourceBoundsMapping
+	// Tip of V3 transactions, defaults to 0x0
+	Tip rpc.U64
+	// PayMasterData of V3 transactions
+	PayMasterData []*felt.Felt
+	// AccountDeploymentData of V3 transactions
+	AccountDeploymentData []*felt.Felt
+	// NonceDataMode of V3 transactions, defaults to rpc.DAModeL1
+	NonceDataMode rpc.DataAvailabilityMode
+	// FeeDataMode of V3 transactions, defaults to rpc.DAModeL1
+	FeeDataMode rpc.DataAvailabilityMode
+}
+
+// DeclareResponse is the result of Declare.
+type DeclareResponse struct {
+	// TransactionHash is nil when the class was already declared
+	TransactionHash   *felt.Felt
+	ClassHash         *felt.Felt
+	CompiledClassHash *felt.Felt
+	// AlreadyDeclared reports that the class exists, it was found before the declaration
+	// or the network rejected the declaration because of it
+	AlreadyDeclared bool
+}
+
+// Declare declares the Sierra class compiled by Scarb at sierraPath, together
+// with its CASM compilation at casmPath. It computes the class hash and the
+// compiled class hash, fetches the nonce, estimates the fee unless it is set in
+// the options, signs the transaction and submits it.
+//
+// The class is looked up in the pending block first, as the fee estimation of
+// an already declared class fails with a transaction execution error. If the
+// class is already declared, no error is returned and the response holds the
+// class hash with AlreadyDeclared set.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// - sierraPath: the path of the .contract_class.json file
+// - casmPath: the path of the .compiled_contract_class.json file
+// - opts: the transaction options, nil for a V2 transaction with the default fee multiplier
+// Returns:
+// - *DeclareResponse: the transaction hash and the hashes of the class
+// - error: an error if any step fails
+func (account *Account) Declare(ctx context.Context, sierraPath, casmPath string, opts *DeclareOptions) (*DeclareResponse, error) {
+	if opts == nil {
+		opts = &DeclareOptions{}
+	}
+	multiplier := opts.FeeMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultFeeMultiplier
+	}
+
+	content, err := os.ReadFile(sierraPath)
+	if err != nil {
+		return nil, err
+	}
+	var class rpc.ContractClass
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	casmClass, err := contracts.UnmarshalCasmClass(casmPath)
+	if err != nil {
+		return nil, err
+	}
+
+	classHash, err := hash.ClassHash(class)
+	if err != nil {
+		return nil, err
+	}
+	compiledClassHash, err := hash.CompiledClassHash(*casmClass)
+	if err != nil {
+		return nil, err
+	}
+	response := &DeclareResponse{
+		ClassHash:         classHash,
+		CompiledClassHash: compiledClassHash,
+	}
+
+	_, err = account.Class(ctx, rpc.WithBlockTag("pending"), classHash)
+	switch {
+	case err == nil:
+		response.AlreadyDeclared = true
+		return response, nil
+	case !errors.Is(err, rpc.ErrClassHashNotFound):
+		return nil, err
+	}
+
+	nonce := opts.Nonce
+	if nonce == nil {
+		nonce, err = account.Nonce(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch opts.Version {
+	case "", rpc.TransactionV2:
+		err = account.declareV2(ctx, class, nonce, response, opts, multiplier)
+	case rpc.TransactionV3:
+		err = account.declareV3(ctx, class, nonce, response, opts, multiplier)
+	default:
+		return nil, ErrTxnVersionUnSupported
+	}
+	if errors.Is(err, rpc.ErrClassAlreadyDeclared) {
+		response.AlreadyDeclared = true
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (account *Account) declareV2(ctx context.Context, class rpc.ContractClass, nonce *felt.Felt, response *DeclareResponse, opts *DeclareOptions, multiplier float64) error {
+	tx := rpc.BroadcastDeclareTxnV2{
+		Type:              rpc.TransactionType_Declare,
+		SenderAddress:     account.AccountAddress,
+		CompiledClassHash: response.CompiledClassHash,
+		MaxFee:            opts.MaxFee,
+		Version:           rpc.NumAsHex(rpc.TransactionV2),
+		Signature:         []*felt.Felt{},
+		Nonce:             nonce,
+		ContractClass:     class,
+	}
+	if tx.MaxFee == nil {
+		tx.MaxFee = &felt.Zero
+		estimate, err := account.estimateFee(ctx, tx)
+		if err != nil {
+			return err
+		}
+		tx.MaxFee = mulFelt(estimate.OverallFee, multiplier)
+	}
+
+	if err := account.SignDeclareTransaction(ctx, &tx); err != nil {
+		return err
+	}
+	resp, err := account.AddDeclareTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	response.TransactionHash = resp.TransactionHash
+	return nil
+}
+
+func (account *Account) declareV3(ctx context.Context, class rpc.ContractClass, nonce *felt.Felt, response *DeclareResponse, opts *DeclareOptions, multiplier float64) error {
+	tx := rpc.BroadcastDeclareTxnV3{
+		Type:                  rpc.TransactionType_Declare,
+		SenderAddress:         account.AccountAddress,
+		CompiledClassHash:     response.CompiledClassHash,
+		Version:               rpc.NumAsHex(rpc.TransactionV3),
+		Signature:             []*felt.Felt{},
+		Nonce:                 nonce,
+		ContractClass:         &class,
+		Tip:                   opts.Tip,
+		PayMasterData:         opts.PayMasterData,
+		AccountDeploymentData: opts.AccountDeploymentData,
+		NonceDataMode:         opts.NonceDataMode,
+		FeeMode:               opts.FeeDataMode,
+	}
+	if tx.Tip == "" {
+		tx.Tip = "0x0"
+	}
+	if tx.PayMasterData == nil {
+		tx.PayMasterData = []*felt.Felt{}
+	}
+	if tx.AccountDeploymentData == nil {
+		tx.AccountDeploymentData = []*felt.Felt{}
+	}
+	if tx.NonceDataMode == "" {
+		tx.NonceDataMode = rpc.DAModeL1
+	}
+	if tx.FeeMode == "" {
+		tx.FeeMode = rpc.DAModeL1
+	}
+
+	if opts.ResourceBounds != nil {
+		tx.ResourceBounds = *opts.ResourceBounds
+	} else {
+		tx.ResourceBounds = zeroResourceBounds()
+		bounds, err := account.EstimateResourceBounds(ctx, tx, multiplier)
+		if err != nil {
+			return err
+		}
+		tx.ResourceBounds = bounds
+	}
+
+	if err := account.SignDeclareTransaction(ctx, &tx); err != nil {
+		return err
+	}
+	resp, err := account.AddDeclareTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	response.TransactionHash = resp.TransactionHash
+	return nil
+}
@@ -0,0 +1,266 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// DefaultFeeMultiplier is applied to the estimated fee when ExecuteOptions.FeeMultiplier is not set,
+// so that the transaction does not fail if the network conditions change before it is included.
+const DefaultFeeMultiplier = 1.5
+
+var ErrNoFeeEstimate = errors.New("fee estimation returned no estimate")
+
+// ExecuteOptions configures how Execute builds the transaction.
+type ExecuteOptions struct {
+	// Version is the transaction version, rpc.TransactionV1 (fee paid in ETH with MaxFee)
+	// or rpc.TransactionV3 (fee paid in STRK with ResourceBounds). Defaults to rpc.TransactionV1.
+	Version rpc.TransactionVersion
+	// Nonce overrides the nonce fetched from the pending block
+	Nonce *felt.Felt
+	// FeeMultiplier is applied to the estimated fee. Defaults to DefaultFeeMultiplier.
+	FeeMultiplier float64
+
+	// MaxFee skips the fee estimation of V1 transactions
+	MaxFee *felt.Felt
+
+	// ResourceBounds skips the fee estimation of V3 transactions
+	ResourceBounds *rpc.ResourceBoundsMapping
+	// Tip of V3 transactions, defaults to 0x0
+	Tip rpc.U64
+	// PayMasterData of V3 transactions
+	PayMasterData []*felt.Felt
+	// AccountDeploymentData of V3 transactions
+	AccountDeploymentData []*felt.Felt
+	// NonceDataMode of V3 transactions, defaults to rpc.DAModeL1
+	NonceDataMode rpc.DataAvailabilityMode
+	// FeeDataMode of V3 transactions, defaults to rpc.DAModeL1
+	FeeDataMode rpc.DataAvailabilityMode
+}
+
+// ExecuteResponse is the result of Execute.
+type ExecuteResponse struct {
+	TransactionHash *felt.Felt
+	// Transaction is the signed transaction that was sent, an rpc.InvokeTxnV1 or an rpc.InvokeTxnV3
+	Transaction rpc.InvokeTxnType
+}
+
+// Execute sends the given calls in a single invoke transaction. It fetches the
+// nonce, formats the calldata for the account CairoVersion, estimates the fee
+// unless it is set in the options, signs the transaction and submits it.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// - calls: the calls to execute
+// - opts: the transaction options, nil for a V1 transaction with the default fee multiplier
+// Returns:
+// - *ExecuteResponse: the transaction hash and the transaction that was sent
+// - error: an error if any step fails
+func (account *Account) Execute(ctx context.Context, calls []rpc.FunctionCall, opts *ExecuteOptions) (*ExecuteResponse, error) {
+	if opts == nil {
+		opts = &ExecuteOptions{}
+	}
+	multiplier := opts.FeeMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultFeeMultiplier
+	}
+
+	nonce := opts.Nonce
+	if nonce == nil {
+		var err error
+		nonce, err = account.Nonce(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	calldata, err := account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Version {
+	case "", rpc.TransactionV1:
+		return account.executeV1(ctx, nonce, calldata, opts, multiplier)
+	case rpc.TransactionV3:
+		return account.executeV3(ctx, nonce, calldata, opts, multiplier)
+	}
+	return nil, ErrTxnVersionUnSupported
+}
+
+func (account *Account) executeV1(ctx context.Context, nonce *felt.Felt, calldata []*felt.Felt, opts *ExecuteOptions, multiplier float64) (*ExecuteResponse, error) {
+	tx := rpc.InvokeTxnV1{
+		MaxFee:        opts.MaxFee,
+		Version:       rpc.TransactionV1,
+		Signature:     []*felt.Felt{},
+		Nonce:         nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: account.AccountAddress,
+		Calldata:      calldata,
+	}
+	if tx.MaxFee == nil {
+		tx.MaxFee = &felt.Zero
+		estimate, err := account.estimateFee(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx})
+		if err != nil {
+			return nil, err
+		}
+		tx.MaxFee = mulFelt(estimate.OverallFee, multiplier)
+	}
+
+	if err := account.SignInvokeTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+	resp, err := account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResponse{TransactionHash: resp.TransactionHash, Transaction: tx}, nil
+}
+
+func (account *Account) executeV3(ctx context.Context, nonce *felt.Felt, calldata []*felt.Felt, opts *ExecuteOptions, multiplier float64) (*ExecuteResponse, error) {
+	tx := rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         account.AccountAddress,
+		Calldata:              calldata,
+		Version:               rpc.TransactionV3,
+		Signature:             []*felt.Felt{},
+		Nonce:                 nonce,
+		Tip:                   opts.Tip,
+		PayMasterData:         opts.PayMasterData,
+		AccountDeploymentData: opts.AccountDeploymentData,
+		NonceDataMode:         opts.NonceDataMode,
+		FeeMode:               opts.FeeDataMode,
+	}
+	if tx.Tip == "" {
+		tx.Tip = "0x0"
+	}
+	if tx.PayMasterData == nil {
+		tx.PayMasterData = []*felt.Felt{}
+	}
+	if tx.AccountDeploymentData == nil {
+		tx.AccountDeploymentData = []*felt.Felt{}
+	}
+	if tx.NonceDataMode == "" {
+		tx.NonceDataMode = rpc.DAModeL1
+	}
+	if tx.FeeMode == "" {
+		tx.FeeMode = rpc.DAModeL1
+	}
+
+	if opts.ResourceBounds != nil {
+		tx.ResourceBounds = *opts.ResourceBounds
+	} else {
+		tx.ResourceBounds = zeroResourceBounds()
+		bounds, err := account.EstimateResourceBounds(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx}, multiplier)
+		if err != nil {
+			return nil, err
+		}
+		tx.ResourceBounds = bounds
+	}
+
+	if err := account.SignInvokeTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+	resp, err := account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResponse{TransactionHash: resp.TransactionHash, Transaction: tx}, nil
+}
+
+// estimateFee estimates the fee of an unsigned transaction against the pending block.
+func (account *Account) estimateFee(ctx context.Context, tx rpc.BroadcastTxn) (*rpc.FeeEstimate, error) {
+	estimates, err := account.EstimateFee(ctx, []rpc.BroadcastTxn{tx}, []rpc.SimulationFlag{rpc.SKIP_VALIDATE}, rpc.WithBlockTag("pending"))
+	if err != nil {
+		return nil, err
+	}
+	if len(estimates) == 0 {
+		return nil, ErrNoFeeEstimate
+	}
+	return &estimates[0], nil
+}
+
+func zeroResourceBounds() rpc.ResourceBoundsMapping {
+	return rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+}
+
+// EstimateResourceBounds estimates the fee of an unsigned V3 transaction against
+// the pending block and returns the resource bounds covering it.
+//
+// Parameters:
+// - ctx: the context.Context for the request
+// - tx: the transaction to estimate, e.g. an rpc.BroadcastInvokev3Txn, rpc.BroadcastDeclareTxnV3
+// or rpc.BroadcastDeployAccountTxnV3. Its resource bounds may be zero.
+// - multiplier: the factor applied to the estimated amount and price
+// Returns:
+// - rpc.ResourceBoundsMapping: the resource bounds, see FeeEstimateToResourceBounds
+// - error: an error if the estimation fails
+func (account *Account) EstimateResourceBounds(ctx context.Context, tx rpc.BroadcastTxn, multiplier float64) (rpc.ResourceBoundsMapping, error) {
+	estimate, err := account.estimateFee(ctx, tx)
+	if err != nil {
+		return rpc.ResourceBoundsMapping{}, err
+	}
+	return FeeEstimateToResourceBounds(*estimate, multiplier), nil
+}
+
+// FeeEstimateToResourceBounds returns the resource bounds covering the
+// estimated overall fee. As the overall fee includes the L1 data gas cost of
+// the 0.7 estimates, the L1 gas amount is derived from it,
+// ceil(overall_fee / gas_price), rather than from the gas consumed. Without an
+// overall fee, the fee is computed from the L1 gas and L1 data gas amounts and prices. The amount and the price are both scaled by
+// multiplier. The L2 gas bounds are zero as L2 gas is not charged yet.
+//
+// Parameters:
+// - estimate: the fee estimate of the transaction
+// - multiplier: the factor applied to the estimated amount and price
+// Returns:
+// - rpc.ResourceBoundsMapping: the resource bounds of the transaction
+func FeeEstimateToResourceBounds(estimate rpc.FeeEstimate, multiplier float64) rpc.ResourceBoundsMapping {
+	bounds := zeroResourceBounds()
+	if estimate.GasPrice == nil || estimate.GasPrice.IsZero() {
+		if estimate.GasConsumed != nil {
+			bounds.L1Gas.MaxAmount = rpc.U64(mulFelt(estimate.GasConsumed, multiplier).String())
+		}
+		return bounds
+	}
+
+	fee := overallFee(estimate)
+	price := utils.FeltToBigInt(estimate.GasPrice)
+	// ceil(fee / price)
+	amount := new(big.Int).Add(fee, new(big.Int).Sub(price, big.NewInt(1)))
+	amount.Div(amount, price)
+	bounds.L1Gas.MaxAmount = rpc.U64(mulFelt(utils.BigIntToFelt(amount), multiplier).String())
+	bounds.L1Gas.MaxPricePerUnit = rpc.U128(mulFelt(estimate.GasPrice, multiplier).String())
+	return bounds
+}
+
+// overallFee returns the overall fee of an estimate, or the sum of its L1 gas
+// and L1 data gas costs when it is missing or lower, e.g. for the estimates of
+// the nodes rounding the overall fee down.
+func overallFee(estimate rpc.FeeEstimate) *big.Int {
+	fee := new(big.Int)
+	if estimate.GasConsumed != nil && estimate.GasPrice != nil {
+		fee.Mul(utils.FeltToBigInt(estimate.GasConsumed), utils.FeltToBigInt(estimate.GasPrice))
+	}
+	if estimate.DataGasConsumed != nil && estimate.DataGasPrice != nil {
+		fee.Add(fee, new(big.Int).Mul(utils.FeltToBigInt(estimate.DataGasConsumed), utils.FeltToBigInt(estimate.DataGasPrice)))
+	}
+	if estimate.OverallFee != nil && utils.FeltToBigInt(estimate.OverallFee).Cmp(fee) > 0 {
+		return utils.FeltToBigInt(estimate.OverallFee)
+	}
+	return fee
+}
+
+// mulFelt returns f * multiplier, rounded down.
+func mulFelt(f *felt.Felt, multiplier float64) *felt.Felt {
+	product := new(big.Float).Mul(new(big.Float).SetInt(utils.FeltToBigInt(f)), big.NewFloat(multiplier))
+	result, _ := product.Int(nil)
+	return utils.BigIntToFelt(result)
+}
@@ -0,0 +1,545 @@
+package account
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrKeyNotFound          = errors.New("Key not found in the keystore")
+	ErrKeyLocked            = errors.New("Key is locked")
+	ErrDecrypt              = errors.New("Could not decrypt key with given password")
+	ErrKeystoreUnsupported  = errors.New("Unsupported keystore format")
+	ErrPrivateKeyOutOfRange = errors.New("Private key is out of the range of the curve order")
+)
+
+const (
+	// StandardScryptN is the N parameter of scrypt, using 256MB of memory and
+	// taking approximately 1s of CPU time on a modern processor.
+	StandardScryptN = 1 << 18
+	// StandardScryptP is the P parameter of scrypt used with StandardScryptN,
+	// a single scrypt instance as in the keystores of go-ethereum.
+	StandardScryptP = 1
+	// LightScryptN is the N parameter of scrypt used by starkli, using 8MB of
+	// memory and taking approximately 30ms of CPU time on a modern processor.
+	LightScryptN = 1 << 13
+	// LightScryptP is the P parameter of scrypt used by starkli.
+	LightScryptP = 1
+
+	scryptR     = 8
+	scryptDKLen = 32
+
+	// the bounds of the KDF parameters of the decrypted keystores, so that a
+	// keystore cannot make the key derivation use unbounded memory or CPU time
+	maxScryptN       = 1 << 20
+	maxScryptR       = 32
+	maxScryptP       = 16
+	maxScryptMemory  = 1 << 30
+	maxPBKDF2Rounds  = 1 << 24
+	maxKeystoreDKLen = 64
+)
+
+// encryptedKeyJSON is the Ethereum v3 keystore format, also used by starkli.
+type encryptedKeyJSON struct {
+	Crypto  cryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts a private key with a password into a v3 keystore JSON,
+// using scrypt and AES-128-CTR.
+//
+// Parameters:
+// - key: the private key to encrypt
+// - password: the password of the keystore
+// - scryptN: the N parameter of scrypt, e.g. StandardScryptN or LightScryptN
+// - scryptP: the P parameter of scrypt, e.g. StandardScryptP or LightScryptP
+// Returns:
+// - []byte: the keystore JSON
+// - error: an error if any
+func EncryptKey(key *big.Int, password string, scryptN, scryptP int) ([]byte, error) {
+	if key.Sign() <= 0 || key.Cmp(curve.Curve.N) >= 0 {
+		return nil, ErrPrivateKeyOutOfRange
+	}
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], key.FillBytes(make([]byte, 32)), iv)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encryptedKeyJSON{
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(keystoreMAC(derivedKey, cipherText)),
+		},
+		Id:      id,
+		Version: 3,
+	})
+}
+
+// DecryptKey decrypts the private key of a v3 keystore JSON, encrypted with
+// scrypt or pbkdf2 and AES-128-CTR, e.g. a starkli keystore.
+//
+// Parameters:
+// - keyJSON: the keystore JSON
+// - password: the password of the keystore
+// Returns:
+// - *big.Int: the private key
+// - error: ErrDecrypt if the password is wrong, ErrKeystoreUnsupported if the keystore format or its
+// KDF parameters are not supported, ErrPrivateKeyOutOfRange if the key is not a Stark private key
+func DecryptKey(keyJSON []byte, password string) (*big.Int, error) {
+	var k encryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return nil, err
+	}
+	if k.Version != 3 {
+		return nil, fmt.Errorf("%w: version %d", ErrKeystoreUnsupported, k.Version)
+	}
+	if k.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("%w: cipher %s", ErrKeystoreUnsupported, k.Crypto.Cipher)
+	}
+	mac, err := hex.DecodeString(k.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(k.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := deriveKey(k.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(keystoreMAC(derivedKey, cipherText), mac) != 1 {
+		return nil, ErrDecrypt
+	}
+	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+	key := new(big.Int).SetBytes(plainText)
+	if key.Sign() <= 0 || key.Cmp(curve.Curve.N) >= 0 {
+		return nil, ErrPrivateKeyOutOfRange
+	}
+	return key, nil
+}
+
+// deriveKey derives the encryption key of a keystore from the password.
+func deriveKey(c cryptoJSON, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(fmt.Sprint(c.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+	dkLen := kdfParam(c.KDFParams, "dklen")
+	if dkLen < 32 || dkLen > maxKeystoreDKLen {
+		return nil, fmt.Errorf("%w: dklen %d", ErrKeystoreUnsupported, dkLen)
+	}
+
+	switch c.KDF {
+	case "scrypt":
+		n := kdfParam(c.KDFParams, "n")
+		r := kdfParam(c.KDFParams, "r")
+		p := kdfParam(c.KDFParams, "p")
+		if n <= 1 || n > maxScryptN || r < 1 || r > maxScryptR || p < 1 || p > maxScryptP || 128*r*n > maxScryptMemory {
+			return nil, fmt.Errorf("%w: scrypt n %d, r %d, p %d", ErrKeystoreUnsupported, n, r, p)
+		}
+		return scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case "pbkdf2":
+		if prf := fmt.Sprint(c.KDFParams["prf"]); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: prf %s", ErrKeystoreUnsupported, prf)
+		}
+		iterations := kdfParam(c.KDFParams, "c")
+		if iterations < 1 || iterations > maxPBKDF2Rounds {
+			return nil, fmt.Errorf("%w: pbkdf2 c %d", ErrKeystoreUnsupported, iterations)
+		}
+		return pbkdf2.Key([]byte(password), salt, iterations, dkLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("%w: kdf %s", ErrKeystoreUnsupported, c.KDF)
+}
+
+// kdfParam returns an integer parameter of the KDF, -1 if it is missing or
+// is not an integer of at most 32 bits.
+func kdfParam(params map[string]interface{}, name string) int {
+	// the numbers are unmarshaled as float64
+	value, ok := params[name].(float64)
+	if !ok || value < 0 || value > math.MaxInt32 || value != math.Trunc(value) {
+		return -1
+	}
+	return int(value)
+}
+
+// keystoreMAC computes the MAC of a keystore, as keccak256(derivedKey[16:32] ++ cipherText).
+func keystoreMAC(derivedKey, cipherText []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+	return hash.Sum(nil)
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: iv of %d bytes", ErrKeystoreUnsupported, len(iv))
+	}
+	outText := make([]byte, len(inText))
+	cipher.NewCTR(block, iv).XORKeyStream(outText, inText)
+	return outText, nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// newUUID generates a random (version 4) UUID.
+func newUUID() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// FileKeystore implements the Keystore interface with password-encrypted key
+// files, stored in a directory as <public key>.json in the v3 keystore format.
+// The keys must be unlocked with their password before signing.
+type FileKeystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+
+	mu       sync.Mutex
+	unlocked map[string]*unlockedKey
+}
+
+var _ Keystore = &FileKeystore{}
+
+type unlockedKey struct {
+	key   *big.Int
+	timer *time.Timer
+}
+
+// NewFileKeystore creates a FileKeystore for the key files of a directory.
+//
+// Parameters:
+// - dir: the directory of the key files, created when the first key is stored
+// - scryptN: the N parameter of scrypt for the keys stored by the keystore
+// - scryptP: the P parameter of scrypt for the keys stored by the keystore
+// Returns:
+// - *FileKeystore: a pointer to the new FileKeystore
+func NewFileKeystore(dir string, scryptN, scryptP int) *FileKeystore {
+	return &FileKeystore{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[string]*unlockedKey),
+	}
+}
+
+// Create generates a new random key and stores it encrypted with the password.
+//
+// Parameters:
+// - password: the password of the key
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: an error if any
+func (ks *FileKeystore) Create(password string) (*felt.Felt, error) {
+	key, err := curve.Curve.GetRandomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return ks.ImportKey(key, password)
+}
+
+// ImportKey stores a private key encrypted with the password.
+//
+// Parameters:
+// - key: the private key
+// - password: the password of the key
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: an error if any
+func (ks *FileKeystore) ImportKey(key *big.Int, password string) (*felt.Felt, error) {
+	keyJSON, err := EncryptKey(key, password, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := publicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pub, ks.write(pub, keyJSON)
+}
+
+// Import stores the key of a keystore JSON, e.g. a starkli keystore, encrypted
+// with a new password.
+//
+// Parameters:
+// - keyJSON: the keystore JSON
+// - password: the password of the keystore JSON
+// - newPassword: the password of the key in the keystore
+// Returns:
+// - *felt.Felt: the public key, identifying the key in the keystore
+// - error: an error if any
+func (ks *FileKeystore) Import(keyJSON []byte, password, newPassword string) (*felt.Felt, error) {
+	key, err := DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+	return ks.ImportKey(key, newPassword)
+}
+
+// Export returns the keystore JSON of a key, encrypted with a new password.
+//
+// Parameters:
+// - id: the public key of the key
+// - password: the password of the key
+// - newPassword: the password of the exported keystore JSON
+// Returns:
+// - []byte: the keystore JSON
+// - error: an error if any
+func (ks *FileKeystore) Export(id, password, newPassword string) ([]byte, error) {
+	key, err := ks.decrypt(id, password)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptKey(key, newPassword, ks.scryptN, ks.scryptP)
+}
+
+// ChangePassword encrypts a key with a new password.
+//
+// Parameters:
+// - id: the public key of the key
+// - password: the current password of the key
+// - newPassword: the new password of the key
+// Returns:
+// - error: an error if any
+func (ks *FileKeystore) ChangePassword(id, password, newPassword string) error {
+	key, err := ks.decrypt(id, password)
+	if err != nil {
+		return err
+	}
+	_, err = ks.ImportKey(key, newPassword)
+	return err
+}
+
+// Unlock decrypts a key, so that it can sign until the timeout or Lock.
+//
+// Parameters:
+// - id: the public key of the key
+// - password: the password of the key
+// - timeout: how long the key stays unlocked, until Lock if zero
+// Returns:
+// - error: an error if any
+func (ks *FileKeystore) Unlock(id, password string, timeout time.Duration) error {
+	key, err := ks.decrypt(id, password)
+	if err != nil {
+		return err
+	}
+	name, err := keyName(id)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.lock(name)
+	u := &unlockedKey{key: key}
+	if timeout > 0 {
+		u.timer = time.AfterFunc(timeout, func() {
+			ks.mu.Lock()
+			defer ks.mu.Unlock()
+			// the key may have been unlocked again in the meantime
+			if ks.unlocked[name] == u {
+				ks.lock(name)
+			}
+		})
+	}
+	ks.unlocked[name] = u
+	return nil
+}
+
+// Lock removes a decrypted key from the memory.
+//
+// Parameters:
+// - id: the public key of the key
+// Returns:
+// - error: an error if the id is not a public key
+func (ks *FileKeystore) Lock(id string) error {
+	name, err := keyName(id)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.lock(name)
+	return nil
+}
+
+// lock removes a decrypted key, ks.mu must be held.
+func (ks *FileKeystore) lock(name string) {
+	if u, ok := ks.unlocked[name]; ok {
+		if u.timer != nil {
+			u.timer.Stop()
+		}
+		u.key.SetInt64(0)
+		delete(ks.unlocked, name)
+	}
+}
+
+// Sign signs a message hash using an unlocked key of the FileKeystore.
+//
+// Parameters:
+// - ctx: the context of the operation.
+// - id: is the public key of the key.
+// - msgHash: is the message hash to be signed.
+// Returns:
+// - *big.Int: the R component of the signature as *big.Int
+// - *big.Int: the S component of the signature as *big.Int
+// - error: ErrKeyLocked if the key is not unlocked, or any other error
+func (ks *FileKeystore) Sign(ctx context.Context, id string, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	name, err := keyName(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ks.mu.Lock()
+	u, ok := ks.unlocked[name]
+	var key *big.Int
+	if ok {
+		key = new(big.Int).Set(u.key)
+	}
+	ks.mu.Unlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("error signing with key %s: %w", id, ErrKeyLocked)
+	}
+	return sign(ctx, msgHash, key)
+}
+
+// decrypt reads and decrypts a key file.
+func (ks *FileKeystore) decrypt(id, password string) (*big.Int, error) {
+	name, err := keyName(id)
+	if err != nil {
+		return nil, err
+	}
+	keyJSON, err := os.ReadFile(filepath.Join(ks.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading key %s: %w", id, ErrKeyNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKey(keyJSON, password)
+}
+
+// write atomically writes a key file, readable by the user only.
+func (ks *FileKeystore) write(pub *felt.Felt, keyJSON []byte) error {
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(ks.dir, ".tmp-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(keyJSON); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(ks.dir, pub.String()+".json"))
+}
+
+// keyName returns the file name of the key of a public key.
+func keyName(id string) (string, error) {
+	pub, err := utils.HexToFelt(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key %s: %w", id, err)
+	}
+	return pub.String() + ".json", nil
+}
+
+// publicKey returns the public key of a private key.
+func publicKey(key *big.Int) (*felt.Felt, error) {
+	pubX, _, err := curve.Curve.PrivateToPoint(key)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BigIntToFelt(pubX), nil
+}
@@ -0,0 +1,139 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typed"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// VALID is the value returned by the is_valid_signature entry point of SNIP-6 accounts
+// when the signature is valid. Older accounts return 1 instead.
+var VALID = new(felt.Felt).SetBytes([]byte("VALID"))
+
+// TypedDataHash computes the hash of the typed data message for the account.
+//
+// Parameters:
+// - td: the typed data to hash
+// Returns:
+// - *felt.Felt: the message hash, as signed by the account
+// - error: an error if the message is not set or can't be hashed
+func (account *Account) TypedDataHash(td typed.TypedData) (*felt.Felt, error) {
+	if td.Message == nil {
+		return nil, ErrTypedDataMsgNotSet
+	}
+	hash, err := td.GetMessageHash(utils.FeltToBigInt(account.AccountAddress), td.Message, curve.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BigIntToFelt(hash), nil
+}
+
+// SignTypedData signs the typed data message using the account's private key.
+//
+// Parameters:
+// - ctx: is the context used for the signing operation
+// - td: the typed data to sign
+// Returns:
+// - []*felt.Felt: the signature of the message hash
+// - error: an error, if any
+func (account *Account) SignTypedData(ctx context.Context, td typed.TypedData) ([]*felt.Felt, error) {
+	hash, err := account.TypedDataHash(td)
+	if err != nil {
+		return nil, err
+	}
+	return account.Sign(ctx, hash)
+}
+
+// VerifyTypedData verifies the signature of a typed data message for the account.
+//
+// The signature is first checked off-chain against the public key of the account.
+// If the check fails, the account contract is asked to validate the signature through
+// its is_valid_signature (or isValidSignature) entry point, so signatures of accounts
+// with custom signers are also supported.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - td: the typed data that was signed
+// - signature: the signature to verify
+// Returns:
+// - bool: true if the signature is valid, false otherwise
+// - error: an error if the message can't be hashed or the node call fails
+func (account *Account) VerifyTypedData(ctx context.Context, td typed.TypedData, signature []*felt.Felt) (bool, error) {
+	hash, err := account.TypedDataHash(td)
+	if err != nil {
+		return false, err
+	}
+	if account.verifyOffChain(hash, signature) {
+		return true, nil
+	}
+	return account.verifyOnChain(ctx, hash, signature)
+}
+
+// verifyOffChain checks a (r, s) signature against the public key of the account.
+//
+// Parameters:
+// - hash: the signed message hash
+// - signature: the signature to verify
+// Returns:
+// - bool: true if the signature is valid for the public key, false otherwise
+func (account *Account) verifyOffChain(hash *felt.Felt, signature []*felt.Felt) bool {
+	if len(signature) != 2 {
+		return false
+	}
+	pubX, ok := new(big.Int).SetString(account.publicKey, 0)
+	if !ok {
+		return false
+	}
+	pubY := curve.Curve.GetYCoordinate(pubX)
+	if pubY == nil {
+		return false
+	}
+	msgHash := utils.FeltToBigInt(hash)
+	r := utils.FeltToBigInt(signature[0])
+	s := utils.FeltToBigInt(signature[1])
+
+	// the public key only fixes x, the signature is valid for either y or -y
+	if curve.Curve.Verify(msgHash, r, s, pubX, pubY) {
+		return true
+	}
+	return curve.Curve.Verify(msgHash, r, s, pubX, new(big.Int).Sub(curve.Curve.P, pubY))
+}
+
+// verifyOnChain asks the account contract whether the signature is valid.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - hash: the signed message hash
+// - signature: the signature to verify
+// Returns:
+// - bool: true if the account accepts the signature, false otherwise
+// - error: an error if the node call fails
+func (account *Account) verifyOnChain(ctx context.Context, hash *felt.Felt, signature []*felt.Felt) (bool, error) {
+	calldata := append([]*felt.Felt{hash, new(felt.Felt).SetUint64(uint64(len(signature)))}, signature...)
+
+	for _, entryPoint := range []string{"is_valid_signature", "isValidSignature"} {
+		result, err := account.provider.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    account.AccountAddress,
+			EntryPointSelector: utils.GetSelectorFromNameFelt(entryPoint),
+			Calldata:           calldata,
+		}, rpc.WithBlockTag("latest"))
+		if err != nil {
+			// the entry point doesn't exist or the account rejects the signature
+			if errors.Is(err, rpc.ErrContractError) {
+				continue
+			}
+			return false, err
+		}
+		if len(result) == 0 {
+			return false, nil
+		}
+		return result[0].Equal(VALID) || result[0].Equal(new(felt.Felt).SetUint64(1)), nil
+	}
+	return false, nil
+}
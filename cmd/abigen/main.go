@@ -0,0 +1,48 @@
+// Command abigen generates typed Go bindings from a Cairo contract ABI.
+//
+// It accepts either a Sierra contract class or a raw ABI JSON array and can be
+// used from a go:generate directive:
+//
+//	//go:generate go run github.com/NethermindEth/starknet.go/cmd/abigen -sierra hello.sierra.json -pkg hello -type HelloStarknet -out hello.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/NethermindEth/starknet.go/abigen"
+)
+
+func main() {
+	sierra := flag.String("sierra", "", "path of the Sierra contract class (e.g. contract.sierra.json)")
+	abiPath := flag.String("abi", "", "path of a raw ABI JSON array, used instead of -sierra")
+	pkg := flag.String("pkg", "", "name of the generated Go package")
+	typ := flag.String("type", "", "name of the generated contract binding type")
+	out := flag.String("out", "", "output file, stdout if empty")
+	flag.Parse()
+
+	path := *sierra
+	if *abiPath != "" {
+		path = *abiPath
+	}
+	if path == "" || *pkg == "" || *typ == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := abigen.GenerateFromFile(path, abigen.Config{Package: *pkg, Type: *typ})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "abigen:", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "abigen:", err)
+		os.Exit(1)
+	}
+}
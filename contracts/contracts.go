@@ -2,17 +2,41 @@ package contracts
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
 
+var ErrInvalidSegmentLengths = errors.New("bytecode segment lengths do not match the bytecode")
+
 type CasmClass struct {
-	Prime            string                     `json:"prime"`
-	Version          string                     `json:"compiler_version"`
-	ByteCode         []*felt.Felt               `json:"bytecode"`
-	EntryPointByType CasmClassEntryPointsByType `json:"entry_points_by_type"`
-	// Hints            any                        `json:"hints"`
+	Prime    string       `json:"prime"`
+	Version  string       `json:"compiler_version"`
+	ByteCode []*felt.Felt `json:"bytecode"`
+	// BytecodeSegmentLengths is set by compilers from Sierra 1.5, the bytecode is then hashed by segments
+	BytecodeSegmentLengths *NestedInts                `json:"bytecode_segment_lengths,omitempty"`
+	Hints                  []Hints                    `json:"hints"`
+	PythonicHints          []PythonicHints            `json:"pythonic_hints,omitempty"`
+	EntryPointByType       CasmClassEntryPointsByType `json:"entry_points_by_type"`
+}
+
+// UnmarshalJSON unmarshals a CASM class and checks that its bytecode segment
+// lengths, if any, cover the bytecode.
+func (c *CasmClass) UnmarshalJSON(data []byte) error {
+	type casmClass CasmClass
+	var class casmClass
+	if err := json.Unmarshal(data, &class); err != nil {
+		return err
+	}
+	if class.BytecodeSegmentLengths != nil {
+		if sum, err := class.BytecodeSegmentLengths.Sum(); err != nil || sum != uint64(len(class.ByteCode)) {
+			return ErrInvalidSegmentLengths
+		}
+	}
+	*c = CasmClass(class)
+	return nil
 }
 
 type CasmClassEntryPointsByType struct {
@@ -46,3 +70,113 @@ func UnmarshalCasmClass(filePath string) (*CasmClass, error) {
 
 	return &casmClass, nil
 }
+
+// Hints are the hints run before the instruction at PC.
+// Each hint is kept as the JSON object emitted by the compiler, e.g. {"AllocSegment": {"dst": ...}}.
+type Hints struct {
+	PC    int
+	Hints []json.RawMessage
+}
+
+// MarshalJSON marshals the hints as the [pc, hints] pair of the CASM format.
+func (h Hints) MarshalJSON() ([]byte, error) {
+	hints := h.Hints
+	if hints == nil {
+		hints = []json.RawMessage{}
+	}
+	return json.Marshal([]any{h.PC, hints})
+}
+
+// UnmarshalJSON unmarshals the [pc, hints] pair of the CASM format.
+func (h *Hints) UnmarshalJSON(data []byte) error {
+	return unmarshalPCPair(data, &h.PC, &h.Hints)
+}
+
+// PythonicHints are the Python code of the hints run before the instruction at PC.
+type PythonicHints struct {
+	PC   int
+	Code []string
+}
+
+// MarshalJSON marshals the hints as the [pc, code] pair of the CASM format.
+func (h PythonicHints) MarshalJSON() ([]byte, error) {
+	code := h.Code
+	if code == nil {
+		code = []string{}
+	}
+	return json.Marshal([]any{h.PC, code})
+}
+
+// UnmarshalJSON unmarshals the [pc, code] pair of the CASM format.
+func (h *PythonicHints) UnmarshalJSON(data []byte) error {
+	return unmarshalPCPair(data, &h.PC, &h.Code)
+}
+
+func unmarshalPCPair(data []byte, pc *int, value any) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [pc, hints] pair, got %d elements", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], pc); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], value)
+}
+
+// NestedInts is a tree of integers, as used by bytecode_segment_lengths.
+// A leaf holds a Value, a node holds its Children.
+type NestedInts struct {
+	IsList   bool
+	Value    uint64
+	Children []NestedInts
+}
+
+// MarshalJSON marshals a leaf as a number and a node as an array.
+func (n NestedInts) MarshalJSON() ([]byte, error) {
+	if !n.IsList {
+		return json.Marshal(n.Value)
+	}
+	children := n.Children
+	if children == nil {
+		children = []NestedInts{}
+	}
+	return json.Marshal(children)
+}
+
+// Sum returns the sum of the values of the leaves, or ErrInvalidSegmentLengths
+// if it overflows a uint64.
+func (n NestedInts) Sum() (uint64, error) {
+	if !n.IsList {
+		return n.Value, nil
+	}
+	var sum uint64
+	for _, child := range n.Children {
+		childSum, err := child.Sum()
+		if err != nil {
+			return 0, err
+		}
+		if sum+childSum < sum {
+			return 0, ErrInvalidSegmentLengths
+		}
+		sum += childSum
+	}
+	return sum, nil
+}
+
+// UnmarshalJSON unmarshals a number into a leaf and an array into a node.
+func (n *NestedInts) UnmarshalJSON(data []byte) error {
+	var children []NestedInts
+	if err := json.Unmarshal(data, &children); err == nil {
+		*n = NestedInts{IsList: true, Children: children}
+		return nil
+	}
+	var value uint64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*n = NestedInts{Value: value}
+	return nil
+}
@@ -2,6 +2,7 @@ package contracts_test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 
@@ -50,3 +51,86 @@ func TestUnmarshalCasmClass(t *testing.T) {
 	assert.Equal(t, casmClass.EntryPointByType.External[1].Offset, 130)
 	assert.Equal(t, casmClass.EntryPointByType.External[1].Builtins[0], "range_check")
 }
+
+// TestCasmClassJSON tests that the hints and the pythonic hints of a CASM class
+// are parsed and that the class is marshaled back to the same JSON.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//   none
+func TestCasmClassJSON(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	var casmClass contracts.CasmClass
+	require.NoError(t, json.Unmarshal(content, &casmClass))
+	assert.Nil(t, casmClass.BytecodeSegmentLengths)
+
+	require.Len(t, casmClass.Hints, 15)
+	assert.Equal(t, 0, casmClass.Hints[0].PC)
+	require.Len(t, casmClass.Hints[0].Hints, 1)
+	var hint map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(casmClass.Hints[0].Hints[0], &hint))
+	assert.Contains(t, hint, "TestLessThanOrEqual")
+	assert.Equal(t, 28, casmClass.Hints[1].PC)
+
+	require.Len(t, casmClass.PythonicHints, 15)
+	assert.Equal(t, []string{"memory[ap + 0] = 22040 <= memory[fp + -6]"}, casmClass.PythonicHints[0].Code)
+
+	marshaled, err := json.Marshal(casmClass)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(content), string(marshaled))
+
+	var hints contracts.Hints
+	require.Error(t, json.Unmarshal([]byte(`[0]`), &hints))
+}
+
+// TestCasmClassSegmentLengths tests that the bytecode segment lengths of a CASM class
+// are parsed and that the lengths must cover the bytecode.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//   none
+func TestCasmClassSegmentLengths(t *testing.T) {
+	type testSetType struct {
+		Lengths         string
+		ExpectedLengths *contracts.NestedInts
+		ExpectedErr     error
+	}
+	testSet := []testSetType{
+		{
+			Lengths: `[1, [2, 1]]`,
+			ExpectedLengths: &contracts.NestedInts{IsList: true, Children: []contracts.NestedInts{
+				{Value: 1},
+				{IsList: true, Children: []contracts.NestedInts{{Value: 2}, {Value: 1}}},
+			}},
+		},
+		{Lengths: `[1, [2]]`, ExpectedErr: contracts.ErrInvalidSegmentLengths},
+		{Lengths: `[4, 1]`, ExpectedErr: contracts.ErrInvalidSegmentLengths},
+		// the sum wraps around to the bytecode length
+		{Lengths: `[18446744073709551615, 5]`, ExpectedErr: contracts.ErrInvalidSegmentLengths},
+	}
+	for _, test := range testSet {
+		content := `{"prime": "0x800000000000011000000000000000000000000000000000000000000000001", "compiler_version": "2.6.0",
+			"bytecode": ["0x1", "0x2", "0x3", "0x4"], "bytecode_segment_lengths": ` + test.Lengths + `, "hints": [],
+			"entry_points_by_type": {"EXTERNAL": [], "L1_HANDLER": [], "CONSTRUCTOR": []}}`
+
+		var casmClass contracts.CasmClass
+		err := json.Unmarshal([]byte(content), &casmClass)
+		if test.ExpectedErr != nil {
+			require.True(t, errors.Is(err, test.ExpectedErr))
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.ExpectedLengths, casmClass.BytecodeSegmentLengths)
+
+		marshaled, err := json.Marshal(casmClass)
+		require.NoError(t, err)
+		assert.JSONEq(t, content, string(marshaled))
+	}
+
+	var lengths contracts.NestedInts
+	require.Error(t, json.Unmarshal([]byte(`"10"`), &lengths))
+}
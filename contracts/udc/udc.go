@@ -0,0 +1,294 @@
+// Package udc deploys contract instances from an account through the Universal Deployer Contract.
+package udc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// Address is the address of the Universal Deployer Contract on mainnet, sepolia and devnet
+	Address, _ = new(felt.Felt).SetString("0x041a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf")
+
+	// DeployContractSelector is the selector of the deployContract entry point
+	DeployContractSelector = utils.GetSelectorFromNameFelt("deployContract")
+	// ContractDeployedSelector is the key of the ContractDeployed event
+	ContractDeployedSelector = utils.GetSelectorFromNameFelt("ContractDeployed")
+)
+
+var (
+	ErrNoDeployedEvent    = errors.New("no ContractDeployed event emitted by the deployer")
+	ErrInvalidEvent       = errors.New("invalid ContractDeployed event")
+	ErrDeploymentReverted = errors.New("deployment transaction reverted")
+	ErrAddressMismatch    = errors.New("deployed address differs from the predicted address")
+	ErrUnsupportedReceipt = errors.New("unsupported transaction receipt")
+	ErrMissingClassHash   = errors.New("class hash is required")
+	ErrMissingAccount     = errors.New("account is required")
+)
+
+// DeployOptions configures a deployment.
+type DeployOptions struct {
+	// Salt of the deployment, a random salt is used when nil
+	Salt *felt.Felt
+	// Unique derives the address from the deployer address too, so that other
+	// accounts cannot deploy a contract at the same address
+	Unique bool
+	// UDCAddress overrides the address of the Universal Deployer Contract
+	UDCAddress *felt.Felt
+}
+
+// DeployResponse is the result of Deploy.
+type DeployResponse struct {
+	TransactionHash *felt.Felt
+	// Address is the predicted address of the contract
+	Address *felt.Felt
+	Salt    *felt.Felt
+	// UDCAddress is the address of the Universal Deployer Contract, Address when nil
+	UDCAddress *felt.Felt
+}
+
+// ContractDeployed is the event emitted by the Universal Deployer Contract.
+type ContractDeployed struct {
+	Address             *felt.Felt
+	Deployer            *felt.Felt
+	Unique              bool
+	ClassHash           *felt.Felt
+	ConstructorCalldata []*felt.Felt
+	Salt                *felt.Felt
+}
+
+// DeployCall builds the call of the deployContract entry point of the Universal Deployer Contract.
+//
+// Parameters:
+// - udcAddress: the address of the Universal Deployer Contract, Address when nil
+// - classHash: the class hash of the contract to deploy
+// - salt: the salt of the deployment
+// - unique: whether the address depends on the deployer address
+// - constructorCalldata: the calldata of the constructor
+// Returns:
+// - rpc.FunctionCall: the call to execute from the deployer account
+func DeployCall(udcAddress, classHash, salt *felt.Felt, unique bool, constructorCalldata []*felt.Felt) rpc.FunctionCall {
+	if udcAddress == nil {
+		udcAddress = Address
+	}
+	calldata := make([]*felt.Felt, 0, 4+len(constructorCalldata))
+	calldata = append(calldata, classHash, salt, boolToFelt(unique), new(felt.Felt).SetUint64(uint64(len(constructorCalldata))))
+	calldata = append(calldata, constructorCalldata...)
+	return rpc.FunctionCall{
+		ContractAddress:    udcAddress,
+		EntryPointSelector: DeployContractSelector,
+		Calldata:           calldata,
+	}
+}
+
+// PrecomputeAddress predicts the address of a contract deployed through the
+// Universal Deployer Contract. Unique deployments hash the salt with the
+// deployer address and use the UDC as deployer, other deployments use a zero deployer.
+//
+// Parameters:
+// - udcAddress: the address of the Universal Deployer Contract, Address when nil
+// - deployer: the address of the account calling the UDC
+// - classHash: the class hash of the contract to deploy
+// - salt: the salt of the deployment
+// - unique: whether the address depends on the deployer address
+// - constructorCalldata: the calldata of the constructor
+// Returns:
+// - *felt.Felt: the address of the contract
+// - error: an error if the hash computation fails
+func PrecomputeAddress(udcAddress, deployer, classHash, salt *felt.Felt, unique bool, constructorCalldata []*felt.Felt) (*felt.Felt, error) {
+	if udcAddress == nil {
+		udcAddress = Address
+	}
+	deployerAddress := &felt.Zero
+	if unique {
+		uniqueSalt, err := curve.Curve.PedersenHash([]*big.Int{utils.FeltToBigInt(deployer), utils.FeltToBigInt(salt)})
+		if err != nil {
+			return nil, err
+		}
+		salt = utils.BigIntToFelt(uniqueSalt)
+		deployerAddress = udcAddress
+	}
+
+	calldataHash, err := hash.ComputeHashOnElementsFelt(constructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	return hash.ComputeHashOnElementsFelt([]*felt.Felt{
+		account.PREFIX_CONTRACT_ADDRESS,
+		deployerAddress,
+		salt,
+		classHash,
+		calldataHash,
+	})
+}
+
+// Deploy deploys a contract instance of classHash from the account through the
+// Universal Deployer Contract. The transaction is built and sent by account.Execute.
+// Use WaitForDeployment to confirm the address once the transaction is accepted.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// - acnt: the account deploying the contract
+// - classHash: the class hash of the contract to deploy, it must be declared
+// - constructorCalldata: the calldata of the constructor
+// - opts: the deployment options, nil for a random salt and a non-unique deployment
+// - executeOpts: the transaction options, see account.ExecuteOptions
+// Returns:
+// - *DeployResponse: the transaction hash, the predicted address and the salt
+// - error: an error if the transaction cannot be sent
+func Deploy(ctx context.Context, acnt *account.Account, classHash *felt.Felt, constructorCalldata []*felt.Felt, opts *DeployOptions, executeOpts *account.ExecuteOptions) (*DeployResponse, error) {
+	if acnt == nil {
+		return nil, ErrMissingAccount
+	}
+	if classHash == nil {
+		return nil, ErrMissingClassHash
+	}
+	if opts == nil {
+		opts = &DeployOptions{}
+	}
+	salt := opts.Salt
+	if salt == nil {
+		var err error
+		salt, err = new(felt.Felt).SetRandom()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if constructorCalldata == nil {
+		constructorCalldata = []*felt.Felt{}
+	}
+
+	address, err := PrecomputeAddress(opts.UDCAddress, acnt.AccountAddress, classHash, salt, opts.Unique, constructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	call := DeployCall(opts.UDCAddress, classHash, salt, opts.Unique, constructorCalldata)
+	resp, err := acnt.Execute(ctx, []rpc.FunctionCall{call}, executeOpts)
+	if err != nil {
+		return nil, err
+	}
+	udcAddress := opts.UDCAddress
+	if udcAddress == nil {
+		udcAddress = Address
+	}
+	return &DeployResponse{TransactionHash: resp.TransactionHash, Address: address, Salt: salt, UDCAddress: udcAddress}, nil
+}
+
+// WaitForDeployment waits for the receipt of a deployment and parses its
+// ContractDeployed event to confirm the address of the contract. Only the
+// events emitted by the Universal Deployer Contract are considered, so that a
+// contract called during the deployment cannot forge the event.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// - acnt: the account that sent the deployment
+// - deployment: the response of Deploy
+// - pollInterval: the interval between receipt requests
+// Returns:
+// - *ContractDeployed: the event emitted by the Universal Deployer Contract
+// - error: ErrDeploymentReverted, ErrNoDeployedEvent or ErrAddressMismatch, or the error of the requests
+func WaitForDeployment(ctx context.Context, acnt *account.Account, deployment *DeployResponse, pollInterval time.Duration) (*ContractDeployed, error) {
+	receipt, err := acnt.WaitForTransactionReceipt(ctx, deployment.TransactionHash, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if (*receipt).GetExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		return nil, ErrDeploymentReverted
+	}
+	events, err := receiptEvents(*receipt)
+	if err != nil {
+		return nil, err
+	}
+	udcAddress := deployment.UDCAddress
+	if udcAddress == nil {
+		udcAddress = Address
+	}
+	for _, event := range events {
+		if event.FromAddress == nil || !event.FromAddress.Equal(udcAddress) {
+			continue
+		}
+		if len(event.Keys) == 0 || !event.Keys[0].Equal(ContractDeployedSelector) {
+			continue
+		}
+		deployed, err := ParseContractDeployed(event)
+		if err != nil {
+			return nil, err
+		}
+		if deployment.Address != nil && !deployed.Address.Equal(deployment.Address) {
+			return deployed, fmt.Errorf("%w: expected %s, got %s", ErrAddressMismatch, deployment.Address, deployed.Address)
+		}
+		return deployed, nil
+	}
+	return nil, ErrNoDeployedEvent
+}
+
+// ParseContractDeployed parses a ContractDeployed event. Both the Cairo 0 UDC
+// layout, with every member in the data, and the Cairo 1 layout, with the
+// address as a key, are supported.
+//
+// Parameters:
+// - event: the event emitted by the Universal Deployer Contract
+// Returns:
+// - *ContractDeployed: the parsed event
+// - error: ErrInvalidEvent if the event is not a well-formed ContractDeployed event
+func ParseContractDeployed(event rpc.Event) (*ContractDeployed, error) {
+	if len(event.Keys) == 0 || !event.Keys[0].Equal(ContractDeployedSelector) {
+		return nil, ErrInvalidEvent
+	}
+	data := event.Data
+	var deployed ContractDeployed
+	switch len(event.Keys) {
+	case 1:
+		if len(data) == 0 {
+			return nil, ErrInvalidEvent
+		}
+		deployed.Address, data = data[0], data[1:]
+	case 2:
+		deployed.Address = event.Keys[1]
+	default:
+		return nil, ErrInvalidEvent
+	}
+
+	// deployer, unique, class hash, calldata length, calldata, salt
+	if len(data) < 5 {
+		return nil, ErrInvalidEvent
+	}
+	deployed.Deployer = data[0]
+	deployed.Unique = !data[1].IsZero()
+	deployed.ClassHash = data[2]
+	length := utils.FeltToBigInt(data[3])
+	if !length.IsUint64() || uint64(len(data)-5) != length.Uint64() {
+		return nil, ErrInvalidEvent
+	}
+	deployed.ConstructorCalldata = data[4 : len(data)-1]
+	deployed.Salt = data[len(data)-1]
+	return &deployed, nil
+}
+
+// receiptEvents returns the events of an invoke transaction receipt.
+func receiptEvents(receipt rpc.TransactionReceipt) ([]rpc.Event, error) {
+	switch receipt := receipt.(type) {
+	case rpc.InvokeTransactionReceipt:
+		return receipt.Events, nil
+	case rpc.PendingInvokeTransactionReceipt:
+		return receipt.Events, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedReceipt, receipt)
+}
+
+func boolToFelt(b bool) *felt.Felt {
+	if b {
+		return new(felt.Felt).SetUint64(1)
+	}
+	return new(felt.Felt)
+}
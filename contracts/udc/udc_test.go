@@ -0,0 +1,229 @@
+package udc_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts/udc"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/mocks"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+)
+
+// TestPrecomputeAddress tests the address derivation of unique and non-unique deployments.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestPrecomputeAddress(t *testing.T) {
+	deployer := utils.TestHexToFelt(t, "0x1234")
+	classHash := utils.TestHexToFelt(t, "0x2a6b3b5bcbc4d6fa5fa5b1e9e0d5d0f7bd1a6fbd6a6d3ff6dbbe4b6f1a16f4a")
+	salt := utils.TestHexToFelt(t, "0x5")
+	calldata := utils.TestHexArrToFelt(t, []string{"0x1", "0x2"})
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, deployer, "0x1", account.NewMemKeystore(), 2)
+	require.NoError(t, err)
+
+	// non-unique deployments do not depend on the deployer
+	expected, err := acnt.PrecomputeAddress(&felt.Zero, salt, classHash, calldata)
+	require.NoError(t, err)
+	address, err := udc.PrecomputeAddress(nil, deployer, classHash, salt, false, calldata)
+	require.NoError(t, err)
+	require.Equal(t, expected, address)
+	address, err = udc.PrecomputeAddress(nil, utils.TestHexToFelt(t, "0x5678"), classHash, salt, false, calldata)
+	require.NoError(t, err)
+	require.Equal(t, expected, address)
+
+	// unique deployments are deployed by the UDC with the salt hashed with the deployer
+	uniqueSalt, err := curve.Curve.PedersenHash([]*big.Int{utils.FeltToBigInt(deployer), utils.FeltToBigInt(salt)})
+	require.NoError(t, err)
+	expected, err = acnt.PrecomputeAddress(udc.Address, utils.BigIntToFelt(uniqueSalt), classHash, calldata)
+	require.NoError(t, err)
+	address, err = udc.PrecomputeAddress(nil, deployer, classHash, salt, true, calldata)
+	require.NoError(t, err)
+	require.Equal(t, expected, address)
+	other, err := udc.PrecomputeAddress(nil, utils.TestHexToFelt(t, "0x5678"), classHash, salt, true, calldata)
+	require.NoError(t, err)
+	require.NotEqual(t, address, other)
+}
+
+// TestParseContractDeployed tests parsing the ContractDeployed events of the Cairo 0 and Cairo 1 deployers.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestParseContractDeployed(t *testing.T) {
+	type testSetType struct {
+		Event       rpc.Event
+		Expected    *udc.ContractDeployed
+		ExpectedErr error
+	}
+	expected := &udc.ContractDeployed{
+		Address:             utils.TestHexToFelt(t, "0xabc"),
+		Deployer:            utils.TestHexToFelt(t, "0x1234"),
+		Unique:              true,
+		ClassHash:           utils.TestHexToFelt(t, "0xc1a55"),
+		ConstructorCalldata: utils.TestHexArrToFelt(t, []string{"0x1", "0x2"}),
+		Salt:                utils.TestHexToFelt(t, "0x5"),
+	}
+	testSet := []testSetType{
+		{
+			Event: rpc.Event{
+				FromAddress: udc.Address,
+				Keys:        []*felt.Felt{udc.ContractDeployedSelector},
+				Data:        utils.TestHexArrToFelt(t, []string{"0xabc", "0x1234", "0x1", "0xc1a55", "0x2", "0x1", "0x2", "0x5"}),
+			},
+			Expected: expected,
+		},
+		{
+			Event: rpc.Event{
+				FromAddress: udc.Address,
+				Keys:        []*felt.Felt{udc.ContractDeployedSelector, utils.TestHexToFelt(t, "0xabc")},
+				Data:        utils.TestHexArrToFelt(t, []string{"0x1234", "0x1", "0xc1a55", "0x2", "0x1", "0x2", "0x5"}),
+			},
+			Expected: expected,
+		},
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{udc.ContractDeployedSelector},
+				Data: utils.TestHexArrToFelt(t, []string{"0xabc", "0x1234", "0x1", "0xc1a55", "0x3", "0x1", "0x2", "0x5"}),
+			},
+			ExpectedErr: udc.ErrInvalidEvent,
+		},
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer")},
+				Data: utils.TestHexArrToFelt(t, []string{"0xabc", "0x1234", "0x1", "0xc1a55", "0x0", "0x5"}),
+			},
+			ExpectedErr: udc.ErrInvalidEvent,
+		},
+	}
+	for _, test := range testSet {
+		deployed, err := udc.ParseContractDeployed(test.Event)
+		if test.ExpectedErr != nil {
+			require.True(t, errors.Is(err, test.ExpectedErr))
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.Expected, deployed)
+	}
+}
+
+// TestDeployMOCK tests that Deploy sends the deployContract call through the account
+// and that WaitForDeployment confirms the predicted address from the receipt.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestDeployMOCK(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	deployer := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, deployer, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	classHash := utils.TestHexToFelt(t, "0xc1a55")
+	calldata := utils.TestHexArrToFelt(t, []string{"0x1", "0x2"})
+
+	type testSetType struct {
+		Unique          bool
+		DeployedAddress *felt.Felt
+		ExpectedErr     error
+	}
+	testSet := []testSetType{
+		{Unique: false},
+		{Unique: true},
+		{Unique: true, DeployedAddress: utils.TestHexToFelt(t, "0xdead"), ExpectedErr: udc.ErrAddressMismatch},
+	}
+	for _, test := range testSet {
+		ctx := context.Background()
+		txHash := utils.TestHexToFelt(t, "0x42")
+		mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), deployer).Return(new(felt.Felt).SetUint64(1), nil)
+		mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+				invoke, ok := tx.(rpc.BroadcastInvokev1Txn)
+				require.True(t, ok)
+				// a single call to the UDC with class hash, salt, unique and the constructor calldata
+				require.Equal(t, udc.Address, invoke.Calldata[1])
+				require.Equal(t, udc.DeployContractSelector, invoke.Calldata[2])
+				require.Equal(t, classHash, invoke.Calldata[4])
+				require.Equal(t, test.Unique, invoke.Calldata[6].IsOne())
+				require.Equal(t, calldata, invoke.Calldata[8:])
+				return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+			})
+
+		salt := utils.TestHexToFelt(t, "0x5")
+		resp, err := udc.Deploy(ctx, acnt, classHash, calldata, &udc.DeployOptions{Salt: salt, Unique: test.Unique}, &account.ExecuteOptions{MaxFee: new(felt.Felt).SetUint64(1000)})
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TransactionHash)
+		require.Equal(t, salt, resp.Salt)
+		require.Equal(t, udc.Address, resp.UDCAddress)
+		expected, err := udc.PrecomputeAddress(nil, deployer, classHash, salt, test.Unique, calldata)
+		require.NoError(t, err)
+		require.Equal(t, expected, resp.Address)
+
+		deployedAddress := test.DeployedAddress
+		if deployedAddress == nil {
+			deployedAddress = resp.Address
+		}
+		unique := new(felt.Felt)
+		if test.Unique {
+			unique.SetUint64(1)
+		}
+		mockRpcProvider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(rpc.InvokeTransactionReceipt{
+			TransactionHash: txHash,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+			Events: []rpc.Event{
+				{
+					FromAddress: utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"),
+					Keys:        []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer")},
+					Data:        utils.TestHexArrToFelt(t, []string{"0x1234", "0x1", "0x2", "0x0"}),
+				},
+				{
+					// a ContractDeployed event emitted by another contract is ignored
+					FromAddress: utils.TestHexToFelt(t, "0xbad"),
+					Keys:        []*felt.Felt{udc.ContractDeployedSelector},
+					Data:        append([]*felt.Felt{utils.TestHexToFelt(t, "0xbeef"), deployer, unique, classHash, new(felt.Felt).SetUint64(2)}, append(calldata, salt)...),
+				},
+				{
+					FromAddress: udc.Address,
+					Keys:        []*felt.Felt{udc.ContractDeployedSelector},
+					Data:        append([]*felt.Felt{deployedAddress, deployer, unique, classHash, new(felt.Felt).SetUint64(2)}, append(calldata, salt)...),
+				},
+			},
+		}, nil)
+
+		deployed, err := udc.WaitForDeployment(ctx, acnt, resp, time.Millisecond)
+		if test.ExpectedErr != nil {
+			require.True(t, errors.Is(err, test.ExpectedErr))
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, resp.Address, deployed.Address)
+		require.Equal(t, test.Unique, deployed.Unique)
+		require.Equal(t, salt, deployed.Salt)
+	}
+}
@@ -623,6 +623,19 @@ func (sc StarkCurve) PoseidonArray(felts ...*felt.Felt) *felt.Felt {
 	return junoCrypto.PoseidonArray(felts...)
 }
 
+// Poseidon computes the Poseidon hash of two felts.
+// NOTE: This function just wraps the Juno implementation
+// (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/poseidon_hash.go#L59)
+//
+// Parameters:
+// - a: a pointer to the first felt.Felt
+// - b: a pointer to the second felt.Felt
+// Returns:
+// - *felt.Felt: pointer to a felt.Felt
+func (sc StarkCurve) Poseidon(a, b *felt.Felt) *felt.Felt {
+	return junoCrypto.Poseidon(a, b)
+}
+
 // StarknetKeccak computes the Starknet Keccak hash of the given byte slice.
 // NOTE: This function just wraps the Juno implementation
 // (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/keccak.go#L11)
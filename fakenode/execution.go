@@ -0,0 +1,307 @@
+package fakenode
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	balancesVariable = utils.GetSelectorFromNameFelt("ERC20_balances")
+	transferSelector = utils.GetSelectorFromNameFelt("transfer")
+	transferEvent    = utils.GetSelectorFromNameFelt("Transfer")
+	balanceOfNames   = []*felt.Felt{utils.GetSelectorFromNameFelt("balanceOf"), utils.GetSelectorFromNameFelt("balance_of")}
+)
+
+// Execution is the execution of a transaction or of a call. The storage writes
+// and the events of a reverted transaction are discarded.
+type Execution struct {
+	state   *executionState
+	caller  *felt.Felt
+	address *felt.Felt
+}
+
+// executionState holds the changes of an execution until they are committed.
+type executionState struct {
+	node   *Node
+	writes map[felt.Felt]map[felt.Felt]*felt.Felt
+	events []rpc.Event
+}
+
+func (n *Node) newExecution(address *felt.Felt) *Execution {
+	return &Execution{
+		state:   &executionState{node: n, writes: map[felt.Felt]map[felt.Felt]*felt.Felt{}},
+		caller:  new(felt.Felt),
+		address: address,
+	}
+}
+
+// Caller returns the address of the contract calling the function, zero for the calls from outside.
+func (e *Execution) Caller() *felt.Felt {
+	return e.caller
+}
+
+// Address returns the address of the contract executing the function.
+func (e *Execution) Address() *felt.Felt {
+	return e.address
+}
+
+// StorageAt returns the value of a storage key of the contract.
+//
+// Parameters:
+// - key: the storage key
+// Returns:
+// - *felt.Felt: the value, zero if it was never written
+func (e *Execution) StorageAt(key *felt.Felt) *felt.Felt {
+	return e.storageAt(e.address, key)
+}
+
+func (e *Execution) storageAt(address, key *felt.Felt) *felt.Felt {
+	if value, ok := e.state.writes[*address][*key]; ok {
+		return value
+	}
+	if contract, ok := e.state.node.contracts[*address]; ok {
+		if value, ok := contract.storage[*key]; ok {
+			return value
+		}
+	}
+	return new(felt.Felt)
+}
+
+// SetStorageAt writes a storage key of the contract.
+//
+// Parameters:
+// - key: the storage key
+// - value: the value
+// Returns:
+//
+//	none
+func (e *Execution) SetStorageAt(key, value *felt.Felt) {
+	e.setStorageAt(e.address, key, value)
+}
+
+func (e *Execution) setStorageAt(address, key, value *felt.Felt) {
+	writes, ok := e.state.writes[*address]
+	if !ok {
+		writes = map[felt.Felt]*felt.Felt{}
+		e.state.writes[*address] = writes
+	}
+	writes[*key] = value
+}
+
+// Emit emits an event from the contract.
+//
+// Parameters:
+// - keys: the keys of the event, its selector first
+// - data: the data of the event
+// Returns:
+//
+//	none
+func (e *Execution) Emit(keys, data []*felt.Felt) {
+	e.state.events = append(e.state.events, rpc.Event{FromAddress: e.address, Keys: keys, Data: data})
+}
+
+// Call calls a function of another contract, with the contract as caller.
+//
+// Parameters:
+// - to: the address of the called contract
+// - selector: the selector of the function
+// - calldata: the arguments of the function
+// Returns:
+// - []*felt.Felt: the result of the function
+// - error: an error if the contract or its function does not exist, or if the function failed
+func (e *Execution) Call(to, selector *felt.Felt, calldata []*felt.Felt) ([]*felt.Felt, error) {
+	contract, ok := e.state.node.contracts[*to]
+	if !ok {
+		return nil, fmt.Errorf("contract not deployed at %s", to)
+	}
+	class := e.state.node.classes[*contract.classHash]
+	if class == nil || class.implementation == nil {
+		return nil, fmt.Errorf("entry point %s not found in contract %s", selector, to)
+	}
+	frame := &Execution{state: e.state, caller: e.address, address: to}
+	return class.implementation(frame, selector, calldata)
+}
+
+// commit applies the storage writes to the state of the node.
+func (e *Execution) commit() {
+	for address, writes := range e.state.writes {
+		contract := e.state.node.contracts[address]
+		for key, value := range writes {
+			contract.storage[key] = value
+		}
+	}
+}
+
+// storageDiffs returns the storage writes of the execution, ordered by address and key.
+func (e *Execution) storageDiffs() []rpc.ContractStorageDiffItem {
+	diffs := make([]rpc.ContractStorageDiffItem, 0, len(e.state.writes))
+	for address, writes := range e.state.writes {
+		address := address
+		item := rpc.ContractStorageDiffItem{Address: &address, StorageEntries: make([]rpc.StorageEntry, 0, len(writes))}
+		for key, value := range writes {
+			key := key
+			item.StorageEntries = append(item.StorageEntries, rpc.StorageEntry{Key: &key, Value: value})
+		}
+		sort.Slice(item.StorageEntries, func(i, j int) bool {
+			return item.StorageEntries[i].Key.Cmp(item.StorageEntries[j].Key) < 0
+		})
+		diffs = append(diffs, item)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Address.Cmp(diffs[j].Address) < 0 })
+	return diffs
+}
+
+// balanceKey returns the storage key of the balance of address in the fee tokens.
+func balanceKey(address *felt.Felt) *felt.Felt {
+	return crypto.Pedersen(balancesVariable, address)
+}
+
+func getUint256(storage map[felt.Felt]*felt.Felt, key *felt.Felt) *big.Int {
+	low, high := new(felt.Felt), new(felt.Felt)
+	if value, ok := storage[*key]; ok {
+		low = value
+	}
+	if value, ok := storage[*new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1))]; ok {
+		high = value
+	}
+	return joinUint256(low, high)
+}
+
+func setUint256(storage map[felt.Felt]*felt.Felt, key *felt.Felt, value *big.Int) {
+	low, high := splitUint256(value)
+	storage[*key] = low
+	storage[*new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1))] = high
+}
+
+func joinUint256(low, high *felt.Felt) *big.Int {
+	value := new(big.Int).Lsh(high.BigInt(new(big.Int)), 128)
+	return value.Add(value, low.BigInt(new(big.Int)))
+}
+
+func splitUint256(value *big.Int) (*felt.Felt, *felt.Felt) {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	low := new(big.Int).And(value, mask)
+	high := new(big.Int).Rsh(value, 128)
+	return utils.BigIntToFelt(low), utils.BigIntToFelt(high)
+}
+
+func (e *Execution) balance(address *felt.Felt) *big.Int {
+	key := balanceKey(address)
+	return joinUint256(e.StorageAt(key), e.StorageAt(new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1))))
+}
+
+func (e *Execution) setBalance(address *felt.Felt, value *big.Int) {
+	key := balanceKey(address)
+	low, high := splitUint256(value)
+	e.SetStorageAt(key, low)
+	e.SetStorageAt(new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1)), high)
+}
+
+// transfer moves amount tokens from the balance of from to the balance of to and emits a Transfer event.
+func (e *Execution) transfer(from, to *felt.Felt, amount *big.Int) error {
+	fromBalance := e.balance(from)
+	if fromBalance.Cmp(amount) < 0 {
+		return errors.New("ERC20: transfer amount exceeds balance")
+	}
+	e.setBalance(from, new(big.Int).Sub(fromBalance, amount))
+	e.setBalance(to, new(big.Int).Add(e.balance(to), amount))
+	low, high := splitUint256(amount)
+	e.Emit([]*felt.Felt{transferEvent}, []*felt.Felt{from, to, low, high})
+	return nil
+}
+
+// erc20 is the implementation of the fee tokens: balanceOf, balance_of and transfer.
+func erc20(exec *Execution, selector *felt.Felt, calldata []*felt.Felt) ([]*felt.Felt, error) {
+	for _, name := range balanceOfNames {
+		if selector.Equal(name) {
+			if len(calldata) != 1 {
+				return nil, errors.New("Failed to deserialize param #1")
+			}
+			low, high := splitUint256(exec.balance(calldata[0]))
+			return []*felt.Felt{low, high}, nil
+		}
+	}
+	if selector.Equal(transferSelector) {
+		if len(calldata) != 3 {
+			return nil, errors.New("Failed to deserialize param #2")
+		}
+		if err := exec.transfer(exec.Caller(), calldata[0], joinUint256(calldata[1], calldata[2])); err != nil {
+			return nil, err
+		}
+		return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+	}
+	return nil, fmt.Errorf("entry point %s not found in contract %s", selector, exec.Address())
+}
+
+// decodeCalls decodes the calldata of the __execute__ function of an account.
+func decodeCalls(calldata []*felt.Felt, cairoVersion int) ([]rpc.FunctionCall, error) {
+	errInvalid := errors.New("invalid calldata of __execute__")
+	next := func(i int) (uint64, error) {
+		if i >= len(calldata) || calldata[i].BigInt(new(big.Int)).BitLen() > 32 {
+			return 0, errInvalid
+		}
+		return calldata[i].BigInt(new(big.Int)).Uint64(), nil
+	}
+	count, err := next(0)
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]rpc.FunctionCall, 0, count)
+
+	if cairoVersion == 0 {
+		// call_array_len, call_array (to, selector, data_offset, data_len), calldata_len, calldata
+		dataStart := 1 + 4*int(count) + 1
+		if dataStart > len(calldata) {
+			return nil, errInvalid
+		}
+		for i := 0; i < int(count); i++ {
+			offset, err := next(1 + 4*i + 2)
+			if err != nil {
+				return nil, err
+			}
+			length, err := next(1 + 4*i + 3)
+			if err != nil {
+				return nil, err
+			}
+			start := dataStart + int(offset)
+			if start+int(length) > len(calldata) {
+				return nil, errInvalid
+			}
+			calls = append(calls, rpc.FunctionCall{
+				ContractAddress:    calldata[1+4*i],
+				EntryPointSelector: calldata[1+4*i+1],
+				Calldata:           calldata[start : start+int(length)],
+			})
+		}
+		return calls, nil
+	}
+
+	// calls_len, calls (to, selector, calldata_len, calldata)
+	i := 1
+	for len(calls) < int(count) {
+		if i+3 > len(calldata) {
+			return nil, errInvalid
+		}
+		length, err := next(i + 2)
+		if err != nil {
+			return nil, err
+		}
+		if i+3+int(length) > len(calldata) {
+			return nil, errInvalid
+		}
+		calls = append(calls, rpc.FunctionCall{
+			ContractAddress:    calldata[i],
+			EntryPointSelector: calldata[i+1],
+			Calldata:           calldata[i+3 : i+3+int(length)],
+		})
+		i += 3 + int(length)
+	}
+	return calls, nil
+}
@@ -0,0 +1,355 @@
+// Package fakenode implements an in-process Starknet node serving the JSON-RPC
+// methods used by rpc.Provider and account.Account, for tests that should not
+// depend on a devnet or on the network.
+//
+// The node serves the methods of the reads (starknet_chainId, specVersion,
+// syncing, blockNumber, blockHashAndNumber, getBlockWithTxHashes, getBlockWithTxs,
+// getBlockWithReceipts, getBlockTransactionCount, getStateUpdate,
+// getTransactionByHash, getTransactionByBlockIdAndIndex, getTransactionReceipt,
+// getTransactionStatus, getMessagesStatus, getNonce, getStorageAt, getClassHashAt,
+// getClassAt, getClass, getCompiledCasm, getStorageProof, getEvents and call), of
+// the estimations and traces (estimateFee, estimateMessageFee, simulateTransactions,
+// traceTransaction and traceBlockTransactions) and of the writes
+// (addInvokeTransaction, addDeclareTransaction and addDeployAccountTransaction).
+// The node has no L1, no CASM and no state tries: getMessagesStatus finds no
+// message, getCompiledCasm fails with a compilation error and getStorageProof is
+// not supported.
+//
+// The node keeps a simple world state: the deployed contracts with their storage
+// and nonces, the declared classes, the balances of the fee tokens, the blocks
+// and the receipts. Each accepted transaction is executed right away in a block
+// of its own. The signatures of the accounts are checked with curve.Curve.Verify.
+//
+// Contracts are not executed from their Cairo code: the accounts and the fee
+// tokens are built in, and other contracts run Go implementations registered
+// with Node.RegisterClass:
+//
+//	node := fakenode.NewNode(nil)
+//	server := httptest.NewServer(node)
+//	client, err := rpc.NewClient(server.URL)
+//	provider := rpc.NewProvider(client)
+package fakenode
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultChainID is the chain id of the nodes created without one.
+	DefaultChainID = "SN_SEPOLIA"
+	// SpecVersion is the version of the JSON-RPC specification served by the node.
+	SpecVersion = "0.6.0"
+	// StarknetVersion is the Starknet version of the blocks of the node.
+	StarknetVersion = "0.13.0"
+	// DefaultGasConsumed is the L1 gas consumed by each transaction.
+	DefaultGasConsumed = 1000
+)
+
+var (
+	// ETHAddress is the address of the ETH fee token, paying the fees of the transactions before V3.
+	ETHAddress = mustFelt("0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	// STRKAddress is the address of the STRK fee token, paying the fees of the V3 transactions.
+	STRKAddress = mustFelt("0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+	// AccountClassHash is the class hash of the built-in accounts, taking their public key as only constructor argument.
+	AccountClassHash = mustFelt("0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f")
+	// ERC20ClassHash is the class hash of the built-in fee tokens.
+	ERC20ClassHash = mustFelt("0x46ded64ae2dead6448e247234bab192a9c483644395b66f2155f2614e5804b0")
+	// SequencerAddress is the sequencer of the blocks, it receives the fees.
+	SequencerAddress = mustFelt("0x1176a1bd84444c89232ec27754698e5d2e7e1a7f1539f12027f28b23ec9f3d8")
+	// DefaultGasPrice is the price of the L1 gas in wei and in fri of the nodes created without one.
+	DefaultGasPrice = big.NewInt(100_000_000_000)
+)
+
+// builtinClass is the class returned for the classes without a declared definition.
+var builtinClass = json.RawMessage(`{"sierra_program":[],"contract_class_version":"0.1.0","entry_points_by_type":{"CONSTRUCTOR":[],"EXTERNAL":[],"L1_HANDLER":[]},"abi":"[]"}`)
+
+// Contract is the Go implementation of the external functions of a contract class.
+// It returns the result of the function, or an error reverting the transaction.
+type Contract func(exec *Execution, selector *felt.Felt, calldata []*felt.Felt) ([]*felt.Felt, error)
+
+// Options are the options of a Node.
+type Options struct {
+	// ChainID is the chain id of the node, DefaultChainID if empty
+	ChainID string
+	// GasPrice is the price of the L1 gas in wei and in fri, DefaultGasPrice if nil
+	GasPrice *big.Int
+	// GasConsumed is the L1 gas consumed by each transaction, DefaultGasConsumed if 0
+	GasConsumed uint64
+	// Now returns the timestamp of the blocks, time.Now if nil
+	Now func() time.Time
+}
+
+// contract is a deployed contract.
+type contract struct {
+	classHash *felt.Felt
+	nonce     *felt.Felt
+	storage   map[felt.Felt]*felt.Felt
+	// publicKey is set for the accounts
+	publicKey    *felt.Felt
+	cairoVersion int
+}
+
+type class struct {
+	definition     json.RawMessage
+	implementation Contract
+}
+
+// transaction is an accepted transaction with its receipt.
+type transaction struct {
+	hash        *felt.Felt
+	kind        rpc.TransactionType
+	raw         json.RawMessage
+	receipt     rpc.TransactionReceipt
+	blockNumber uint64
+	events      []rpc.Event
+	// revertReason is the error reverting the execution, empty if it succeeded
+	revertReason string
+	// stateDiff is the change of the state applied by the transaction
+	stateDiff rpc.StateDiff
+}
+
+type block struct {
+	header       rpc.BlockHeader
+	transactions []*transaction
+}
+
+// Node is an in-process Starknet node. It is an http.Handler serving the
+// starknet_* JSON-RPC methods.
+type Node struct {
+	chainID     string
+	gasPrice    *big.Int
+	gasConsumed uint64
+	now         func() time.Time
+	server      *ethrpc.Server
+	// hasher computes the transaction hashes on the chain of the node
+	hasher *account.Account
+
+	mu           sync.Mutex
+	contracts    map[felt.Felt]*contract
+	classes      map[felt.Felt]*class
+	blocks       []*block
+	transactions map[felt.Felt]*transaction
+}
+
+// NewNode creates a new Node with the fee tokens deployed and a genesis block.
+//
+// Parameters:
+// - opts: the options of the node, the defaults if nil
+// Returns:
+// - *Node: a new Node
+func NewNode(opts *Options) *Node {
+	if opts == nil {
+		opts = &Options{}
+	}
+	node := &Node{
+		chainID:      opts.ChainID,
+		gasPrice:     opts.GasPrice,
+		gasConsumed:  opts.GasConsumed,
+		now:          opts.Now,
+		contracts:    map[felt.Felt]*contract{},
+		classes:      map[felt.Felt]*class{},
+		transactions: map[felt.Felt]*transaction{},
+	}
+	if node.chainID == "" {
+		node.chainID = DefaultChainID
+	}
+	if node.gasPrice == nil {
+		node.gasPrice = DefaultGasPrice
+	}
+	if node.gasConsumed == 0 {
+		node.gasConsumed = DefaultGasConsumed
+	}
+	if node.now == nil {
+		node.now = time.Now
+	}
+	node.hasher = &account.Account{ChainId: new(felt.Felt).SetBytes([]byte(node.chainID))}
+
+	node.classes[*AccountClassHash] = &class{definition: builtinClass}
+	node.classes[*ERC20ClassHash] = &class{definition: builtinClass, implementation: erc20}
+	node.contracts[*ETHAddress] = newContract(ERC20ClassHash)
+	node.contracts[*STRKAddress] = newContract(ERC20ClassHash)
+	node.addBlock(nil)
+
+	node.server = ethrpc.NewServer()
+	if err := node.server.RegisterName("starknet", &service{node: node}); err != nil {
+		panic(err)
+	}
+	return node
+}
+
+func newContract(classHash *felt.Felt) *contract {
+	return &contract{classHash: classHash, nonce: new(felt.Felt), storage: map[felt.Felt]*felt.Felt{}}
+}
+
+// ServeHTTP serves the JSON-RPC requests.
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.server.ServeHTTP(w, r)
+}
+
+// ChainID returns the chain id of the node.
+func (n *Node) ChainID() string {
+	return n.chainID
+}
+
+// AddAccount deploys an account with the built-in account class, without a transaction.
+//
+// Parameters:
+// - address: the address of the account
+// - publicKey: the public key checking the signatures of the account
+// - cairoVersion: the Cairo version of the account, 0 or 2, defining the format of its calldata
+// Returns:
+// - error: an error if a contract is already deployed at the address
+func (n *Node) AddAccount(address, publicKey *felt.Felt, cairoVersion int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.contracts[*address]; ok {
+		return errors.New("contract already deployed at " + address.String())
+	}
+	account := newContract(AccountClassHash)
+	account.publicKey = publicKey
+	account.cairoVersion = cairoVersion
+	n.contracts[*address] = account
+	return nil
+}
+
+// RegisterClass declares a class with the Go implementation of its functions, without a transaction.
+//
+// Parameters:
+// - classHash: the hash of the class
+// - definition: the class returned by starknet_getClass, a class without entry points if nil
+// - implementation: the implementation of the functions of the contracts of the class
+// Returns:
+//
+//	none
+func (n *Node) RegisterClass(classHash *felt.Felt, definition json.RawMessage, implementation Contract) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if definition == nil {
+		definition = builtinClass
+	}
+	n.classes[*classHash] = &class{definition: definition, implementation: implementation}
+}
+
+// Deploy deploys a contract of a declared class, without a transaction.
+//
+// Parameters:
+// - address: the address of the contract
+// - classHash: the hash of the class of the contract
+// - storage: the initial storage of the contract
+// Returns:
+// - error: an error if the class is not declared or a contract is already deployed at the address
+func (n *Node) Deploy(address, classHash *felt.Felt, storage map[felt.Felt]*felt.Felt) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.classes[*classHash]; !ok {
+		return errors.New("class not declared " + classHash.String())
+	}
+	if _, ok := n.contracts[*address]; ok {
+		return errors.New("contract already deployed at " + address.String())
+	}
+	contract := newContract(classHash)
+	for key, value := range storage {
+		contract.storage[key] = value
+	}
+	n.contracts[*address] = contract
+	return nil
+}
+
+// Mint adds fee tokens to the balance of an address, without a transaction.
+//
+// Parameters:
+// - address: the address receiving the tokens
+// - amount: the amount of tokens
+// - unit: rpc.UnitWei for ETH, rpc.UnitStrk for STRK
+// Returns:
+//
+//	none
+func (n *Node) Mint(address *felt.Felt, amount *big.Int, unit rpc.FeePaymentUnit) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	token := n.contracts[*feeToken(unit)]
+	balance := getUint256(token.storage, balanceKey(address))
+	setUint256(token.storage, balanceKey(address), balance.Add(balance, amount))
+}
+
+// Balance returns the fee token balance of an address.
+//
+// Parameters:
+// - address: the address
+// - unit: rpc.UnitWei for ETH, rpc.UnitStrk for STRK
+// Returns:
+// - *big.Int: the balance
+func (n *Node) Balance(address *felt.Felt, unit rpc.FeePaymentUnit) *big.Int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return getUint256(n.contracts[*feeToken(unit)].storage, balanceKey(address))
+}
+
+// feeToken returns the address of the token paying the fees in unit.
+func feeToken(unit rpc.FeePaymentUnit) *felt.Felt {
+	if unit == rpc.UnitStrk {
+		return STRKAddress
+	}
+	return ETHAddress
+}
+
+// addBlock adds a block with the transactions. The caller holds the lock, except in NewNode.
+func (n *Node) addBlock(transactions []*transaction) *block {
+	parentHash := new(felt.Felt)
+	number := uint64(len(n.blocks))
+	if number > 0 {
+		parentHash = n.blocks[number-1].header.BlockHash
+	}
+	hashes := []*felt.Felt{new(felt.Felt).SetUint64(number), parentHash}
+	for _, tx := range transactions {
+		hashes = append(hashes, tx.hash)
+	}
+	b := &block{
+		header: rpc.BlockHeader{
+			BlockHash:        crypto.PoseidonArray(hashes...),
+			ParentHash:       parentHash,
+			BlockNumber:      number,
+			NewRoot:          new(felt.Felt),
+			Timestamp:        uint64(n.now().Unix()),
+			SequencerAddress: SequencerAddress,
+			L1GasPrice:       n.resourcePrice(),
+			StarknetVersion:  StarknetVersion,
+		},
+		transactions: transactions,
+	}
+	for _, tx := range transactions {
+		tx.blockNumber = number
+	}
+	n.blocks = append(n.blocks, b)
+	return b
+}
+
+func (n *Node) resourcePrice() rpc.ResourcePrice {
+	price := utils.BigIntToFelt(n.gasPrice)
+	return rpc.ResourcePrice{PriceInFRI: price, PriceInWei: price}
+}
+
+// fee returns the fee of a transaction.
+func (n *Node) fee() *big.Int {
+	return new(big.Int).Mul(n.gasPrice, new(big.Int).SetUint64(n.gasConsumed))
+}
+
+func mustFelt(hex string) *felt.Felt {
+	f, err := new(felt.Felt).SetString(hex)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
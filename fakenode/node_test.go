@@ -0,0 +1,296 @@
+package fakenode_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/fakenode"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+)
+
+// newKeyAccount creates an account at address with a random key.
+func newKeyAccount(t *testing.T, provider *rpc.Provider, address *felt.Felt) (*account.Account, *felt.Felt) {
+	privateKey, err := curve.Curve.GetRandomPrivateKey()
+	require.NoError(t, err)
+	publicKeyX, _, err := curve.Curve.PrivateToPoint(privateKey)
+	require.NoError(t, err)
+	publicKey := utils.BigIntToFelt(publicKeyX)
+	acnt, err := account.NewAccount(provider, address, publicKey.String(), account.SetNewMemKeystore(publicKey.String(), privateKey), 2)
+	require.NoError(t, err)
+	return acnt, publicKey
+}
+
+// newTestAccount deploys an account on the node with a deploy account transaction.
+func newTestAccount(t *testing.T, node *fakenode.Node, provider *rpc.Provider) *account.Account {
+	acnt, publicKey := newKeyAccount(t, provider, new(felt.Felt))
+
+	salt := new(felt.Felt).SetUint64(42)
+	address, err := acnt.PrecomputeAddress(new(felt.Felt), salt, fakenode.AccountClassHash, []*felt.Felt{publicKey})
+	require.NoError(t, err)
+	node.Mint(address, big.NewInt(1_000_000_000_000_000_000), rpc.UnitWei)
+
+	tx := rpc.BroadcastDeployAccountTxn{DeployAccountTxn: rpc.DeployAccountTxn{
+		MaxFee:              new(felt.Felt).SetUint64(1_000_000_000_000_000),
+		Version:             rpc.TransactionV1,
+		Nonce:               new(felt.Felt),
+		Type:                rpc.TransactionType_DeployAccount,
+		ClassHash:           fakenode.AccountClassHash,
+		ContractAddressSalt: salt,
+		ConstructorCalldata: []*felt.Felt{publicKey},
+	}}
+	require.NoError(t, acnt.SignDeployAccountTransaction(context.Background(), &tx, address))
+	resp, err := acnt.AddDeployAccountTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	require.Equal(t, address, resp.ContractAddress)
+
+	acnt.AccountAddress = address
+	return acnt
+}
+
+// invoke signs and sends an invoke transaction of the account.
+func invoke(t *testing.T, acnt *account.Account, nonce uint64, calls ...rpc.FunctionCall) (*felt.Felt, error) {
+	calldata, err := acnt.FmtCalldata(calls)
+	require.NoError(t, err)
+	tx := rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{
+		MaxFee:        new(felt.Felt).SetUint64(1_000_000_000_000_000),
+		Version:       rpc.TransactionV1,
+		Nonce:         new(felt.Felt).SetUint64(nonce),
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: acnt.AccountAddress,
+		Calldata:      calldata,
+	}}
+	require.NoError(t, acnt.SignInvokeTransaction(context.Background(), &tx))
+	resp, err := acnt.AddInvokeTransaction(context.Background(), tx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.TransactionHash, nil
+}
+
+func transfer(to *felt.Felt, amount uint64) rpc.FunctionCall {
+	return rpc.FunctionCall{
+		ContractAddress:    fakenode.ETHAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{to, new(felt.Felt).SetUint64(amount), new(felt.Felt)},
+	}
+}
+
+func errorCode(err error) int {
+	var codeErr interface{ ErrorCode() int }
+	if errors.As(err, &codeErr) {
+		return codeErr.ErrorCode()
+	}
+	return 0
+}
+
+// TestNode tests an account deploying itself and sending transactions to the node.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestNode(t *testing.T) {
+	node := fakenode.NewNode(nil)
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	client, err := rpc.NewClient(server.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	provider := rpc.NewProvider(client)
+	ctx := context.Background()
+
+	chainID, err := provider.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fakenode.DefaultChainID, chainID)
+
+	acnt := newTestAccount(t, node, provider)
+	fee := new(big.Int).Mul(fakenode.DefaultGasPrice, big.NewInt(fakenode.DefaultGasConsumed))
+	initialBalance := new(big.Int).Sub(big.NewInt(1_000_000_000_000_000_000), fee)
+	require.Equal(t, initialBalance, node.Balance(acnt.AccountAddress, rpc.UnitWei))
+
+	recipient := utils.TestHexToFelt(t, "0x1234")
+	t.Run("invoke", func(t *testing.T) {
+		hash, err := invoke(t, acnt, 1, transfer(recipient, 1000))
+		require.NoError(t, err)
+
+		receipt, err := provider.TransactionReceipt(ctx, hash)
+		require.NoError(t, err)
+		invokeReceipt, ok := receipt.(rpc.InvokeTransactionReceipt)
+		require.True(t, ok)
+		require.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, invokeReceipt.ExecutionStatus)
+		require.Equal(t, utils.BigIntToFelt(fee), invokeReceipt.ActualFee.Amount)
+		require.Len(t, invokeReceipt.Events, 2)
+		require.Equal(t, []*felt.Felt{acnt.AccountAddress, recipient, new(felt.Felt).SetUint64(1000), new(felt.Felt)}, invokeReceipt.Events[0].Data)
+		require.Equal(t, fakenode.SequencerAddress, invokeReceipt.Events[1].Data[1])
+
+		balance, err := provider.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    fakenode.ETHAddress,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
+			Calldata:           []*felt.Felt{recipient},
+		}, rpc.WithBlockTag("latest"))
+		require.NoError(t, err)
+		require.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(1000), new(felt.Felt)}, balance)
+
+		nonce, err := provider.Nonce(ctx, rpc.WithBlockTag("latest"), acnt.AccountAddress)
+		require.NoError(t, err)
+		require.Equal(t, new(felt.Felt).SetUint64(2), nonce)
+
+		block, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockTag("latest"))
+		require.NoError(t, err)
+		require.Equal(t, invokeReceipt.BlockHash, block.BlockHash())
+		require.Equal(t, []*felt.Felt{hash}, block.Block.Transactions)
+	})
+
+	var revertedHash *felt.Felt
+	t.Run("revert", func(t *testing.T) {
+		balance := node.Balance(acnt.AccountAddress, rpc.UnitWei)
+		hash, err := invoke(t, acnt, 2, transfer(recipient, 1000), transfer(recipient, balance.Uint64()))
+		require.NoError(t, err)
+
+		status, err := provider.GetTransactionStatus(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, rpc.TxnExecutionStatusREVERTED, status.ExecutionStatus)
+		receipt, err := provider.TransactionReceipt(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, "ERC20: transfer amount exceeds balance", receipt.(rpc.InvokeTransactionReceipt).RevertReason)
+		// the fee is charged and the first transfer is reverted
+		require.Equal(t, new(big.Int).Sub(balance, fee), node.Balance(acnt.AccountAddress, rpc.UnitWei))
+		require.Equal(t, big.NewInt(1000), node.Balance(recipient, rpc.UnitWei))
+		revertedHash = hash
+	})
+
+	t.Run("state update and traces", func(t *testing.T) {
+		latest := rpc.WithBlockTag("latest")
+		update, err := provider.StateUpdate(ctx, latest)
+		require.NoError(t, err)
+		require.Equal(t, []rpc.ContractNonce{{ContractAddress: acnt.AccountAddress, Nonce: new(felt.Felt).SetUint64(3)}}, update.StateDiff.Nonces)
+		// the transfers are reverted, only the fee is charged
+		require.Len(t, update.StateDiff.StorageDiffs, 1)
+		require.Equal(t, fakenode.ETHAddress, update.StateDiff.StorageDiffs[0].Address)
+
+		block, err := provider.BlockWithReceipts(ctx, latest)
+		require.NoError(t, err)
+		require.Equal(t, update.BlockHash, block.BlockHash())
+		require.Len(t, block.Block.Transactions, 1)
+		require.Equal(t, revertedHash, block.Block.Transactions[0].Receipt.Hash())
+
+		trace, err := provider.TraceTransaction(ctx, revertedHash)
+		require.NoError(t, err)
+		invokeTrace, ok := trace.(rpc.InvokeTxnTrace)
+		require.True(t, ok)
+		require.Equal(t, "ERC20: transfer amount exceeds balance", invokeTrace.ExecuteInvocation.RevertReason)
+		require.Equal(t, update.StateDiff.Nonces, invokeTrace.StateDiff.Nonces)
+
+		traces, err := provider.TraceBlockTransactions(ctx, latest)
+		require.NoError(t, err)
+		require.Len(t, traces, 1)
+		require.Equal(t, revertedHash, traces[0].TxnHash)
+
+		_, err = provider.TraceTransaction(ctx, new(felt.Felt).SetUint64(1))
+		require.True(t, errors.Is(err, rpc.ErrHashNotFound))
+
+		deployUpdate, err := provider.StateUpdate(ctx, rpc.WithBlockNumber(1))
+		require.NoError(t, err)
+		require.Equal(t, []rpc.DeployedContractItem{{Address: acnt.AccountAddress, ClassHash: fakenode.AccountClassHash}}, deployUpdate.StateDiff.DeployedContracts)
+	})
+
+	t.Run("estimations", func(t *testing.T) {
+		simulations, err := provider.SimulateTransactions(ctx, rpc.WithBlockTag("latest"), []rpc.Transaction{rpc.InvokeTxnV1{
+			MaxFee:        new(felt.Felt).SetUint64(1_000_000_000_000_000),
+			Version:       rpc.TransactionV1,
+			Nonce:         new(felt.Felt).SetUint64(3),
+			Type:          rpc.TransactionType_Invoke,
+			SenderAddress: acnt.AccountAddress,
+			Calldata:      []*felt.Felt{},
+			Signature:     []*felt.Felt{},
+		}}, nil)
+		require.NoError(t, err)
+		require.Len(t, simulations, 1)
+		require.Equal(t, utils.BigIntToFelt(fee), simulations[0].OverallFee)
+		require.Equal(t, rpc.UnitWei, simulations[0].FeeUnit)
+
+		message := rpc.MsgFromL1{
+			FromAddress: "0x8453fc6cd1bcfe8d4dfc069c400b433054d47bdc",
+			ToAddress:   fakenode.ETHAddress,
+			Selector:    utils.GetSelectorFromNameFelt("handle_deposit"),
+			Payload:     []*felt.Felt{},
+		}
+		estimate, err := provider.EstimateMessageFee(ctx, message, rpc.WithBlockTag("latest"))
+		require.NoError(t, err)
+		require.Equal(t, utils.BigIntToFelt(fee), estimate.OverallFee)
+
+		message.ToAddress = recipient
+		_, err = provider.EstimateMessageFee(ctx, message, rpc.WithBlockTag("latest"))
+		require.True(t, errors.Is(err, rpc.ErrContractNotFound))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := provider.GetMessagesStatus(ctx, "0x1")
+		require.True(t, errors.Is(err, rpc.ErrHashNotFound))
+
+		_, err = provider.CompiledCasm(ctx, fakenode.AccountClassHash)
+		require.True(t, errors.Is(err, rpc.ErrCompilationError))
+		_, err = provider.CompiledCasm(ctx, new(felt.Felt).SetUint64(1))
+		require.True(t, errors.Is(err, rpc.ErrClassHashNotFound))
+
+		_, err = provider.GetStorageProof(ctx, rpc.StorageProofInput{BlockID: rpc.WithBlockTag("latest")})
+		require.True(t, errors.Is(err, rpc.ErrStorageProofNotSupported))
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		_, err := invoke(t, acnt, 2, transfer(recipient, 1))
+		require.Equal(t, rpc.ErrInvalidTransactionNonce.Code(), errorCode(err))
+
+		other, _ := newKeyAccount(t, provider, acnt.AccountAddress)
+		_, err = invoke(t, other, 3, transfer(recipient, 1))
+		require.Equal(t, rpc.ErrValidationFailure.Code(), errorCode(err))
+
+		_, err = provider.TransactionReceipt(ctx, new(felt.Felt).SetUint64(1))
+		require.Equal(t, rpc.ErrHashNotFound.Code(), errorCode(err))
+
+		// a class that is not an account can't be deployed as one
+		tx := rpc.BroadcastDeployAccountTxn{DeployAccountTxn: rpc.DeployAccountTxn{
+			MaxFee:              new(felt.Felt).SetUint64(1_000_000_000_000_000),
+			Version:             rpc.TransactionV1,
+			Nonce:               new(felt.Felt),
+			Type:                rpc.TransactionType_DeployAccount,
+			ClassHash:           fakenode.ERC20ClassHash,
+			ContractAddressSalt: new(felt.Felt).SetUint64(43),
+			ConstructorCalldata: []*felt.Felt{new(felt.Felt).SetUint64(1)},
+		}}
+		_, err = acnt.AddDeployAccountTransaction(ctx, tx)
+		require.Equal(t, rpc.ErrValidationFailure.Code(), errorCode(err))
+	})
+
+	t.Run("events", func(t *testing.T) {
+		input := rpc.EventsInput{
+			EventFilter: rpc.EventFilter{
+				FromBlock: rpc.WithBlockNumber(0),
+				ToBlock:   rpc.WithBlockTag("latest"),
+				Address:   fakenode.ETHAddress,
+				Keys:      [][]*felt.Felt{{utils.GetSelectorFromNameFelt("Transfer")}},
+			},
+			ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 2},
+		}
+		chunk, err := provider.Events(ctx, input)
+		require.NoError(t, err)
+		require.Len(t, chunk.Events, 2)
+		require.NotEmpty(t, chunk.ContinuationToken)
+
+		input.ContinuationToken = chunk.ContinuationToken
+		chunk, err = provider.Events(ctx, input)
+		require.NoError(t, err)
+		// deploy account fee, invoke transfer and fee, reverted invoke fee
+		require.Len(t, chunk.Events, 2)
+		require.Empty(t, chunk.ContinuationToken)
+	})
+}
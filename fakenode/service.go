@@ -0,0 +1,616 @@
+package fakenode
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// MaxEventChunkSize is the largest chunk_size accepted by starknet_getEvents.
+const MaxEventChunkSize = 1024
+
+var invalidParams = rpc.Err(rpc.InvalidParams, nil)
+
+// nodeError is an error returned with its JSON-RPC code and data.
+type nodeError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func newError(err *rpc.RPCError, data interface{}) error {
+	return &nodeError{code: err.Code(), message: err.Error(), data: data}
+}
+
+func (e *nodeError) Error() string          { return e.message }
+func (e *nodeError) ErrorCode() int         { return e.code }
+func (e *nodeError) ErrorData() interface{} { return e.data }
+
+// service implements the starknet_* JSON-RPC methods.
+type service struct {
+	node *Node
+}
+
+// blockRef is a block_id: a tag, a block number or a block hash.
+type blockRef struct {
+	pending bool
+	block   *block
+}
+
+// resolveBlock returns the block of a block_id. The caller holds the lock.
+func (n *Node) resolveBlock(raw json.RawMessage) (blockRef, error) {
+	latest := n.blocks[len(n.blocks)-1]
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var tag string
+		if err := json.Unmarshal(raw, &tag); err != nil {
+			return blockRef{}, newError(invalidParams, err.Error())
+		}
+		switch tag {
+		case "latest":
+			return blockRef{block: latest}, nil
+		case "pending":
+			return blockRef{pending: true, block: latest}, nil
+		}
+		return blockRef{}, newError(invalidParams, "invalid block tag "+tag)
+	}
+	var id struct {
+		Number *uint64    `json:"block_number"`
+		Hash   *felt.Felt `json:"block_hash"`
+	}
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return blockRef{}, newError(invalidParams, err.Error())
+	}
+	switch {
+	case id.Number != nil:
+		if *id.Number < uint64(len(n.blocks)) {
+			return blockRef{block: n.blocks[*id.Number]}, nil
+		}
+	case id.Hash != nil:
+		for _, b := range n.blocks {
+			if b.header.BlockHash.Equal(id.Hash) {
+				return blockRef{block: b}, nil
+			}
+		}
+	default:
+		return blockRef{}, newError(invalidParams, "invalid block id")
+	}
+	return blockRef{}, newError(rpc.ErrBlockNotFound, nil)
+}
+
+// pendingHeader returns the header of the pending block. The caller holds the lock.
+func (n *Node) pendingHeader() rpc.PendingBlockHeader {
+	return rpc.PendingBlockHeader{
+		ParentHash:       n.blocks[len(n.blocks)-1].header.BlockHash,
+		Timestamp:        uint64(n.now().Unix()),
+		SequencerAddress: SequencerAddress,
+		L1GasPrice:       n.resourcePrice(),
+		StarknetVersion:  StarknetVersion,
+	}
+}
+
+// contractAt returns the contract deployed at address, checking the block_id. The caller holds the lock.
+// The state is not versioned: every block sees the latest state.
+func (n *Node) contractAt(blockID json.RawMessage, address *felt.Felt) (*contract, error) {
+	if _, err := n.resolveBlock(blockID); err != nil {
+		return nil, err
+	}
+	contract, ok := n.contracts[*address]
+	if !ok {
+		return nil, newError(rpc.ErrContractNotFound, nil)
+	}
+	return contract, nil
+}
+
+func (s *service) ChainId() string {
+	return new(felt.Felt).SetBytes([]byte(s.node.chainID)).String()
+}
+
+func (s *service) SpecVersion() string {
+	return SpecVersion
+}
+
+func (s *service) Syncing() bool {
+	return false
+}
+
+func (s *service) BlockNumber() uint64 {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	return uint64(len(s.node.blocks) - 1)
+}
+
+func (s *service) BlockHashAndNumber() *rpc.BlockHashAndNumberOutput {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	latest := s.node.blocks[len(s.node.blocks)-1]
+	return &rpc.BlockHashAndNumberOutput{BlockNumber: latest.header.BlockNumber, BlockHash: latest.header.BlockHash}
+}
+
+func (s *service) GetBlockWithTxHashes(blockID json.RawMessage) (interface{}, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if ref.pending {
+		return rpc.PendingBlockTxHashes{PendingBlockHeader: s.node.pendingHeader(), Transactions: []*felt.Felt{}}, nil
+	}
+	hashes := make([]*felt.Felt, len(ref.block.transactions))
+	for i, tx := range ref.block.transactions {
+		hashes[i] = tx.hash
+	}
+	return rpc.BlockTxHashes{BlockHeader: ref.block.header, Status: rpc.BlockStatus_AcceptedOnL2, Transactions: hashes}, nil
+}
+
+func (s *service) GetBlockWithTxs(blockID json.RawMessage) (interface{}, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if ref.pending {
+		return struct {
+			rpc.PendingBlockHeader
+			Transactions []json.RawMessage `json:"transactions"`
+		}{s.node.pendingHeader(), []json.RawMessage{}}, nil
+	}
+	transactions := make([]json.RawMessage, len(ref.block.transactions))
+	for i, tx := range ref.block.transactions {
+		transactions[i] = tx.raw
+	}
+	return struct {
+		rpc.BlockHeader
+		Status       rpc.BlockStatus   `json:"status"`
+		Transactions []json.RawMessage `json:"transactions"`
+	}{ref.block.header, rpc.BlockStatus_AcceptedOnL2, transactions}, nil
+}
+
+func (s *service) GetBlockWithReceipts(blockID json.RawMessage) (interface{}, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	type transactionWithReceipt struct {
+		Transaction json.RawMessage        `json:"transaction"`
+		Receipt     rpc.TransactionReceipt `json:"receipt"`
+	}
+	if ref.pending {
+		return struct {
+			rpc.PendingBlockHeader
+			Transactions []transactionWithReceipt `json:"transactions"`
+		}{s.node.pendingHeader(), []transactionWithReceipt{}}, nil
+	}
+	transactions := make([]transactionWithReceipt, len(ref.block.transactions))
+	for i, tx := range ref.block.transactions {
+		transactions[i] = transactionWithReceipt{Transaction: tx.raw, Receipt: tx.receipt}
+	}
+	return struct {
+		rpc.BlockHeader
+		Status       rpc.BlockStatus          `json:"status"`
+		Transactions []transactionWithReceipt `json:"transactions"`
+	}{ref.block.header, rpc.BlockStatus_AcceptedOnL2, transactions}, nil
+}
+
+// GetStateUpdate returns the state diff of the transactions of a block. The
+// state roots are zero: the node has no state tries.
+func (s *service) GetStateUpdate(blockID json.RawMessage) (interface{}, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if ref.pending {
+		return rpc.PendingStateUpdate{OldRoot: ref.block.header.NewRoot, StateDiff: emptyStateDiff()}, nil
+	}
+	stateDiff := emptyStateDiff()
+	for _, tx := range ref.block.transactions {
+		stateDiff.StorageDiffs = append(stateDiff.StorageDiffs, tx.stateDiff.StorageDiffs...)
+		stateDiff.DeclaredClasses = append(stateDiff.DeclaredClasses, tx.stateDiff.DeclaredClasses...)
+		stateDiff.DeployedContracts = append(stateDiff.DeployedContracts, tx.stateDiff.DeployedContracts...)
+		stateDiff.Nonces = append(stateDiff.Nonces, tx.stateDiff.Nonces...)
+	}
+	return rpc.StateUpdateOutput{
+		BlockHash:          ref.block.header.BlockHash,
+		NewRoot:            ref.block.header.NewRoot,
+		PendingStateUpdate: rpc.PendingStateUpdate{OldRoot: new(felt.Felt), StateDiff: stateDiff},
+	}, nil
+}
+
+func (s *service) GetBlockTransactionCount(blockID json.RawMessage) (uint64, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil || ref.pending {
+		return 0, err
+	}
+	return uint64(len(ref.block.transactions)), nil
+}
+
+func (s *service) GetTransactionByHash(hash *felt.Felt) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	tx, ok := s.node.transactions[*hash]
+	if !ok {
+		return nil, newError(rpc.ErrHashNotFound, nil)
+	}
+	return tx.raw, nil
+}
+
+func (s *service) GetTransactionByBlockIdAndIndex(blockID json.RawMessage, index uint64) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if ref.pending || index >= uint64(len(ref.block.transactions)) {
+		return nil, newError(rpc.ErrInvalidTxnIndex, nil)
+	}
+	return ref.block.transactions[index].raw, nil
+}
+
+func (s *service) GetTransactionReceipt(hash *felt.Felt) (rpc.TransactionReceipt, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	tx, ok := s.node.transactions[*hash]
+	if !ok {
+		return nil, newError(rpc.ErrHashNotFound, nil)
+	}
+	return tx.receipt, nil
+}
+
+func (s *service) GetTransactionStatus(hash *felt.Felt) (*rpc.TxnStatusResp, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	tx, ok := s.node.transactions[*hash]
+	if !ok {
+		return nil, newError(rpc.ErrHashNotFound, nil)
+	}
+	return &rpc.TxnStatusResp{ExecutionStatus: tx.receipt.GetExecutionStatus(), FinalityStatus: rpc.TxnStatus_Accepted_On_L2}, nil
+}
+
+// GetMessagesStatus finds no message: the node has no L1.
+func (s *service) GetMessagesStatus(transactionHash rpc.NumAsHex) ([]rpc.MessageStatus, error) {
+	return nil, newError(rpc.ErrHashNotFound, nil)
+}
+
+func (s *service) GetNonce(blockID json.RawMessage, address *felt.Felt) (*felt.Felt, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	contract, err := s.node.contractAt(blockID, address)
+	if err != nil {
+		return nil, err
+	}
+	return contract.nonce, nil
+}
+
+func (s *service) GetStorageAt(address, key *felt.Felt, blockID json.RawMessage) (*felt.Felt, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	contract, err := s.node.contractAt(blockID, address)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := contract.storage[*key]; ok {
+		return value, nil
+	}
+	return new(felt.Felt), nil
+}
+
+func (s *service) GetClassHashAt(blockID json.RawMessage, address *felt.Felt) (*felt.Felt, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	contract, err := s.node.contractAt(blockID, address)
+	if err != nil {
+		return nil, err
+	}
+	return contract.classHash, nil
+}
+
+func (s *service) GetClassAt(blockID json.RawMessage, address *felt.Felt) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	contract, err := s.node.contractAt(blockID, address)
+	if err != nil {
+		return nil, err
+	}
+	return s.node.classes[*contract.classHash].definition, nil
+}
+
+func (s *service) GetClass(blockID json.RawMessage, classHash *felt.Felt) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.resolveBlock(blockID); err != nil {
+		return nil, err
+	}
+	class, ok := s.node.classes[*classHash]
+	if !ok {
+		return nil, newError(rpc.ErrClassHashNotFound, nil)
+	}
+	return class.definition, nil
+}
+
+// GetCompiledCasm fails with a compilation error for the declared classes: the
+// node does not compile the Sierra classes.
+func (s *service) GetCompiledCasm(classHash *felt.Felt) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, ok := s.node.classes[*classHash]; !ok {
+		return nil, newError(rpc.ErrClassHashNotFound, nil)
+	}
+	return nil, newError(rpc.ErrCompilationError, rpc.CompilationErrData{CompilationError: "the node does not compile Sierra classes"})
+}
+
+// GetStorageProof is not supported: the node has no state tries.
+func (s *service) GetStorageProof(blockID json.RawMessage, classHashes, contractAddresses []*felt.Felt, contractsStorageKeys json.RawMessage) (json.RawMessage, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.resolveBlock(blockID); err != nil {
+		return nil, err
+	}
+	return nil, newError(rpc.ErrStorageProofNotSupported, nil)
+}
+
+func (s *service) Call(request rpc.FunctionCall, blockID json.RawMessage) ([]*felt.Felt, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.contractAt(blockID, request.ContractAddress); err != nil {
+		return nil, err
+	}
+	exec := s.node.newExecution(new(felt.Felt))
+	result, err := exec.Call(request.ContractAddress, request.EntryPointSelector, request.Calldata)
+	if err != nil {
+		return nil, newError(rpc.ErrContractError, map[string]string{"revert_error": err.Error()})
+	}
+	return result, nil
+}
+
+func (s *service) EstimateFee(requests []json.RawMessage, simulationFlags []string, blockID json.RawMessage) ([]rpc.FeeEstimate, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.resolveBlock(blockID); err != nil {
+		return nil, err
+	}
+	estimates := make([]rpc.FeeEstimate, len(requests))
+	for i, request := range requests {
+		version, err := decodeVersion(request)
+		if err != nil {
+			return nil, err
+		}
+		estimates[i] = s.node.feeEstimate(version)
+	}
+	return estimates, nil
+}
+
+// feeEstimate returns the fee estimate of a transaction, in fri for V3 and in wei before.
+func (n *Node) feeEstimate(version rpc.TransactionVersion) rpc.FeeEstimate {
+	unit := rpc.UnitWei
+	if version == rpc.TransactionV3 {
+		unit = rpc.UnitStrk
+	}
+	return rpc.FeeEstimate{
+		GasConsumed: new(felt.Felt).SetUint64(n.gasConsumed),
+		GasPrice:    utils.BigIntToFelt(n.gasPrice),
+		OverallFee:  utils.BigIntToFelt(n.fee()),
+		FeeUnit:     unit,
+	}
+}
+
+// EstimateMessageFee estimates the fee of an L1 handler transaction, in wei.
+// The handler is not executed.
+func (s *service) EstimateMessageFee(message rpc.MsgFromL1, blockID json.RawMessage) (*rpc.FeeEstimate, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.contractAt(blockID, message.ToAddress); err != nil {
+		return nil, err
+	}
+	estimate := s.node.feeEstimate(rpc.TransactionV0)
+	return &estimate, nil
+}
+
+// txnTrace is the trace of a transaction. The contracts are not executed from
+// their Cairo code, so the trace has no invocations: only the revert reason of
+// a reverted invoke transaction and the state diff.
+type txnTrace struct {
+	Type              rpc.TransactionType `json:"type"`
+	ExecuteInvocation *revertedInvocation `json:"execute_invocation,omitempty"`
+	StateDiff         rpc.StateDiff       `json:"state_diff"`
+}
+
+// revertedInvocation is the execute invocation of a reverted transaction.
+type revertedInvocation struct {
+	RevertReason string `json:"revert_reason"`
+}
+
+// trace returns the trace of an accepted transaction.
+func (tx *transaction) trace() *txnTrace {
+	trace := &txnTrace{Type: tx.kind, StateDiff: tx.stateDiff}
+	if tx.revertReason != "" {
+		trace.ExecuteInvocation = &revertedInvocation{RevertReason: tx.revertReason}
+	}
+	return trace
+}
+
+// SimulateTransactions estimates the fees of the transactions. The transactions
+// are neither validated nor executed: their traces have an empty state diff.
+func (s *service) SimulateTransactions(blockID json.RawMessage, requests []json.RawMessage, simulationFlags []string) ([]rpc.SimulatedTransaction, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	if _, err := s.node.resolveBlock(blockID); err != nil {
+		return nil, err
+	}
+	simulations := make([]rpc.SimulatedTransaction, len(requests))
+	for i, request := range requests {
+		var header struct {
+			Type    rpc.TransactionType    `json:"type"`
+			Version rpc.TransactionVersion `json:"version"`
+		}
+		if err := json.Unmarshal(request, &header); err != nil {
+			return nil, newError(invalidParams, err.Error())
+		}
+		simulations[i] = rpc.SimulatedTransaction{
+			TxnTrace:    &txnTrace{Type: header.Type, StateDiff: emptyStateDiff()},
+			FeeEstimate: s.node.feeEstimate(header.Version),
+		}
+	}
+	return simulations, nil
+}
+
+func (s *service) TraceTransaction(hash *felt.Felt) (*txnTrace, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	tx, ok := s.node.transactions[*hash]
+	if !ok {
+		return nil, newError(rpc.ErrHashNotFound, nil)
+	}
+	return tx.trace(), nil
+}
+
+func (s *service) TraceBlockTransactions(blockID json.RawMessage) ([]rpc.Trace, error) {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	ref, err := s.node.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	traces := []rpc.Trace{}
+	if ref.pending {
+		return traces, nil
+	}
+	for _, tx := range ref.block.transactions {
+		traces = append(traces, rpc.Trace{TraceRoot: tx.trace(), TxnHash: tx.hash})
+	}
+	return traces, nil
+}
+
+// eventsInput is the filter of starknet_getEvents, with undecoded block ids.
+type eventsInput struct {
+	FromBlock         json.RawMessage `json:"from_block"`
+	ToBlock           json.RawMessage `json:"to_block"`
+	Address           *felt.Felt      `json:"address"`
+	Keys              [][]*felt.Felt  `json:"keys"`
+	ContinuationToken string          `json:"continuation_token"`
+	ChunkSize         int             `json:"chunk_size"`
+}
+
+// matches reports whether an event passes the address and keys filters.
+func (input *eventsInput) matches(event rpc.Event) bool {
+	if input.Address != nil && !input.Address.Equal(event.FromAddress) {
+		return false
+	}
+	for i, keys := range input.Keys {
+		if len(keys) == 0 {
+			continue
+		}
+		if i >= len(event.Keys) {
+			return false
+		}
+		found := false
+		for _, key := range keys {
+			if key.Equal(event.Keys[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *service) GetEvents(input eventsInput) (*rpc.EventChunk, error) {
+	if input.ChunkSize <= 0 {
+		return nil, newError(invalidParams, "chunk_size must be positive")
+	}
+	if input.ChunkSize > MaxEventChunkSize {
+		return nil, newError(rpc.ErrPageSizeTooBig, nil)
+	}
+	offset := 0
+	if input.ContinuationToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(input.ContinuationToken); err != nil || offset < 0 {
+			return nil, newError(rpc.ErrInvalidContinuationToken, nil)
+		}
+	}
+
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	from, to := uint64(0), uint64(len(s.node.blocks)-1)
+	if len(input.FromBlock) > 0 {
+		ref, err := s.node.resolveBlock(input.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		from = ref.block.header.BlockNumber
+	}
+	if len(input.ToBlock) > 0 {
+		ref, err := s.node.resolveBlock(input.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		to = ref.block.header.BlockNumber
+	}
+
+	chunk := &rpc.EventChunk{Events: []rpc.EmittedEvent{}}
+	matched := 0
+	for number := from; number <= to && number < uint64(len(s.node.blocks)); number++ {
+		b := s.node.blocks[number]
+		for _, tx := range b.transactions {
+			for _, event := range tx.events {
+				if !input.matches(event) {
+					continue
+				}
+				matched++
+				if matched <= offset {
+					continue
+				}
+				if len(chunk.Events) == input.ChunkSize {
+					chunk.ContinuationToken = strconv.Itoa(offset + input.ChunkSize)
+					return chunk, nil
+				}
+				chunk.Events = append(chunk.Events, rpc.EmittedEvent{
+					Event:           event,
+					BlockHash:       b.header.BlockHash,
+					BlockNumber:     b.header.BlockNumber,
+					TransactionHash: tx.hash,
+				})
+			}
+		}
+	}
+	return chunk, nil
+}
+
+func (s *service) AddInvokeTransaction(tx json.RawMessage) (*rpc.AddInvokeTransactionResponse, error) {
+	hash, err := s.node.addInvokeTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.AddInvokeTransactionResponse{TransactionHash: hash}, nil
+}
+
+func (s *service) AddDeclareTransaction(tx json.RawMessage) (*rpc.AddDeclareTransactionResponse, error) {
+	hash, classHash, err := s.node.addDeclareTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.AddDeclareTransactionResponse{TransactionHash: hash, ClassHash: classHash}, nil
+}
+
+func (s *service) AddDeployAccountTransaction(tx json.RawMessage) (*rpc.AddDeployAccountTransactionResponse, error) {
+	hash, address, err := s.node.addDeployAccountTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.AddDeployAccountTransactionResponse{TransactionHash: hash, ContractAddress: address}, nil
+}
@@ -0,0 +1,419 @@
+package fakenode
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// txInfo holds the fields checked before the execution of a transaction.
+type txInfo struct {
+	kind      rpc.TransactionType
+	hash      *felt.Felt
+	sender    *felt.Felt
+	nonce     *felt.Felt
+	signature []*felt.Felt
+	// maxFee is the maximum fee the sender agrees to pay, in unit
+	maxFee *big.Int
+	unit   rpc.FeePaymentUnit
+	// classHash and compiledClassHash are set for the declare transactions
+	classHash         *felt.Felt
+	compiledClassHash *felt.Felt
+}
+
+// decodeVersion returns the version of a transaction.
+func decodeVersion(raw json.RawMessage) (rpc.TransactionVersion, error) {
+	var header struct {
+		Version rpc.TransactionVersion `json:"version"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return "", newError(invalidParams, err.Error())
+	}
+	return header.Version, nil
+}
+
+// maxFeeV3 returns the maximum fee of a V3 transaction, from its L1 gas bounds.
+func maxFeeV3(bounds rpc.ResourceBoundsMapping) (*big.Int, error) {
+	amount, err := bounds.L1Gas.MaxAmount.ToUint64()
+	if err != nil {
+		return nil, newError(invalidParams, err.Error())
+	}
+	price, ok := new(big.Int).SetString(string(bounds.L1Gas.MaxPricePerUnit), 0)
+	if !ok {
+		return nil, newError(invalidParams, "invalid max_price_per_unit")
+	}
+	return price.Mul(price, new(big.Int).SetUint64(amount)), nil
+}
+
+// verifySignature reports whether signature is a valid signature of hash by publicKey.
+func verifySignature(hash, publicKey *felt.Felt, signature []*felt.Felt) bool {
+	if len(signature) != 2 || publicKey == nil {
+		return false
+	}
+	x := utils.FeltToBigInt(publicKey)
+	y := curve.Curve.GetYCoordinate(x)
+	if y == nil {
+		return false
+	}
+	return curve.Curve.Verify(utils.FeltToBigInt(hash), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), x, y)
+}
+
+// validate checks the nonce, the signature and the fee of a transaction sent by
+// an account, or deploying it when the account is not deployed yet. The caller holds the lock.
+func (n *Node) validate(info txInfo, account *contract) error {
+	if _, ok := n.transactions[*info.hash]; ok {
+		return newError(rpc.ErrDuplicateTx, nil)
+	}
+	if account.publicKey == nil {
+		return newError(rpc.ErrNonAccount, nil)
+	}
+	if !info.nonce.Equal(account.nonce) {
+		return newError(rpc.ErrInvalidTransactionNonce, nil)
+	}
+	if !verifySignature(info.hash, account.publicKey, info.signature) {
+		return newError(rpc.ErrValidationFailure, "invalid signature")
+	}
+	if info.maxFee.Cmp(n.fee()) < 0 {
+		return newError(rpc.ErrInsufficientMaxFee, nil)
+	}
+	balance := getUint256(n.contracts[*feeToken(info.unit)].storage, balanceKey(info.sender))
+	if balance.Cmp(info.maxFee) < 0 {
+		return newError(rpc.ErrInsufficientAccountBalance, nil)
+	}
+	return nil
+}
+
+// accept charges the fee of a transaction, increments the nonce of its sender
+// and adds it in a new block with its receipt. The caller holds the lock.
+func (n *Node) accept(info txInfo, raw json.RawMessage, exec *Execution, execErr error, contractAddress *felt.Felt) {
+	var events []rpc.Event
+	stateDiff := emptyStateDiff()
+	status, revertReason := rpc.TxnExecutionStatusSUCCEEDED, ""
+	if execErr != nil {
+		status, revertReason = rpc.TxnExecutionStatusREVERTED, execErr.Error()
+	} else if exec != nil {
+		exec.commit()
+		events = exec.state.events
+		stateDiff.StorageDiffs = exec.storageDiffs()
+	}
+
+	fee := n.fee()
+	charge := n.newExecution(feeToken(info.unit))
+	// the balance was checked against the max fee in validate
+	_ = charge.transfer(info.sender, SequencerAddress, fee)
+	charge.commit()
+	events = append(events, charge.state.events...)
+	stateDiff.StorageDiffs = append(stateDiff.StorageDiffs, charge.storageDiffs()...)
+	sender := n.contracts[*info.sender]
+	sender.nonce = new(felt.Felt).Add(sender.nonce, new(felt.Felt).SetUint64(1))
+	stateDiff.Nonces = append(stateDiff.Nonces, rpc.ContractNonce{ContractAddress: info.sender, Nonce: sender.nonce})
+	if info.classHash != nil {
+		stateDiff.DeclaredClasses = append(stateDiff.DeclaredClasses, rpc.DeclaredClassesItem{ClassHash: info.classHash, CompiledClassHash: info.compiledClassHash})
+	}
+	if contractAddress != nil {
+		stateDiff.DeployedContracts = append(stateDiff.DeployedContracts, rpc.DeployedContractItem{Address: contractAddress, ClassHash: sender.classHash})
+	}
+
+	tx := &transaction{
+		hash:         info.hash,
+		kind:         info.kind,
+		raw:          withHash(raw, info.hash),
+		events:       events,
+		revertReason: revertReason,
+		stateDiff:    stateDiff,
+	}
+	b := n.addBlock([]*transaction{tx})
+	common := rpc.CommonTransactionReceipt{
+		TransactionHash:    info.hash,
+		ActualFee:          rpc.FeePayment{Amount: utils.BigIntToFelt(fee), Unit: info.unit},
+		ExecutionStatus:    status,
+		FinalityStatus:     rpc.TxnFinalityStatusAcceptedOnL2,
+		BlockHash:          b.header.BlockHash,
+		BlockNumber:        b.header.BlockNumber,
+		Type:               info.kind,
+		MessagesSent:       []rpc.MsgToL1{},
+		RevertReason:       revertReason,
+		Events:             events,
+		ExecutionResources: rpc.ExecutionResources{Steps: int(n.gasConsumed)},
+	}
+	switch info.kind {
+	case rpc.TransactionType_Invoke:
+		tx.receipt = rpc.InvokeTransactionReceipt(common)
+	case rpc.TransactionType_Declare:
+		tx.receipt = rpc.DeclareTransactionReceipt(common)
+	case rpc.TransactionType_DeployAccount:
+		tx.receipt = rpc.DeployAccountTransactionReceipt{CommonTransactionReceipt: common, ContractAddress: contractAddress}
+	}
+	n.transactions[*info.hash] = tx
+}
+
+// emptyStateDiff returns a state diff without changes, with empty lists.
+func emptyStateDiff() rpc.StateDiff {
+	return rpc.StateDiff{
+		StorageDiffs:              []rpc.ContractStorageDiffItem{},
+		DeprecatedDeclaredClasses: []*felt.Felt{},
+		DeclaredClasses:           []rpc.DeclaredClassesItem{},
+		DeployedContracts:         []rpc.DeployedContractItem{},
+		ReplacedClasses:           []rpc.ReplacedClassesItem{},
+		Nonces:                    []rpc.ContractNonce{},
+	}
+}
+
+// withHash adds the transaction hash to a transaction and removes its contract class.
+func withHash(raw json.RawMessage, hash *felt.Felt) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return raw
+	}
+	delete(fields, "contract_class")
+	fields["transaction_hash"], _ = json.Marshal(hash)
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return raw
+	}
+	return result
+}
+
+// addInvokeTransaction validates, executes and accepts an invoke transaction.
+func (n *Node) addInvokeTransaction(raw json.RawMessage) (*felt.Felt, error) {
+	version, err := decodeVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	info := txInfo{kind: rpc.TransactionType_Invoke}
+	var calldata []*felt.Felt
+	switch version {
+	case rpc.TransactionV1:
+		var tx rpc.InvokeTxnV1
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, newError(invalidParams, err.Error())
+		}
+		if info.hash, err = n.hasher.TransactionHashInvoke(tx); err != nil {
+			return nil, newError(invalidParams, err.Error())
+		}
+		info.sender, info.nonce, info.signature, calldata = tx.SenderAddress, tx.Nonce, tx.Signature, tx.Calldata
+		info.maxFee, info.unit = utils.FeltToBigInt(tx.MaxFee), rpc.UnitWei
+	case rpc.TransactionV3:
+		var tx rpc.InvokeTxnV3
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, newError(invalidParams, err.Error())
+		}
+		if info.hash, err = n.hasher.TransactionHashInvoke(tx); err != nil {
+			return nil, newError(invalidParams, err.Error())
+		}
+		if info.maxFee, err = maxFeeV3(tx.ResourceBounds); err != nil {
+			return nil, err
+		}
+		info.sender, info.nonce, info.signature, calldata = tx.SenderAddress, tx.Nonce, tx.Signature, tx.Calldata
+		info.unit = rpc.UnitStrk
+	default:
+		return nil, newError(rpc.ErrUnsupportedTxVersion, nil)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	account, ok := n.contracts[*info.sender]
+	if !ok {
+		return nil, newError(rpc.ErrContractNotFound, nil)
+	}
+	if err := n.validate(info, account); err != nil {
+		return nil, err
+	}
+
+	exec := n.newExecution(info.sender)
+	calls, err := decodeCalls(calldata, account.cairoVersion)
+	if err == nil {
+		for _, call := range calls {
+			if _, err = exec.Call(call.ContractAddress, call.EntryPointSelector, call.Calldata); err != nil {
+				break
+			}
+		}
+	}
+	n.accept(info, raw, exec, err, nil)
+	return info.hash, nil
+}
+
+// addDeclareTransaction validates and accepts a declare transaction.
+func (n *Node) addDeclareTransaction(raw json.RawMessage) (*felt.Felt, *felt.Felt, error) {
+	version, err := decodeVersion(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := txInfo{kind: rpc.TransactionType_Declare}
+	var contractClass *rpc.ContractClass
+	var classHash *felt.Felt
+	switch version {
+	case rpc.TransactionV2:
+		var tx rpc.BroadcastDeclareTxnV2
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		if classHash, err = hash.ClassHash(tx.ContractClass); err != nil {
+			return nil, nil, newError(rpc.ErrInvalidContractClass, err.Error())
+		}
+		info.hash, err = n.hasher.TransactionHashDeclare(rpc.DeclareTxnV2{
+			Type:              rpc.TransactionType_Declare,
+			SenderAddress:     tx.SenderAddress,
+			CompiledClassHash: tx.CompiledClassHash,
+			MaxFee:            tx.MaxFee,
+			Version:           rpc.TransactionV2,
+			Signature:         tx.Signature,
+			Nonce:             tx.Nonce,
+			ClassHash:         classHash,
+		})
+		if err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		contractClass = &tx.ContractClass
+		info.sender, info.nonce, info.signature = tx.SenderAddress, tx.Nonce, tx.Signature
+		info.maxFee, info.unit = utils.FeltToBigInt(tx.MaxFee), rpc.UnitWei
+		info.compiledClassHash = tx.CompiledClassHash
+	case rpc.TransactionV3:
+		var tx rpc.BroadcastDeclareTxnV3
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		if tx.ContractClass == nil {
+			return nil, nil, newError(invalidParams, "missing contract_class")
+		}
+		if classHash, err = hash.ClassHash(*tx.ContractClass); err != nil {
+			return nil, nil, newError(rpc.ErrInvalidContractClass, err.Error())
+		}
+		info.hash, err = n.hasher.TransactionHashDeclare(rpc.DeclareTxnV3{
+			Type:                  rpc.TransactionType_Declare,
+			SenderAddress:         tx.SenderAddress,
+			CompiledClassHash:     tx.CompiledClassHash,
+			Version:               rpc.TransactionV3,
+			Signature:             tx.Signature,
+			Nonce:                 tx.Nonce,
+			ClassHash:             classHash,
+			ResourceBounds:        tx.ResourceBounds,
+			Tip:                   tx.Tip,
+			PayMasterData:         tx.PayMasterData,
+			AccountDeploymentData: tx.AccountDeploymentData,
+			NonceDataMode:         tx.NonceDataMode,
+			FeeMode:               tx.FeeMode,
+		})
+		if err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		if info.maxFee, err = maxFeeV3(tx.ResourceBounds); err != nil {
+			return nil, nil, err
+		}
+		contractClass = tx.ContractClass
+		info.sender, info.nonce, info.signature = tx.SenderAddress, tx.Nonce, tx.Signature
+		info.unit = rpc.UnitStrk
+		info.compiledClassHash = tx.CompiledClassHash
+	default:
+		return nil, nil, newError(rpc.ErrUnsupportedTxVersion, nil)
+	}
+	definition, err := json.Marshal(contractClass)
+	if err != nil {
+		return nil, nil, newError(rpc.ErrInvalidContractClass, err.Error())
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	account, ok := n.contracts[*info.sender]
+	if !ok {
+		return nil, nil, newError(rpc.ErrContractNotFound, nil)
+	}
+	if _, ok := n.classes[*classHash]; ok {
+		return nil, nil, newError(rpc.ErrClassAlreadyDeclared, nil)
+	}
+	if err := n.validate(info, account); err != nil {
+		return nil, nil, err
+	}
+	n.classes[*classHash] = &class{definition: definition}
+	info.classHash = classHash
+	n.accept(info, withClassHash(raw, classHash), nil, nil, nil)
+	return info.hash, classHash, nil
+}
+
+// withClassHash adds the class hash to a declare transaction.
+func withClassHash(raw json.RawMessage, classHash *felt.Felt) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return raw
+	}
+	fields["class_hash"], _ = json.Marshal(classHash)
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return raw
+	}
+	return result
+}
+
+// addDeployAccountTransaction validates and accepts a deploy account transaction.
+// Only the built-in account class can be deployed, the public key of the account
+// is the first argument of its constructor.
+func (n *Node) addDeployAccountTransaction(raw json.RawMessage) (*felt.Felt, *felt.Felt, error) {
+	version, err := decodeVersion(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := txInfo{kind: rpc.TransactionType_DeployAccount}
+	var classHash, salt *felt.Felt
+	var constructorCalldata []*felt.Felt
+	var tx rpc.DeployAccountType
+	switch version {
+	case rpc.TransactionV1:
+		var txV1 rpc.DeployAccountTxn
+		if err := json.Unmarshal(raw, &txV1); err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		classHash, salt, constructorCalldata = txV1.ClassHash, txV1.ContractAddressSalt, txV1.ConstructorCalldata
+		info.nonce, info.signature = txV1.Nonce, txV1.Signature
+		info.maxFee, info.unit = utils.FeltToBigInt(txV1.MaxFee), rpc.UnitWei
+		tx = txV1
+	case rpc.TransactionV3:
+		var txV3 rpc.DeployAccountTxnV3
+		if err := json.Unmarshal(raw, &txV3); err != nil {
+			return nil, nil, newError(invalidParams, err.Error())
+		}
+		if info.maxFee, err = maxFeeV3(txV3.ResourceBounds); err != nil {
+			return nil, nil, err
+		}
+		classHash, salt, constructorCalldata = txV3.ClassHash, txV3.ContractAddressSalt, txV3.ConstructorCalldata
+		info.nonce, info.signature = txV3.Nonce, txV3.Signature
+		info.unit = rpc.UnitStrk
+		tx = txV3
+	default:
+		return nil, nil, newError(rpc.ErrUnsupportedTxVersion, nil)
+	}
+	if classHash == nil || salt == nil || len(constructorCalldata) == 0 {
+		return nil, nil, newError(invalidParams, "missing class_hash, contract_address_salt or constructor_calldata")
+	}
+	if info.sender, err = n.hasher.PrecomputeAddress(new(felt.Felt), salt, classHash, constructorCalldata); err != nil {
+		return nil, nil, newError(invalidParams, err.Error())
+	}
+	if info.hash, err = n.hasher.TransactionHashDeployAccount(tx, info.sender); err != nil {
+		return nil, nil, newError(invalidParams, err.Error())
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.classes[*classHash]; !ok {
+		return nil, nil, newError(rpc.ErrClassHashNotFound, nil)
+	}
+	if !classHash.Equal(AccountClassHash) {
+		return nil, nil, newError(rpc.ErrValidationFailure, "not an account class")
+	}
+	if _, ok := n.contracts[*info.sender]; ok {
+		return nil, nil, newError(rpc.ErrValidationFailure, "contract already deployed")
+	}
+	account := newContract(classHash)
+	account.publicKey = constructorCalldata[0]
+	account.cairoVersion = 2
+	if err := n.validate(info, account); err != nil {
+		return nil, nil, err
+	}
+	n.contracts[*info.sender] = account
+	n.accept(info, raw, nil, nil, info.sender)
+	return info.hash, info.sender, nil
+}
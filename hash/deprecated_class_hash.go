@@ -0,0 +1,304 @@
+package hash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrInvalidProgram = errors.New("invalid Cairo 0 program")
+
+// deprecatedProgram holds the fields of a Cairo 0 program that are hashed directly.
+type deprecatedProgram struct {
+	Builtins []string     `json:"builtins"`
+	Data     []*felt.Felt `json:"data"`
+}
+
+// DeprecatedClassHash calculates the class hash of a Cairo 0 contract class.
+// ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/core/os/contract_class/deprecated_class_hash.py
+//
+// Parameters:
+// - contract: A rpc.DeprecatedContractClass, as returned by Provider.Class and Provider.ClassAt
+// Returns:
+// - *felt.Felt: the class hash
+// - error: an error if the program cannot be decoded
+func DeprecatedClassHash(contract rpc.DeprecatedContractClass) (*felt.Felt, error) {
+	programJSON, err := DecodeProgram(contract.Program)
+	if err != nil {
+		return nil, err
+	}
+	var program deprecatedProgram
+	if err := json.Unmarshal(programJSON, &program); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProgram, err)
+	}
+
+	abi, err := json.Marshal(contract.ABI)
+	if err != nil {
+		return nil, err
+	}
+	if contract.ABI == nil {
+		abi = []byte("[]")
+	}
+	hintedClassHash, err := HintedClassHash(programJSON, abi)
+	if err != nil {
+		return nil, err
+	}
+
+	externalHash, err := hashDeprecatedEntryPoints(contract.DeprecatedEntryPointsByType.External)
+	if err != nil {
+		return nil, err
+	}
+	l1HandlerHash, err := hashDeprecatedEntryPoints(contract.DeprecatedEntryPointsByType.L1Handler)
+	if err != nil {
+		return nil, err
+	}
+	constructorHash, err := hashDeprecatedEntryPoints(contract.DeprecatedEntryPointsByType.Constructor)
+	if err != nil {
+		return nil, err
+	}
+
+	builtins := make([]*felt.Felt, 0, len(program.Builtins))
+	for _, builtin := range program.Builtins {
+		builtins = append(builtins, new(felt.Felt).SetBytes([]byte(builtin)))
+	}
+	builtinsHash, err := ComputeHashOnElementsFelt(builtins)
+	if err != nil {
+		return nil, err
+	}
+	dataHash, err := ComputeHashOnElementsFelt(program.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersion := &felt.Zero
+	return ComputeHashOnElementsFelt([]*felt.Felt{
+		apiVersion,
+		externalHash,
+		l1HandlerHash,
+		constructorHash,
+		builtinsHash,
+		hintedClassHash,
+		dataHash,
+	})
+}
+
+// DecodeProgram decodes the base64 encoded, gzip compressed program of a Cairo 0 class.
+//
+// Parameters:
+// - program: the Program of a rpc.DeprecatedContractClass
+// Returns:
+// - []byte: the JSON of the program
+// - error: an error if the program is not valid base64 or gzip
+func DecodeProgram(program string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(program)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProgram, err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProgram, err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// HintedClassHash calculates the hash of the program and the ABI of a Cairo 0 class,
+// which commits to the hints that are not part of the bytecode. The program is
+// stripped of its debug info and serialized as Python's json.dumps(sort_keys=True) does.
+//
+// Parameters:
+// - program: the JSON of the program
+// - abi: the JSON of the ABI
+// Returns:
+// - *felt.Felt: the Starknet Keccak of the serialized program and ABI
+// - error: an error if the JSON is invalid
+func HintedClassHash(program, abi []byte) (*felt.Felt, error) {
+	programValue, err := decodeJSONValue(program)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProgram, err)
+	}
+	programMap, ok := programValue.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidProgram
+	}
+	abiValue, err := decodeJSONValue(abi)
+	if err != nil {
+		return nil, err
+	}
+
+	programMap["debug_info"] = nil
+	// attributes and their empty fields were added to the program after classes were
+	// deployed, they are removed to keep the hash of those classes
+	attributes, _ := programMap["attributes"].([]any)
+	if len(attributes) == 0 {
+		delete(programMap, "attributes")
+	}
+	for _, attribute := range attributes {
+		attributeMap, ok := attribute.(map[string]any)
+		if !ok {
+			continue
+		}
+		if scopes, _ := attributeMap["accessible_scopes"].([]any); len(scopes) == 0 {
+			delete(attributeMap, "accessible_scopes")
+		}
+		if data, ok := attributeMap["flow_tracking_data"]; ok && data == nil {
+			delete(attributeMap, "flow_tracking_data")
+		}
+	}
+	// hints are keyed by integer PCs in the Python program, they are sorted as numbers
+	if hints, ok := programMap["hints"].(map[string]any); ok {
+		programMap["hints"] = intKeyedMap(hints)
+	}
+	// programs compiled before Cairo 0.10.0 have no compiler version and were
+	// hashed with the "(a : felt)" syntax of named tuples
+	if _, ok := programMap["compiler_version"]; !ok {
+		addNamedTupleSpaces(programMap)
+	}
+
+	var buf bytes.Buffer
+	writePythonJSON(&buf, map[string]any{"abi": abiValue, "program": programMap})
+	return curve.Curve.StarknetKeccak(buf.Bytes())
+}
+
+// hashDeprecatedEntryPoints hashes the (selector, offset) pairs of Cairo 0 entry points.
+func hashDeprecatedEntryPoints(entryPoints []rpc.DeprecatedCairoEntryPoint) (*felt.Felt, error) {
+	flattened := make([]*felt.Felt, 0, 2*len(entryPoints))
+	for _, entryPoint := range entryPoints {
+		offset, err := new(felt.Felt).SetString(string(entryPoint.Offset))
+		if err != nil {
+			return nil, err
+		}
+		flattened = append(flattened, entryPoint.Selector, offset)
+	}
+	return ComputeHashOnElementsFelt(flattened)
+}
+
+// decodeJSONValue decodes JSON keeping numbers as written.
+func decodeJSONValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// addNamedTupleSpaces rewrites the "cairo_type" and "value" strings from "a: felt" to "a : felt".
+func addNamedTupleSpaces(value any) {
+	switch value := value.(type) {
+	case []any:
+		for _, item := range value {
+			addNamedTupleSpaces(item)
+		}
+	case map[string]any:
+		for key, item := range value {
+			if s, ok := item.(string); ok && (key == "cairo_type" || key == "value") {
+				value[key] = strings.ReplaceAll(strings.ReplaceAll(s, ": ", " : "), "  :", " :")
+				continue
+			}
+			addNamedTupleSpaces(item)
+		}
+	}
+}
+
+// intKeyedMap is a JSON object whose keys are integers.
+type intKeyedMap map[string]any
+
+// writePythonJSON writes value as Python's json.dumps(value, sort_keys=True):
+// ", " and ": " separators, sorted keys and non-ASCII characters escaped.
+func writePythonJSON(buf *bytes.Buffer, value any) {
+	switch value := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case json.Number:
+		buf.WriteString(value.String())
+	case string:
+		writePythonString(buf, value)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writePythonJSON(buf, item)
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		writePythonObject(buf, value, keys)
+	case intKeyedMap:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		writePythonObject(buf, value, keys)
+	}
+}
+
+func writePythonObject(buf *bytes.Buffer, value map[string]any, keys []string) {
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writePythonString(buf, key)
+		buf.WriteString(": ")
+		writePythonJSON(buf, value[key])
+	}
+	buf.WriteByte('}')
+}
+
+func writePythonString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r < 0x20 || (r > 0x7e && r <= 0xffff):
+			fmt.Fprintf(buf, `\u%04x`, r)
+		case r > 0xffff:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(buf, `\u%04x\u%04x`, r1, r2)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
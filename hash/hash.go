@@ -109,20 +109,83 @@ func hashEntryPointByType(entryPoint []rpc.SierraEntryPoint) *felt.Felt {
 }
 
 // CompiledClassHash calculates the hash of a compiled class in the Casm format.
+// When the class has bytecode segment lengths, the bytecode is hashed as a tree of segments,
+// otherwise as a flat array.
 //
 // Parameters:
 // - casmClass: A `contracts.CasmClass` object
 // Returns:
-// - *felt.Felt: a pointer to a felt.Felt object that represents the calculated hash.
-func CompiledClassHash(casmClass contracts.CasmClass) *felt.Felt {
+// - *felt.Felt: a pointer to a felt.Felt object that represents the calculated hash
+// - error: contracts.ErrInvalidSegmentLengths if the bytecode segment lengths do not cover the bytecode
+func CompiledClassHash(casmClass contracts.CasmClass) (*felt.Felt, error) {
 	ContractClassVersionHash := new(felt.Felt).SetBytes([]byte("COMPILED_CLASS_V1"))
 	ExternalHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.External)
 	L1HandleHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.L1Handler)
 	ConstructorHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.Constructor)
-	ByteCodeHasH := curve.Curve.PoseidonArray(casmClass.ByteCode...)
+	ByteCodeHasH, err := hashCasmBytecode(casmClass.ByteCode, casmClass.BytecodeSegmentLengths)
+	if err != nil {
+		return nil, err
+	}
 
 	// https://github.com/software-mansion/starknet.py/blob/development/starknet_py/hash/casm_class_hash.py#L10
-	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ByteCodeHasH)
+	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ByteCodeHasH), nil
+}
+
+// hashCasmBytecode calculates the hash of the bytecode of a compiled class.
+// ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/core/os/contract_class/compiled_class_hash_objects.py
+//
+// Parameters:
+// - bytecode: the bytecode of the class
+// - segmentLengths: the bytecode segment lengths, nil for a flat bytecode
+// Returns:
+// - *felt.Felt: the hash of the bytecode
+// - error: contracts.ErrInvalidSegmentLengths if the segment lengths do not cover the bytecode
+func hashCasmBytecode(bytecode []*felt.Felt, segmentLengths *contracts.NestedInts) (*felt.Felt, error) {
+	if segmentLengths == nil {
+		return curve.Curve.PoseidonArray(bytecode...), nil
+	}
+	hash, length, err := hashBytecodeSegment(bytecode, *segmentLengths)
+	if err != nil {
+		return nil, err
+	}
+	if length != uint64(len(bytecode)) {
+		return nil, contracts.ErrInvalidSegmentLengths
+	}
+	return hash, nil
+}
+
+// hashBytecodeSegment hashes the segment at the start of bytecode. A leaf is the
+// Poseidon hash of its data, a node is 1 + the Poseidon hash of the
+// (length, hash) pairs of its children.
+//
+// Parameters:
+// - bytecode: the bytecode starting at the segment
+// - segment: the lengths of the segment
+// Returns:
+// - *felt.Felt: the hash of the segment
+// - uint64: the length of the segment
+// - error: contracts.ErrInvalidSegmentLengths if the segment is longer than the bytecode
+func hashBytecodeSegment(bytecode []*felt.Felt, segment contracts.NestedInts) (*felt.Felt, uint64, error) {
+	if !segment.IsList {
+		if segment.Value > uint64(len(bytecode)) {
+			return nil, 0, contracts.ErrInvalidSegmentLengths
+		}
+		return curve.Curve.PoseidonArray(bytecode[:segment.Value]...), segment.Value, nil
+	}
+
+	var offset uint64
+	flattened := make([]*felt.Felt, 0, 2*len(segment.Children))
+	for _, child := range segment.Children {
+		// each child is checked against the bytecode left after its siblings
+		hash, length, err := hashBytecodeSegment(bytecode[offset:], child)
+		if err != nil {
+			return nil, 0, err
+		}
+		flattened = append(flattened, new(felt.Felt).SetUint64(length), hash)
+		offset += length
+	}
+	hash := curve.Curve.PoseidonArray(flattened...)
+	return hash.Add(hash, new(felt.Felt).SetUint64(1)), offset, nil
 }
 
 // hashCasmClassEntryPointByType calculates the hash of a CasmClassEntryPoint array.
@@ -2,6 +2,8 @@ package hash_test
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"os"
 	"testing"
 
@@ -72,6 +74,64 @@ func TestCompiledClassHash(t *testing.T) {
 	err = json.Unmarshal(content, &casmClass)
 	require.NoError(t, err)
 
-	hash := hash.CompiledClassHash(casmClass)
+	hash, err := hash.CompiledClassHash(casmClass)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, hash.String())
+}
+
+// TestCompiledClassHashSegments tests the compiled class hash of a class with bytecode
+// segment lengths, compiled by Cairo 2.6.0 and declared on the integration network.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//   none
+func TestCompiledClassHashSegments(t *testing.T) {
+	// https://external.integration.starknet.io/feeder_gateway/get_compiled_class_by_class_hash?classHash=0x6b3da05b352f93912df0593a703f1884c4c607523bb33feaff4940635ef050d
+	// the hash is also checked by the TestCompiledClassHash test of juno
+	expectedHash := "0x603dd72504d8b0bc54df4f1102fdcf87fc3b2b94750a9083a5876913eec08e4"
+
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/0x6b3da05b352f93912df0593a703f1884c4c607523bb33feaff4940635ef050d.casm.json")
+	require.NoError(t, err)
+	require.NotNil(t, casmClass.BytecodeSegmentLengths)
+	require.True(t, casmClass.BytecodeSegmentLengths.IsList)
+
+	hash, err := hash.CompiledClassHash(*casmClass)
+	require.NoError(t, err)
 	require.Equal(t, expectedHash, hash.String())
 }
+
+// TestCompiledClassHashInvalidSegments tests that a compiled class whose bytecode
+// segment lengths do not cover its bytecode is rejected without panicking.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//   none
+func TestCompiledClassHashInvalidSegments(t *testing.T) {
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	length := uint64(len(casmClass.ByteCode))
+
+	for _, lengths := range []contracts.NestedInts{
+		{IsList: true, Children: []contracts.NestedInts{{Value: 1}, {Value: length - 2}}},
+		{IsList: true, Children: []contracts.NestedInts{{Value: 1}, {IsList: true, Children: []contracts.NestedInts{{Value: length}}}}},
+		// the lengths wrap around to the bytecode length when summed
+		{IsList: true, Children: []contracts.NestedInts{{Value: math.MaxUint64}, {Value: length + 1}}},
+		{Value: math.MaxUint64},
+	} {
+		lengths := lengths
+		casmClass.BytecodeSegmentLengths = &lengths
+		_, err := hash.CompiledClassHash(*casmClass)
+		require.True(t, errors.Is(err, contracts.ErrInvalidSegmentLengths))
+	}
+
+	// a single segment is hashed as a flat bytecode
+	casmClass.BytecodeSegmentLengths = nil
+	flatHash, err := hash.CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+	casmClass.BytecodeSegmentLengths = &contracts.NestedInts{Value: length}
+	segmentHash, err := hash.CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+	require.Equal(t, flatHash, segmentHash)
+}
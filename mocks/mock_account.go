@@ -15,6 +15,7 @@ import (
 
 	felt "github.com/NethermindEth/juno/core/felt"
 	rpc "github.com/NethermindEth/starknet.go/rpc"
+	typed "github.com/NethermindEth/starknet.go/typed"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -72,7 +73,7 @@ func (mr *MockAccountInterfaceMockRecorder) Sign(ctx, msg any) *gomock.Call {
 }
 
 // SignDeclareTransaction mocks base method.
-func (m *MockAccountInterface) SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error {
+func (m *MockAccountInterface) SignDeclareTransaction(ctx context.Context, tx rpc.DeclareTxnType) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignDeclareTransaction", ctx, tx)
 	ret0, _ := ret[0].(error)
@@ -86,7 +87,7 @@ func (mr *MockAccountInterfaceMockRecorder) SignDeclareTransaction(ctx, tx any)
 }
 
 // SignDeployAccountTransaction mocks base method.
-func (m *MockAccountInterface) SignDeployAccountTransaction(ctx context.Context, tx *rpc.DeployAccountTxn, precomputeAddress *felt.Felt) error {
+func (m *MockAccountInterface) SignDeployAccountTransaction(ctx context.Context, tx rpc.DeployAccountType, precomputeAddress *felt.Felt) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignDeployAccountTransaction", ctx, tx, precomputeAddress)
 	ret0, _ := ret[0].(error)
@@ -100,7 +101,7 @@ func (mr *MockAccountInterfaceMockRecorder) SignDeployAccountTransaction(ctx, tx
 }
 
 // SignInvokeTransaction mocks base method.
-func (m *MockAccountInterface) SignInvokeTransaction(ctx context.Context, tx *rpc.InvokeTxnV1) error {
+func (m *MockAccountInterface) SignInvokeTransaction(ctx context.Context, tx rpc.InvokeTxnType) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignInvokeTransaction", ctx, tx)
 	ret0, _ := ret[0].(error)
@@ -113,6 +114,21 @@ func (mr *MockAccountInterfaceMockRecorder) SignInvokeTransaction(ctx, tx any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInvokeTransaction", reflect.TypeOf((*MockAccountInterface)(nil).SignInvokeTransaction), ctx, tx)
 }
 
+// SignTypedData mocks base method.
+func (m *MockAccountInterface) SignTypedData(ctx context.Context, td typed.TypedData) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignTypedData", ctx, td)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTypedData indicates an expected call of SignTypedData.
+func (mr *MockAccountInterfaceMockRecorder) SignTypedData(ctx, td any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTypedData", reflect.TypeOf((*MockAccountInterface)(nil).SignTypedData), ctx, td)
+}
+
 // TransactionHashDeclare mocks base method.
 func (m *MockAccountInterface) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Felt, error) {
 	m.ctrl.T.Helper()
@@ -158,6 +174,21 @@ func (mr *MockAccountInterfaceMockRecorder) TransactionHashInvoke(invokeTxn any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionHashInvoke", reflect.TypeOf((*MockAccountInterface)(nil).TransactionHashInvoke), invokeTxn)
 }
 
+// VerifyTypedData mocks base method.
+func (m *MockAccountInterface) VerifyTypedData(ctx context.Context, td typed.TypedData, signature []*felt.Felt) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTypedData", ctx, td, signature)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTypedData indicates an expected call of VerifyTypedData.
+func (mr *MockAccountInterfaceMockRecorder) VerifyTypedData(ctx, td, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTypedData", reflect.TypeOf((*MockAccountInterface)(nil).VerifyTypedData), ctx, td, signature)
+}
+
 // WaitForTransactionReceipt mocks base method.
 func (m *MockAccountInterface) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceipt, error) {
 	m.ctrl.T.Helper()
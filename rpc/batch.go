@@ -0,0 +1,256 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchChunkSize is the number of requests sent in one JSON-RPC batch.
+const DefaultBatchChunkSize = 100
+
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error
+}
+
+// batchRequest is a request of a Batch with the decoding of its result.
+type batchRequest struct {
+	method string
+	args   []interface{}
+	decode func(raw json.RawMessage) (interface{}, error)
+	errs   []*RPCError
+}
+
+// BatchResponse is the result of a request of a Batch.
+type BatchResponse struct {
+	// Method is the JSON-RPC method of the request
+	Method string
+	// Result has the type returned by the Provider method of the same name, it is nil when Err is set
+	Result interface{}
+	// Err is the error of the request, with the same mapping as the Provider method of the same name
+	Err error
+}
+
+// Batch queues requests and sends them as JSON-RPC batches:
+//
+//	responses, err := provider.Batch().
+//		TransactionReceipt(hash).
+//		StorageAt(address, "balance", rpc.WithBlockTag("latest")).
+//		Send(ctx)
+//
+// The responses are in the order of the requests.
+type Batch struct {
+	provider  *Provider
+	chunkSize int
+	requests  []batchRequest
+}
+
+// Batch creates a new Batch of requests on the provider.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Batch: an empty batch sending at most DefaultBatchChunkSize requests at once
+func (provider *Provider) Batch() *Batch {
+	return &Batch{provider: provider, chunkSize: DefaultBatchChunkSize}
+}
+
+// WithChunkSize sets the maximum number of requests sent in one JSON-RPC batch.
+// Larger batches are split in several HTTP requests.
+//
+// Parameters:
+// - size: the number of requests per JSON-RPC batch, DefaultBatchChunkSize if not positive
+// Returns:
+// - *Batch: the batch
+func (b *Batch) WithChunkSize(size int) *Batch {
+	if size <= 0 {
+		size = DefaultBatchChunkSize
+	}
+	b.chunkSize = size
+	return b
+}
+
+// Len returns the number of queued requests.
+func (b *Batch) Len() int {
+	return len(b.requests)
+}
+
+// BlockWithTxHashes queues a starknet_getBlockWithTxHashes request, its result is a *BlockTxHashesResult.
+func (b *Batch) BlockWithTxHashes(blockID BlockID) *Batch {
+	return b.add("starknet_getBlockWithTxHashes", func(raw json.RawMessage) (interface{}, error) {
+		var result BlockTxHashes
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, err
+		}
+		return newBlockTxHashesResult(&result), nil
+	}, []*RPCError{ErrBlockNotFound}, blockID)
+}
+
+// BlockWithTxs queues a starknet_getBlockWithTxs request, its result is a *BlockWithTxsResult.
+func (b *Batch) BlockWithTxs(blockID BlockID) *Batch {
+	return b.add("starknet_getBlockWithTxs", func(raw json.RawMessage) (interface{}, error) {
+		var result Block
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, err
+		}
+		return newBlockWithTxsResult(&result), nil
+	}, []*RPCError{ErrBlockNotFound}, blockID)
+}
+
+// StateUpdate queues a starknet_getStateUpdate request, its result is a *StateUpdateOutput.
+func (b *Batch) StateUpdate(blockID BlockID) *Batch {
+	return b.add("starknet_getStateUpdate", decodeBatchResult[*StateUpdateOutput], []*RPCError{ErrBlockNotFound}, blockID)
+}
+
+// Call queues a starknet_call request, its result is a []*felt.Felt.
+func (b *Batch) Call(request FunctionCall, blockID BlockID) *Batch {
+	if len(request.Calldata) == 0 {
+		request.Calldata = make([]*felt.Felt, 0)
+	}
+	return b.add("starknet_call", decodeBatchResult[[]*felt.Felt], []*RPCError{ErrContractNotFound, ErrBlockNotFound}, request, blockID)
+}
+
+// ClassHashAt queues a starknet_getClassHashAt request, its result is a *felt.Felt.
+func (b *Batch) ClassHashAt(blockID BlockID, contractAddress *felt.Felt) *Batch {
+	return b.add("starknet_getClassHashAt", decodeBatchResult[*felt.Felt], []*RPCError{ErrContractNotFound, ErrBlockNotFound}, blockID, contractAddress)
+}
+
+// Nonce queues a starknet_getNonce request, its result is a *felt.Felt.
+func (b *Batch) Nonce(blockID BlockID, contractAddress *felt.Felt) *Batch {
+	return b.add("starknet_getNonce", decodeBatchResult[*felt.Felt], []*RPCError{ErrContractNotFound, ErrBlockNotFound}, blockID, contractAddress)
+}
+
+// StorageAt queues a starknet_getStorageAt request for the storage variable key, its result is a string.
+func (b *Batch) StorageAt(contractAddress *felt.Felt, key string, blockID BlockID) *Batch {
+	hashKey := fmt.Sprintf("0x%x", utils.GetSelectorFromName(key))
+	return b.add("starknet_getStorageAt", decodeBatchResult[string], []*RPCError{ErrContractNotFound, ErrBlockNotFound}, contractAddress, hashKey, blockID)
+}
+
+// TransactionByHash queues a starknet_getTransactionByHash request, its result is a Transaction.
+func (b *Batch) TransactionByHash(hash *felt.Felt) *Batch {
+	return b.add("starknet_getTransactionByHash", func(raw json.RawMessage) (interface{}, error) {
+		var tx TXN
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, err
+		}
+		return adaptTransaction(tx)
+	}, []*RPCError{ErrHashNotFound}, hash)
+}
+
+// TransactionReceipt queues a starknet_getTransactionReceipt request, its result is a TransactionReceipt.
+func (b *Batch) TransactionReceipt(transactionHash *felt.Felt) *Batch {
+	return b.add("starknet_getTransactionReceipt", func(raw json.RawMessage) (interface{}, error) {
+		var receipt UnknownTransactionReceipt
+		if err := json.Unmarshal(raw, &receipt); err != nil {
+			return nil, err
+		}
+		return receipt.TransactionReceipt, nil
+	}, []*RPCError{ErrHashNotFound}, transactionHash)
+}
+
+// GetTransactionStatus queues a starknet_getTransactionStatus request, its result is a *TxnStatusResp.
+func (b *Batch) GetTransactionStatus(transactionHash *felt.Felt) *Batch {
+	return b.add("starknet_getTransactionStatus", decodeBatchResult[*TxnStatusResp], []*RPCError{ErrHashNotFound}, transactionHash)
+}
+
+func (b *Batch) add(method string, decode func(json.RawMessage) (interface{}, error), errs []*RPCError, args ...interface{}) *Batch {
+	b.requests = append(b.requests, batchRequest{method: method, args: args, decode: decode, errs: errs})
+	return b
+}
+
+// Send sends the queued requests, in JSON-RPC batches of at most the chunk size.
+// The errors of the requests are reported in their responses, the returned
+// error is only set if a batch could not be sent. If the client does not
+// support batches, the requests are sent one by one.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// Returns:
+// - []BatchResponse: the responses, in the order of the requests
+// - error: an error if a batch could not be sent
+func (b *Batch) Send(ctx context.Context) ([]BatchResponse, error) {
+	responses := make([]BatchResponse, len(b.requests))
+	chunkSize := b.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultBatchChunkSize
+	}
+	for start := 0; start < len(b.requests); start += chunkSize {
+		end := start + chunkSize
+		if end > len(b.requests) {
+			end = len(b.requests)
+		}
+		if err := b.sendChunk(ctx, b.requests[start:end], responses[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+func (b *Batch) sendChunk(ctx context.Context, requests []batchRequest, responses []BatchResponse) error {
+	raws := make([]json.RawMessage, len(requests))
+	elems := make([]ethrpc.BatchElem, len(requests))
+	for i, request := range requests {
+		elems[i] = ethrpc.BatchElem{Method: request.method, Args: request.args, Result: &raws[i]}
+	}
+	if err := batchCall(ctx, b.provider.c, elems); err != nil {
+		return err
+	}
+
+	for i, request := range requests {
+		responses[i].Method = request.method
+		err := elems[i].Error
+		if err == nil && len(raws[i]) == 0 {
+			err = errNotFound
+		}
+		if err != nil {
+			responses[i].Err = tryUnwrapToRPCErr(err, request.errs...)
+			continue
+		}
+		responses[i].Result, responses[i].Err = request.decode(raws[i])
+	}
+	return nil
+}
+
+// batchCall sends elems as one JSON-RPC batch, or one by one if the client does not support batches.
+func batchCall(ctx context.Context, c callCloser, elems []ethrpc.BatchElem) error {
+	if batcher, ok := c.(batchCaller); ok {
+		return batcher.BatchCallContext(ctx, elems)
+	}
+	for i := range elems {
+		elems[i].Error = c.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+	}
+	return nil
+}
+
+func decodeBatchResult[T any](raw json.RawMessage) (interface{}, error) {
+	var result T
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchResult returns the result of a BatchResponse with its type.
+//
+// Parameters:
+// - response: a response of Batch.Send
+// Returns:
+// - T: the result of the request
+// - error: the error of the request, or an error if the result does not have the type T
+func BatchResult[T any](response BatchResponse) (T, error) {
+	var zero T
+	if response.Err != nil {
+		return zero, response.Err
+	}
+	result, ok := response.Result.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s result has type %T, not %T", response.Method, response.Result, zero)
+	}
+	return result, nil
+}
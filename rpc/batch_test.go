@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/test-go/testify/require"
+)
+
+// batchService is an in-process node serving the methods used by TestBatch.
+type batchService struct {
+	unknownHash *felt.Felt
+}
+
+// codeError is an error with a JSON-RPC error code, as the nodes return them.
+type codeError struct {
+	code    int
+	message string
+}
+
+func (e codeError) Error() string  { return e.message }
+func (e codeError) ErrorCode() int { return e.code }
+
+func (s *batchService) GetTransactionReceipt(hash *felt.Felt) (*InvokeTransactionReceipt, error) {
+	if hash.Equal(s.unknownHash) {
+		return nil, codeError{code: ErrHashNotFound.Code(), message: ErrHashNotFound.Error()}
+	}
+	return &InvokeTransactionReceipt{
+		TransactionHash: hash,
+		BlockHash:       new(felt.Felt).SetUint64(1),
+		BlockNumber:     1,
+		Type:            TransactionType_Invoke,
+		ExecutionStatus: TxnExecutionStatusSUCCEEDED,
+		FinalityStatus:  TxnFinalityStatusAcceptedOnL2,
+	}, nil
+}
+
+func (s *batchService) GetStorageAt(contractAddress *felt.Felt, key string, blockID interface{}) (string, error) {
+	return key, nil
+}
+
+func (s *batchService) GetNonce(blockID interface{}, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return contractAddress, nil
+}
+
+// TestBatch tests that the requests of a Batch are sent in chunks and that
+// each response is decoded, or reports its own error.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBatch(t *testing.T) {
+	service := &batchService{unknownHash: utils.TestHexToFelt(t, "0xdead")}
+	server := ethrpc.NewServer()
+	require.NoError(t, server.RegisterName("starknet", service))
+	var httpRequests int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&httpRequests, 1)
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	client, err := NewClient(httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	provider := NewProvider(client)
+
+	txHash := utils.TestHexToFelt(t, "0x42")
+	contract := utils.TestHexToFelt(t, "0xc0ffee")
+	batch := provider.Batch().WithChunkSize(2).
+		TransactionReceipt(txHash).
+		TransactionReceipt(service.unknownHash).
+		StorageAt(contract, "balance", WithBlockTag("latest")).
+		Nonce(WithBlockTag("latest"), contract).
+		TransactionReceipt(txHash)
+	require.Equal(t, 5, batch.Len())
+
+	responses, err := batch.Send(context.Background())
+	require.NoError(t, err)
+	require.Len(t, responses, 5)
+	require.Equal(t, int32(3), atomic.LoadInt32(&httpRequests))
+
+	receipt, err := BatchResult[TransactionReceipt](responses[0])
+	require.NoError(t, err)
+	require.Equal(t, txHash, receipt.Hash())
+	require.IsType(t, InvokeTransactionReceipt{}, receipt)
+
+	require.Equal(t, "starknet_getTransactionReceipt", responses[1].Method)
+	require.Nil(t, responses[1].Result)
+	require.True(t, errors.Is(responses[1].Err, ErrHashNotFound))
+
+	storage, err := BatchResult[string](responses[2])
+	require.NoError(t, err)
+	require.Equal(t, utils.GetSelectorFromNameFelt("balance").String(), storage)
+
+	nonce, err := BatchResult[*felt.Felt](responses[3])
+	require.NoError(t, err)
+	require.Equal(t, contract, nonce)
+
+	_, err = BatchResult[*felt.Felt](responses[4])
+	require.Error(t, err)
+}
+
+// TestBatchWithoutBatchSupport tests that a Batch sends the requests one by one
+// when the client does not support JSON-RPC batches.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBatchWithoutBatchSupport(t *testing.T) {
+	provider := &Provider{c: &rpcMock{}}
+	single, err := provider.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x123"))
+	require.NoError(t, err)
+
+	responses, err := provider.Batch().
+		Nonce(WithBlockTag("latest"), utils.TestHexToFelt(t, "0x123")).
+		Send(context.Background())
+	require.NoError(t, err)
+	nonce, err := BatchResult[*felt.Felt](responses[0])
+	require.NoError(t, err)
+	require.Equal(t, single, nonce)
+
+	responses, err = provider.Batch().Send(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, responses)
+}
@@ -82,26 +82,29 @@ func WithBlockTag(tag string) BlockID {
 // - ctx: The context.Context object for controlling the function call
 // - blockID: The ID of the block to retrieve the transactions from
 // Returns:
-// - interface{}: The retrieved block
+// - *BlockTxHashesResult: The retrieved block, either a block or a pending block
 // - error: An error, if any
-func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
+func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error) {
 	var result BlockTxHashes
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 
+	return newBlockTxHashesResult(&result), nil
+}
+
+// newBlockTxHashesResult wraps a decoded block, converting it to a pending block if it has no hash.
+func newBlockTxHashesResult(result *BlockTxHashes) *BlockTxHashesResult {
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlockTxHashes{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
-			result.Transactions,
-		}, nil
+		return &BlockTxHashesResult{
+			PendingBlock: &PendingBlockTxHashes{
+				result.BlockHeader.pendingHeader(),
+				result.Transactions,
+			},
+		}
 	}
-
-	return &result, nil
+	return &BlockTxHashesResult{Block: result}
 }
 
 // StateUpdate is a function that performs a state update operation
@@ -146,22 +149,74 @@ func (provider *Provider) BlockTransactionCount(ctx context.Context, blockID Blo
 // - ctx: The context.Context object for the request
 // - blockID: The ID of the block to retrieve
 // Returns:
-// - interface{}: The retrieved block
+// - *BlockWithTxsResult: The retrieved block, either a block or a pending block
 // - error: An error, if any
-func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (*BlockWithTxsResult, error) {
 	var result Block
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err,ErrBlockNotFound )
 	}
+	return newBlockWithTxsResult(&result), nil
+}
+
+// newBlockWithTxsResult wraps a decoded block, converting it to a pending block if it has no hash.
+func newBlockWithTxsResult(result *Block) *BlockWithTxsResult {
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlock{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
-			result.Transactions,
+		return &BlockWithTxsResult{
+			PendingBlock: &PendingBlock{
+				result.BlockHeader.pendingHeader(),
+				result.Transactions,
+			},
+		}
+	}
+	return &BlockWithTxsResult{Block: result}
+}
+
+// BlockWithReceipts retrieves a block with its transactions and their receipts given the block id.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockWithReceiptsResult: The retrieved block, either a block or a pending block
+// - error: An error, if any
+func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error) {
+	var result rawBlockWithReceipts
+	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	return newBlockWithReceiptsResult(&result)
+}
+
+// newBlockWithReceiptsResult wraps a decoded block, converting it to a pending block if it has no hash.
+// The receipts of a block get its hash and number.
+func newBlockWithReceiptsResult(result *rawBlockWithReceipts) (*BlockWithReceiptsResult, error) {
+	transactions := make([]TransactionWithReceipt, len(result.Transactions))
+	for i, txn := range result.Transactions {
+		if result.BlockHash != nil {
+			txn.Receipt["block_hash"] = result.BlockHash
+			txn.Receipt["block_number"] = result.BlockNumber
+		}
+		receipt, err := unmarshalTransactionReceipt(txn.Receipt)
+		if err != nil {
+			return nil, err
+		}
+		transactions[i] = TransactionWithReceipt{Transaction: txn.Transaction.Transaction, Receipt: receipt}
+	}
+
+	// if header.Hash == nil it's a pending block
+	if result.BlockHash == nil {
+		return &BlockWithReceiptsResult{
+			PendingBlock: &PendingBlockWithReceipts{
+				PendingBlockHeader: result.BlockHeader.pendingHeader(),
+				Transactions:       transactions,
+			},
 		}, nil
 	}
-	return &result, nil
+	return &BlockWithReceiptsResult{Block: &BlockWithReceipts{
+		BlockHeader:  result.BlockHeader,
+		Status:       result.Status,
+		Transactions: transactions,
+	}}, nil
 }
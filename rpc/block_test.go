@@ -227,15 +227,11 @@ func TestBlockWithTxHashes(t *testing.T) {
 		testConfig.provider.c = spy
 		result, err := testConfig.provider.BlockWithTxHashes(context.Background(), test.BlockID)
 		require.Equal(t, test.ExpectedError, err, "Error in BlockWithTxHashes")
-		switch resultType := result.(type) {
-		case *BlockTxHashes:
-			block, ok := result.(*BlockTxHashes)
-			if !ok {
-				t.Fatalf("should return *BlockTxHashes, instead: %T\n", result)
-			}
-			if test.ExpectedError != nil {
-				continue
-			}
+		if test.ExpectedError != nil {
+			continue
+		}
+		if !result.Pending() {
+			block := result.Block
 			if !strings.HasPrefix(block.BlockHash.String(), "0x") {
 				t.Fatal("Block Hash should start with \"0x\", instead", block.BlockHash)
 			}
@@ -255,18 +251,13 @@ func TestBlockWithTxHashes(t *testing.T) {
 				require.Equal(t, block.Status, test.ExpectedBlockWithTxHashes.Status, "Error in BlockTxHash Status")
 				require.Equal(t, block.Transactions, test.ExpectedBlockWithTxHashes.Transactions, "Error in BlockTxHash Transactions")
 			}
-		case *PendingBlockTxHashes:
-			pBlock, ok := result.(*PendingBlockTxHashes)
-			if !ok {
-				t.Fatalf("should return *PendingBlockTxHashes, instead: %T\n", result)
-			}
-
+		} else {
+			pBlock := result.PendingBlock
+			require.Nil(t, result.BlockHash(), "Error in PendingBlockTxHashes BlockHash")
 			require.Equal(t, pBlock.ParentHash, test.ExpectedPendingBlockWithTxHashes.ParentHash, "Error in PendingBlockTxHashes ParentHash")
 			require.Equal(t, pBlock.SequencerAddress, test.ExpectedPendingBlockWithTxHashes.SequencerAddress, "Error in PendingBlockTxHashes SequencerAddress")
 			require.Equal(t, pBlock.Timestamp, test.ExpectedPendingBlockWithTxHashes.Timestamp, "Error in PendingBlockTxHashes Timestamp")
 			require.Equal(t, pBlock.Transactions, test.ExpectedPendingBlockWithTxHashes.Transactions, "Error in PendingBlockTxHashes Transactions")
-		default:
-			t.Fatalf("unexpected block type, found: %T\n", resultType)
 		}
 	}
 }
@@ -359,17 +350,17 @@ func TestBlockWithTxsAndInvokeTXNV0(t *testing.T) {
 	for _, test := range testSet {
 		spy := NewSpy(testConfig.provider.c)
 		testConfig.provider.c = spy
-		blockWithTxsInterface, err := testConfig.provider.BlockWithTxs(context.Background(), test.BlockID)
+		blockWithTxsResult, err := testConfig.provider.BlockWithTxs(context.Background(), test.BlockID)
 		if err != test.ExpectedError {
 			t.Fatal("BlockWithTxHashes match the expected error:", err)
 		}
-		if test.ExpectedError != nil && blockWithTxsInterface == nil {
+		if test.ExpectedError != nil && blockWithTxsResult == nil {
 			continue
 		}
-		blockWithTxs, ok := blockWithTxsInterface.(*Block)
-		if !ok {
-			t.Fatalf("expecting *rpv02.Block, instead %T", blockWithTxsInterface)
+		if blockWithTxsResult.Pending() {
+			t.Fatal("expecting *rpc.Block, instead *rpc.PendingBlock")
 		}
+		blockWithTxs := blockWithTxsResult.Block
 		_, err = spy.Compare(blockWithTxs, false)
 		if err != nil {
 			t.Fatal("expecting to match", err)
@@ -570,17 +561,17 @@ func TestBlockWithTxsAndDeployOrDeclare(t *testing.T) {
 	for _, test := range testSet {
 		spy := NewSpy(testConfig.provider.c)
 		testConfig.provider.c = spy
-		blockWithTxsInterface, err := testConfig.provider.BlockWithTxs(context.Background(), test.BlockID)
+		blockWithTxsResult, err := testConfig.provider.BlockWithTxs(context.Background(), test.BlockID)
 		if err != test.ExpectedError {
 			t.Fatal("BlockWithTxHashes match the expected error:", err)
 		}
-		if test.ExpectedError != nil && blockWithTxsInterface == nil {
+		if test.ExpectedError != nil && blockWithTxsResult == nil {
 			continue
 		}
-		blockWithTxs, ok := blockWithTxsInterface.(*Block)
-		if !ok {
-			t.Fatalf("expecting *rpc.Block, instead %T", blockWithTxsInterface)
+		if blockWithTxsResult.Pending() {
+			t.Fatal("expecting *rpc.Block, instead *rpc.PendingBlock")
 		}
+		blockWithTxs := blockWithTxsResult.Block
 		diff, err := spy.Compare(blockWithTxs, false)
 		if err != nil {
 			t.Fatal("expecting to match", err)
@@ -689,14 +680,14 @@ func TestCaptureUnsupportedBlockTxn(t *testing.T) {
 	}[testEnv]
 	for _, test := range testSet {
 		for i := test.StartBlock; i < test.EndBlock; i++ {
-			blockWithTxsInterface, err := testConfig.provider.BlockWithTxs(context.Background(), WithBlockNumber(i))
+			blockWithTxsResult, err := testConfig.provider.BlockWithTxs(context.Background(), WithBlockNumber(i))
 			if err != nil {
 				t.Fatal("BlockWithTxHashes match the expected error:", err)
 			}
-			blockWithTxs, ok := blockWithTxsInterface.(*Block)
-			if !ok {
-				t.Fatalf("expecting *rpc.Block, instead %T", blockWithTxsInterface)
+			if blockWithTxsResult.Pending() {
+				t.Fatal("expecting *rpc.Block, instead *rpc.PendingBlock")
 			}
+			blockWithTxs := blockWithTxsResult.Block
 			for k, v := range blockWithTxs.Transactions {
 				_, okv1 := v.(BlockInvokeTxnV1)
 				_, okv0 := v.(BlockInvokeTxnV0)
@@ -827,3 +818,86 @@ func TestStateUpdate(t *testing.T) {
 		}
 	}
 }
+
+// TestBlockWithReceipts tests the BlockWithReceipts function.
+//
+// It checks that the receipts of a block get its hash and number, and that
+// the receipts of the pending block are pending receipts.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockWithReceipts(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	type testSetType struct {
+		BlockID         BlockID
+		ExpectedPending bool
+		ExpectedReceipt TransactionReceipt
+		ExpectedErr     error
+	}
+	receipt := CommonTransactionReceipt{
+		TransactionHash: utils.TestHexToFelt(t, "0x1f2a3c9d6c3e3a4b5e4a8c2f7d0b1e9a4c6d8f0a2b4c6e8f0a1b3c5d7e9f1a3"),
+		ActualFee:       FeePayment{Amount: utils.TestHexToFelt(t, "0x1d1a94a20000"), Unit: UnitWei},
+		ExecutionStatus: TxnExecutionStatusSUCCEEDED,
+		FinalityStatus:  TxnFinalityStatusAcceptedOnL2,
+		Type:            TransactionType_Invoke,
+		MessagesSent:    []MsgToL1{},
+		Events:          []Event{},
+		ExecutionResources: ExecutionResources{
+			Steps: 4512,
+		},
+	}
+	blockReceipt := receipt
+	blockReceipt.BlockHash = utils.TestHexToFelt(t, "0x4d0c9b6ac9a1e5d4b9c1d8f3a2e7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a")
+	blockReceipt.BlockNumber = 48213
+
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				BlockID:         WithBlockTag("latest"),
+				ExpectedReceipt: InvokeTransactionReceipt(blockReceipt),
+			},
+			{
+				BlockID:         WithBlockTag("pending"),
+				ExpectedPending: true,
+				ExpectedReceipt: PendingInvokeTransactionReceipt{
+					Type: TransactionType_Invoke,
+					PendingCommonTransactionReceiptProperties: PendingCommonTransactionReceiptProperties{
+						TransactionHash:    receipt.TransactionHash,
+						ActualFee:          receipt.ActualFee,
+						MessagesSent:       receipt.MessagesSent,
+						ExecutionStatus:    receipt.ExecutionStatus,
+						FinalityStatus:     receipt.FinalityStatus,
+						Events:             receipt.Events,
+						ExecutionResources: receipt.ExecutionResources,
+					},
+				},
+			},
+			{
+				BlockID:     WithBlockNumber(1),
+				ExpectedErr: ErrBlockNotFound,
+			},
+		},
+		"testnet": {},
+		"mainnet": {},
+	}[testEnv]
+
+	for _, test := range testSet {
+		block, err := testConfig.provider.BlockWithReceipts(context.Background(), test.BlockID)
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedPending, block.Pending())
+		require.Len(t, block.Transactions(), 1)
+		txn := block.Transactions()[0]
+		require.Equal(t, test.ExpectedReceipt, txn.Receipt)
+		invoke, ok := txn.Transaction.(InvokeTxnV1)
+		require.True(t, ok)
+		require.Equal(t, utils.TestHexToFelt(t, "0x7"), invoke.Nonce)
+	}
+}
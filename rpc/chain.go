@@ -45,6 +45,12 @@ func (provider *Provider) Syncing(ctx context.Context) (*SyncStatus, error) {
 		return &SyncStatus{SyncStatus: res}, nil
 	case SyncStatus:
 		return &res, nil
+	case map[string]interface{}:
+		var status SyncStatus
+		if err := remarshal(res, &status); err != nil {
+			return nil, err
+		}
+		return &status, nil
 	default:
 		return nil, errors.New("internal error with starknet_syncing")
 	}
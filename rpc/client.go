@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
@@ -47,3 +48,16 @@ func do(ctx context.Context, call callCloser, method string, data interface{}, a
 func NewClient(url string) (*ethrpc.Client, error) {
 	return ethrpc.DialContext(context.Background(), url)
 }
+
+// NewClientWithTransport creates a new ethrpc.Client for an HTTP endpoint sending
+// its requests with the given transport, e.g. a RecordTransport or a ReplayTransport.
+//
+// Parameters:
+// - url: the URL of the HTTP RPC endpoint
+// - transport: the http.RoundTripper sending the requests
+// Returns:
+// - *ethrpc.Client: a new ethrpc.Client
+// - error: an error if any occurred
+func NewClientWithTransport(url string, transport http.RoundTripper) (*ethrpc.Client, error) {
+	return ethrpc.DialHTTPWithClient(url, &http.Client{Transport: transport})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
@@ -162,3 +163,48 @@ func (provider *Provider) EstimateMessageFee(ctx context.Context, msg MsgFromL1,
 	}
 	return &raw, nil
 }
+
+// CompiledCasm retrieves the CASM compiled by the node from a declared Sierra class.
+//
+// Parameters:
+// - ctx: The context.Context object
+// - classHash: The hash of the Sierra class
+// Returns:
+// - *contracts.CasmClass: The compiled class
+// - error: An error if any occurred during the execution, ErrCompilationError holds the compilation error
+func (provider *Provider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	var result contracts.CasmClass
+	if err := do(ctx, provider.c, "starknet_getCompiledCasm", &result, classHash); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrCompilationError, ErrClassHashNotFound)
+	}
+	return &result, nil
+}
+
+// GetStorageProof retrieves the Merkle paths proving classes, contracts and storage
+// keys in the global state tries of a block.
+//
+// Parameters:
+// - ctx: The context.Context object
+// - input: The block and the items to prove
+// Returns:
+// - *StorageProofResult: The proofs and the roots of the tries
+// - error: An error if any occurred during the execution
+func (provider *Provider) GetStorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error) {
+	classHashes := input.ClassHashes
+	if classHashes == nil {
+		classHashes = []*felt.Felt{}
+	}
+	contractAddresses := input.ContractAddresses
+	if contractAddresses == nil {
+		contractAddresses = []*felt.Felt{}
+	}
+	storageKeys := input.ContractsStorageKeys
+	if storageKeys == nil {
+		storageKeys = []ContractStorageKeys{}
+	}
+	var result StorageProofResult
+	if err := do(ctx, provider.c, "starknet_getStorageProof", &result, input.BlockID, classHashes, contractAddresses, storageKeys); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound, ErrStorageProofNotSupported)
+	}
+	return &result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/test-go/testify/require"
 )
@@ -513,3 +514,99 @@ func TestEstimateFee(t *testing.T) {
 		require.Equal(t, test.expectedResp, resp)
 	}
 }
+
+// TestGetStorageProof tests the GetStorageProof function.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGetStorageProof(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	type testSetType struct {
+		Input       StorageProofInput
+		ExpectedErr error
+	}
+	address := utils.TestHexToFelt(t, "0x6ca4fdd437dffde5253ba7021ef7265c88b07789aa642eafda37791626edf00")
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				Input: StorageProofInput{BlockID: WithBlockTag("latest"), ContractAddresses: []*felt.Felt{address}},
+			},
+			{
+				Input:       StorageProofInput{BlockID: WithBlockTag("pending")},
+				ExpectedErr: ErrStorageProofNotSupported,
+			},
+		},
+		"testnet": {},
+		"mainnet": {},
+	}[testEnv]
+
+	for _, test := range testSet {
+		proof, err := testConfig.provider.GetStorageProof(context.Background(), test.Input)
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Empty(t, proof.ClassesProof)
+		require.Len(t, proof.ContractsProof.Nodes, 2)
+		binary := proof.ContractsProof.Nodes[0].Node.Binary
+		require.NotNil(t, binary)
+		require.Equal(t, proof.ContractsProof.Nodes[1].NodeHash, binary.Left)
+		edge := proof.ContractsProof.Nodes[1].Node.Edge
+		require.NotNil(t, edge)
+		require.Equal(t, uint64(250), edge.Length)
+		require.Equal(t, []ContractLeafData{{
+			Nonce:     utils.TestHexToFelt(t, "0x7"),
+			ClassHash: utils.TestHexToFelt(t, "0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f"),
+		}}, proof.ContractsProof.ContractLeavesData)
+		require.Equal(t, proof.ContractsProof.Nodes[0].NodeHash, proof.GlobalRoots.ContractsTreeRoot)
+	}
+}
+
+// TestCompiledCasm tests the CompiledCasm function.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestCompiledCasm(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	expectedCasm, err := contracts.UnmarshalCasmClass("../contracts/tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	type testSetType struct {
+		ClassHash    *felt.Felt
+		ExpectedCasm *contracts.CasmClass
+		ExpectedErr  error
+	}
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				ClassHash:    utils.TestHexToFelt(t, "0xdeadbeef"),
+				ExpectedCasm: expectedCasm,
+			},
+			{
+				ClassHash:   utils.TestHexToFelt(t, "0x1"),
+				ExpectedErr: ErrClassHashNotFound,
+			},
+		},
+		"testnet": {},
+		"mainnet": {},
+	}[testEnv]
+
+	for _, test := range testSet {
+		casm, err := testConfig.provider.CompiledCasm(context.Background(), test.ClassHash)
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedCasm, casm)
+	}
+}
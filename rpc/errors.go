@@ -3,6 +3,8 @@ package rpc
 import (
 	"encoding/json"
 	"errors"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 var ErrNotImplemented = errors.New("not implemented")
@@ -37,57 +39,104 @@ func Err(code int, data any) *RPCError {
 	}
 }
 
-// tryUnwrapToRPCErr unwraps the error and checks if it matches any of the given RPC errors.
-// If a match is found, the corresponding RPC error is returned.
-// If no match is found, the function returns an InternalError with the original error.
+// tryUnwrapToRPCErr converts the JSON-RPC error returned by a node to an *RPCError
+// with the code, the message and the data of the node error. The result satisfies
+// errors.Is with the sentinel of its code, e.g. ErrBlockNotFound, and its data is
+// decoded for the errors with a structured data, see RPCError.Data.
+// The errors that are not JSON-RPC errors, e.g. network errors, are returned unchanged.
 //
 // Parameters:
 // - err: The error to be unwrapped
-// - rpcErrors: variadic list of *RPCError objects to be checked
+// - rpcErrors: the errors defined by the spec for the method, their messages are used for their codes
 // Returns:
-// - error: the original error
+// - error: the *RPCError, or err if it is not a JSON-RPC error
 func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) error {
-	var nodeErr *RPCError
-	if json.Unmarshal([]byte(err.Error()), nodeErr) != nil {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
 		return err
 	}
-
-	dataErr := isErrorWithData(nodeErr)
-	if dataErr != nil {
-		return dataErr
+	var nodeErr ethrpc.Error
+	if !errors.As(err, &nodeErr) {
+		return err
 	}
 
+	code := nodeErr.ErrorCode()
+	message := nodeErr.Error()
+	if known := findError(code, rpcErrors); known != nil {
+		message = known.message
+	} else if known := findError(code, knownErrors); known != nil {
+		message = known.message
+	}
+	var data any
+	var dataErr ethrpc.DataError
+	if errors.As(err, &dataErr) {
+		data = decodeErrorData(code, dataErr.ErrorData())
+	}
+	return &RPCError{code: code, message: message, data: data}
+}
+
+func findError(code int, rpcErrors []*RPCError) *RPCError {
 	for _, rpcErr := range rpcErrors {
-		if errors.Is(nodeErr, rpcErr) {
+		if rpcErr.code == code {
 			return rpcErr
 		}
 	}
-	return Err(InternalError, err)
+	return nil
 }
 
-// isErrorWithData checks if the error is the type of error that might contain information in the data field.
-// In the case it is, it adds this information to the returned error.
-//
-// Parameters:
-// - nodeErr: The error to be checked
-// Returns:
-// - *RPCError: a pointer to the RPCError resulting object
-func isErrorWithData(nodeErr *RPCError) *RPCError {
-	switch nodeErr.code {
-	case ErrUnexpectedError.code:
-		unexpectedErr := *ErrUnexpectedError
-		unexpectedErr.data = nodeErr.data
-		return &unexpectedErr
+// decodeErrorData decodes the data of the errors with a structured data.
+// The data that does not match the structure of its error is returned unchanged.
+func decodeErrorData(code int, data any) any {
+	if data == nil {
+		return nil
+	}
+	var typed any
+	switch code {
 	case ErrNoTraceAvailable.code:
-		noTraceAvailableError := *ErrNoTraceAvailable
-		noTraceAvailableError.data = nodeErr.data
-		return &noTraceAvailableError
+		typed = &TraceStatusErrData{}
 	case ErrContractError.code:
-		contractError := *ErrContractError
-		contractError.data = nodeErr.data
-		return &contractError
+		typed = &ContractErrData{}
+	case ErrTxnExec.code:
+		typed = &TransactionExecErrData{}
+	case ErrCompilationError.code:
+		typed = &CompilationErrData{}
+	default:
+		return data
 	}
-	return nil
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	if json.Unmarshal(raw, typed) != nil {
+		return data
+	}
+	return typed
+}
+
+// TraceStatusErrData is the data of ErrNoTraceAvailable.
+type TraceStatusErrData struct {
+	// Status The status of the transaction, RECEIVED or REJECTED
+	Status string `json:"status"`
+}
+
+// ContractErrData is the data of ErrContractError.
+type ContractErrData struct {
+	// RevertError The revert reason of the contract, with the call stack of the failure
+	RevertError string `json:"revert_error"`
+}
+
+// TransactionExecErrData is the data of ErrTxnExec.
+type TransactionExecErrData struct {
+	// TransactionIndex The index of the failed transaction in the request
+	TransactionIndex int `json:"transaction_index"`
+	// ExecutionError The execution trace up to the point of failure
+	ExecutionError string `json:"execution_error"`
+}
+
+// CompilationErrData is the data of ErrCompilationError.
+type CompilationErrData struct {
+	// CompilationError The error of the compiler
+	CompilationError string `json:"compilation_error"`
 }
 
 type RPCError struct {
@@ -118,7 +167,9 @@ func (e *RPCError) Code() int {
 	return e.code
 }
 
-// Data returns the data associated with the RPCError.
+// Data returns the data associated with the RPCError. It is a *TraceStatusErrData,
+// a *ContractErrData, a *TransactionExecErrData or a *CompilationErrData for
+// the errors with a structured data, the data sent by the node otherwise.
 //
 // Parameters:
 //
@@ -130,6 +181,28 @@ func (e *RPCError) Data() any {
 	return e.data
 }
 
+// ErrorCode returns the code of the RPCError, as the errors of go-ethereum/rpc.
+func (e *RPCError) ErrorCode() int {
+	return e.code
+}
+
+// ErrorData returns the data of the RPCError, as the errors of go-ethereum/rpc.
+func (e *RPCError) ErrorData() interface{} {
+	return e.data
+}
+
+// Is reports whether target is an *RPCError with the same code, so that
+// errors.Is(err, ErrBlockNotFound) holds for the errors returned by the Provider.
+//
+// Parameters:
+// - target: the error to compare with
+// Returns:
+// - bool: true if target has the code of the RPCError
+func (e *RPCError) Is(target error) bool {
+	t, ok := target.(*RPCError)
+	return ok && t != nil && t.code == e.code
+}
+
 var (
 	ErrFailedToReceiveTxn = &RPCError{
 		code:    1,
@@ -183,6 +256,10 @@ var (
 		code:    34,
 		message: "Too many keys provided in a filter",
 	}
+	ErrStorageProofNotSupported = &RPCError{
+		code:    42,
+		message: "The node doesn't support storage proofs for blocks that are too far in the past",
+	}
 	ErrContractError = &RPCError{
 		code:    40,
 		message: "Contract error",
@@ -247,4 +324,43 @@ var (
 		code:    63,
 		message: "An unexpected error occurred",
 	}
+	ErrCompilationError = &RPCError{
+		code:    100,
+		message: "Failed to compile the contract",
+	}
 )
+
+// knownErrors are the errors defined by the spec, by which the codes of the node errors get their messages.
+var knownErrors = []*RPCError{
+	ErrFailedToReceiveTxn,
+	ErrNoTraceAvailable,
+	ErrContractNotFound,
+	ErrBlockNotFound,
+	ErrInvalidTxnHash,
+	ErrInvalidBlockHash,
+	ErrInvalidTxnIndex,
+	ErrClassHashNotFound,
+	ErrHashNotFound,
+	ErrPageSizeTooBig,
+	ErrNoBlocks,
+	ErrInvalidContinuationToken,
+	ErrTooManyKeysInFilter,
+	ErrContractError,
+	ErrTxnExec,
+	ErrStorageProofNotSupported,
+	ErrInvalidContractClass,
+	ErrClassAlreadyDeclared,
+	ErrInvalidTransactionNonce,
+	ErrInsufficientMaxFee,
+	ErrInsufficientAccountBalance,
+	ErrValidationFailure,
+	ErrCompilationFailed,
+	ErrContractClassSizeTooLarge,
+	ErrNonAccount,
+	ErrDuplicateTx,
+	ErrCompiledClassHashMismatch,
+	ErrUnsupportedTxVersion,
+	ErrUnsupportedContractClassVersion,
+	ErrUnexpectedError,
+	ErrCompilationError,
+}
@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/test-go/testify/require"
+)
+
+// dataNodeError is a JSON-RPC error with data, as sent by errorService.
+type dataNodeError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func (e *dataNodeError) Error() string          { return e.message }
+func (e *dataNodeError) ErrorCode() int         { return e.code }
+func (e *dataNodeError) ErrorData() interface{} { return e.data }
+
+// errorService answers every starknet_call with its error.
+type errorService struct {
+	err error
+}
+
+func (s *errorService) Call(request FunctionCall, blockID interface{}) ([]*felt.Felt, error) {
+	return nil, s.err
+}
+
+// TestTryUnwrapToRPCErr tests the conversion of the errors sent by a node.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTryUnwrapToRPCErr(t *testing.T) {
+	type testSetType struct {
+		Name            string
+		NodeError       error
+		ExpectedTarget  *RPCError
+		ExpectedMessage string
+		ExpectedData    any
+	}
+	testSet := []testSetType{
+		{
+			Name:            "sentinel",
+			NodeError:       &dataNodeError{code: 20, message: "Contract not found"},
+			ExpectedTarget:  ErrContractNotFound,
+			ExpectedMessage: ErrContractNotFound.Error(),
+		},
+		{
+			Name: "contract error",
+			NodeError: &dataNodeError{code: 40, message: "Contract error", data: map[string]interface{}{
+				"revert_error": "Error in the called contract: 0x6e6f7420656e6f756768 ('not enough')",
+			}},
+			ExpectedTarget:  ErrContractError,
+			ExpectedMessage: ErrContractError.Error(),
+			ExpectedData:    &ContractErrData{RevertError: "Error in the called contract: 0x6e6f7420656e6f756768 ('not enough')"},
+		},
+		{
+			Name: "transaction execution error",
+			NodeError: &dataNodeError{code: 41, message: "Transaction execution error", data: map[string]interface{}{
+				"transaction_index": 2,
+				"execution_error":   "Insufficient balance",
+			}},
+			ExpectedTarget:  ErrTxnExec,
+			ExpectedMessage: ErrTxnExec.Error(),
+			ExpectedData:    &TransactionExecErrData{TransactionIndex: 2, ExecutionError: "Insufficient balance"},
+		},
+		{
+			Name:            "unstructured data",
+			NodeError:       &dataNodeError{code: 40, message: "Contract error", data: "Execution was reverted"},
+			ExpectedTarget:  ErrContractError,
+			ExpectedMessage: ErrContractError.Error(),
+			ExpectedData:    "Execution was reverted",
+		},
+		{
+			Name:            "validation failure",
+			NodeError:       &dataNodeError{code: 55, message: "Account validation failed", data: "invalid signature"},
+			ExpectedTarget:  ErrValidationFailure,
+			ExpectedMessage: ErrValidationFailure.Error(),
+			ExpectedData:    "invalid signature",
+		},
+		{
+			Name:            "unknown code",
+			NodeError:       &dataNodeError{code: 1234, message: "Node is overloaded"},
+			ExpectedMessage: "Node is overloaded",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			server := ethrpc.NewServer()
+			require.NoError(t, server.RegisterName("starknet", &errorService{err: test.NodeError}))
+			t.Cleanup(server.Stop)
+			client := ethrpc.DialInProc(server)
+			t.Cleanup(client.Close)
+			provider := &Provider{c: client}
+
+			_, err := provider.Call(context.Background(), FunctionCall{
+				ContractAddress:    utils.TestHexToFelt(t, "0x1"),
+				EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
+			}, WithBlockTag("latest"))
+
+			var rpcErr *RPCError
+			require.True(t, errors.As(err, &rpcErr))
+			require.Equal(t, test.NodeError.(*dataNodeError).code, rpcErr.Code())
+			require.Equal(t, test.ExpectedMessage, rpcErr.Error())
+			require.Equal(t, test.ExpectedData, rpcErr.Data())
+			if test.ExpectedTarget != nil {
+				require.True(t, errors.Is(err, test.ExpectedTarget))
+				// the sentinels are not modified
+				require.Nil(t, test.ExpectedTarget.Data())
+			}
+			require.False(t, errors.Is(err, ErrBlockNotFound))
+		})
+	}
+
+	t.Run("not a node error", func(t *testing.T) {
+		networkErr := errors.New("connection refused")
+		require.Equal(t, networkErr, tryUnwrapToRPCErr(networkErr, ErrBlockNotFound))
+	})
+}
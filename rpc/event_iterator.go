@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+const (
+	// DefaultEventChunkSize is the chunk size of the EventIterator requests when the input has none
+	DefaultEventChunkSize = 1000
+	// DefaultEventPollInterval is the interval between the requests of an EventIterator following the chain
+	DefaultEventPollInterval = 5 * time.Second
+)
+
+// EventIteratorOptions configures an EventIterator.
+type EventIteratorOptions struct {
+	// Follow keeps polling for the events of new blocks once the events of the
+	// filter are exhausted. Each query ends at the latest block number at the
+	// time of the query and the next poll starts from the following block, so
+	// the pending events are not delivered.
+	Follow bool
+	// PollInterval is the interval between the polls, DefaultEventPollInterval when zero
+	PollInterval time.Duration
+}
+
+// eventKey identifies an event: the block, the transaction and the index of
+// the event among the events of the transaction matching the filter.
+type eventKey struct {
+	blockNumber     uint64
+	blockHash       felt.Felt
+	transactionHash felt.Felt
+	index           int
+}
+
+// EventIterator pages through the events matching a filter with the
+// continuation tokens of starknet_getEvents:
+//
+//	it := rpc.NewEventIterator(provider, input, nil)
+//	for it.Next(ctx) {
+//		event := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// ErrPageSizeTooBig is recovered by halving the chunk size and
+// ErrInvalidContinuationToken by querying again from the last block with
+// events. The events delivered before are skipped.
+type EventIterator struct {
+	provider     RpcProvider
+	input        EventsInput
+	follow       bool
+	pollInterval time.Duration
+
+	page     []EmittedEvent
+	pos      int
+	fetched  bool
+	retried  bool
+	current  *EmittedEvent
+	err      error
+	hasLast  bool
+	last     uint64
+	seen     map[eventKey]struct{}
+	pending  map[eventKey]struct{}
+	counters map[eventKey]int
+}
+
+// NewEventIterator creates an EventIterator over the events matching the input.
+//
+// Parameters:
+// - provider: the provider used for the starknet_getEvents requests
+// - input: the filter and the first page request, the chunk size defaults to DefaultEventChunkSize
+// - opts: the iterator options, nil to stop once the events of the filter are exhausted
+// Returns:
+// - *EventIterator: the iterator
+func NewEventIterator(provider RpcProvider, input EventsInput, opts *EventIteratorOptions) *EventIterator {
+	if opts == nil {
+		opts = &EventIteratorOptions{}
+	}
+	if input.ChunkSize <= 0 {
+		input.ChunkSize = DefaultEventChunkSize
+	}
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultEventPollInterval
+	}
+	return &EventIterator{
+		provider:     provider,
+		input:        input,
+		follow:       opts.Follow,
+		pollInterval: pollInterval,
+		seen:         map[eventKey]struct{}{},
+		pending:      map[eventKey]struct{}{},
+		counters:     map[eventKey]int{},
+	}
+}
+
+// Next advances the iterator to the next event, requesting the next page when needed.
+// It returns false when the events are exhausted, or when an error occurred, which
+// is then returned by Err. An iterator following the chain only stops on errors,
+// including the error of ctx.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// Returns:
+// - bool: true if Event returns the next event
+func (it *EventIterator) Next(ctx context.Context) bool {
+	for it.err == nil {
+		for it.pos < len(it.page) {
+			event := &it.page[it.pos]
+			it.pos++
+			if it.isDuplicate(event) {
+				continue
+			}
+			it.current = event
+			return true
+		}
+
+		if it.fetched && it.input.ContinuationToken == "" {
+			if !it.follow {
+				return false
+			}
+			select {
+			case <-ctx.Done():
+				it.err = ctx.Err()
+				return false
+			case <-time.After(it.pollInterval):
+			}
+			it.advance()
+		}
+		it.err = it.fetch(ctx)
+	}
+	return false
+}
+
+// Event returns the current event.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *EmittedEvent: the event of the last successful call to Next
+func (it *EventIterator) Event() *EmittedEvent {
+	return it.current
+}
+
+// Err returns the error that stopped the iterator.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error, nil if the events were exhausted
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// ChunkSize returns the chunk size of the requests, it shrinks on ErrPageSizeTooBig.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the chunk size
+func (it *EventIterator) ChunkSize() int {
+	return it.input.ChunkSize
+}
+
+// fetch requests the next page. The recoverable errors change the request
+// and return nil, so that the caller requests the page again.
+func (it *EventIterator) fetch(ctx context.Context) error {
+	if it.follow && it.input.ToBlock.Number == nil {
+		latest, err := it.provider.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		it.input.ToBlock = BlockID{Number: &latest}
+	}
+	if it.follow && it.input.FromBlock.Number != nil && *it.input.FromBlock.Number > *it.input.ToBlock.Number {
+		// no new block since the last query
+		it.page, it.pos = nil, 0
+		it.fetched = true
+		return nil
+	}
+
+	chunk, err := it.provider.Events(ctx, it.input)
+	switch {
+	case err == nil:
+		it.page, it.pos = chunk.Events, 0
+		it.input.ContinuationToken = chunk.ContinuationToken
+		it.fetched = true
+		it.retried = false
+		return nil
+	case errors.Is(err, ErrPageSizeTooBig):
+		if it.input.ChunkSize <= 1 {
+			return err
+		}
+		it.input.ChunkSize /= 2
+		return nil
+	case errors.Is(err, ErrInvalidContinuationToken):
+		// a second failure in a row means the restarted query fails as well
+		if it.retried {
+			return err
+		}
+		it.retried = true
+		it.restart()
+		return nil
+	}
+	return err
+}
+
+// restart queries again from the last block with events, or from the first
+// block of the filter if there were none.
+func (it *EventIterator) restart() {
+	it.input.ContinuationToken = ""
+	it.page, it.pos = nil, 0
+	it.fetched = false
+	it.counters = map[eventKey]int{}
+	if it.hasLast {
+		last := it.last
+		it.input.FromBlock = BlockID{Number: &last}
+	}
+}
+
+// advance queries the blocks following the last block of the completed query,
+// up to the latest block number resolved by fetch.
+func (it *EventIterator) advance() {
+	next := *it.input.ToBlock.Number + 1
+	it.input.FromBlock = BlockID{Number: &next}
+	it.input.ToBlock = BlockID{}
+	it.input.ContinuationToken = ""
+	it.page, it.pos = nil, 0
+	it.fetched = false
+	it.counters = map[eventKey]int{}
+}
+
+// isDuplicate reports whether the event was already delivered. Only the keys of
+// the last block with events are kept, since the queries restart from that block.
+// The pending events, which have no block, are identified by their transaction
+// and their index only.
+func (it *EventIterator) isDuplicate(event *EmittedEvent) bool {
+	key := eventKey{blockNumber: event.BlockNumber}
+	if event.BlockHash != nil {
+		key.blockHash = *event.BlockHash
+	}
+	if event.TransactionHash != nil {
+		key.transactionHash = *event.TransactionHash
+	}
+	key.index = it.counters[key]
+	it.counters[key]++
+
+	if event.BlockHash == nil {
+		if _, ok := it.pending[key]; ok {
+			return true
+		}
+		it.pending[key] = struct{}{}
+		return false
+	}
+	if _, ok := it.seen[key]; ok {
+		return true
+	}
+	if !it.hasLast || event.BlockNumber > it.last {
+		it.hasLast = true
+		it.last = event.BlockNumber
+		it.seen = map[eventKey]struct{}{}
+	}
+	it.seen[key] = struct{}{}
+	return false
+}
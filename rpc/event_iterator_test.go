@@ -0,0 +1,245 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+)
+
+// eventPage is the response of the fakeEventsProvider to one starknet_getEvents request.
+type eventPage struct {
+	chunk *EventChunk
+	err   error
+}
+
+// fakeEventsProvider answers the Events requests with the pages in order and records the inputs.
+// The BlockNumber requests are answered with the latest block numbers in order, the last one is kept.
+type fakeEventsProvider struct {
+	RpcProvider
+	pages  []eventPage
+	inputs []EventsInput
+	latest []uint64
+}
+
+func (p *fakeEventsProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	if len(p.latest) == 0 {
+		return 0, nil
+	}
+	latest := p.latest[0]
+	if len(p.latest) > 1 {
+		p.latest = p.latest[1:]
+	}
+	return latest, nil
+}
+
+func (p *fakeEventsProvider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
+	p.inputs = append(p.inputs, input)
+	if len(p.pages) == 0 {
+		return &EventChunk{}, nil
+	}
+	page := p.pages[0]
+	p.pages = p.pages[1:]
+	return page.chunk, page.err
+}
+
+func testEvent(block uint64, tx uint64, data uint64) EmittedEvent {
+	return EmittedEvent{
+		Event:           Event{Data: []*felt.Felt{new(felt.Felt).SetUint64(data)}},
+		BlockHash:       new(felt.Felt).SetUint64(0xb000 + block),
+		BlockNumber:     block,
+		TransactionHash: new(felt.Felt).SetUint64(tx),
+	}
+}
+
+// testPendingEvent is an event of the pending block, which has no block hash and number.
+func testPendingEvent(tx uint64, data uint64) EmittedEvent {
+	return EmittedEvent{
+		Event:           Event{Data: []*felt.Felt{new(felt.Felt).SetUint64(data)}},
+		TransactionHash: new(felt.Felt).SetUint64(tx),
+	}
+}
+
+func eventChunk(token string, events ...EmittedEvent) eventPage {
+	return eventPage{chunk: &EventChunk{Events: events, ContinuationToken: token}}
+}
+
+// collectEvents returns the data of the events delivered by the iterator.
+func collectEvents(ctx context.Context, it *EventIterator, max int) []uint64 {
+	data := []uint64{}
+	for len(data) < max && it.Next(ctx) {
+		data = append(data, it.Event().Data[0].BigInt(new(big.Int)).Uint64())
+	}
+	return data
+}
+
+// TestEventIterator tests the pagination, the recovery of the page size and
+// continuation token errors and following the chain.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEventIterator(t *testing.T) {
+	from := uint64(10)
+	type testSetType struct {
+		Name           string
+		Pages          []eventPage
+		Latest         []uint64
+		ToBlock        BlockID
+		ChunkSize      int
+		Options        *EventIteratorOptions
+		Max            int
+		ExpectedData   []uint64
+		ExpectedErr    error
+		ExpectedInputs func(t *testing.T, inputs []EventsInput)
+	}
+	testSet := []testSetType{
+		{
+			Name: "pages",
+			Pages: []eventPage{
+				eventChunk("1", testEvent(10, 1, 1), testEvent(10, 1, 2)),
+				eventChunk("2", testEvent(11, 2, 3)),
+				eventChunk("", testEvent(12, 3, 4)),
+			},
+			ExpectedData: []uint64{1, 2, 3, 4},
+			ExpectedInputs: func(t *testing.T, inputs []EventsInput) {
+				require.Len(t, inputs, 3)
+				require.Equal(t, "", inputs[0].ContinuationToken)
+				require.Equal(t, DefaultEventChunkSize, inputs[0].ChunkSize)
+				require.Equal(t, "1", inputs[1].ContinuationToken)
+				require.Equal(t, "2", inputs[2].ContinuationToken)
+			},
+		},
+		{
+			Name: "page size too big",
+			Pages: []eventPage{
+				{err: ErrPageSizeTooBig},
+				{err: ErrPageSizeTooBig},
+				eventChunk("", testEvent(10, 1, 1)),
+			},
+			ExpectedData: []uint64{1},
+			ExpectedInputs: func(t *testing.T, inputs []EventsInput) {
+				require.Len(t, inputs, 3)
+				require.Equal(t, DefaultEventChunkSize/2, inputs[1].ChunkSize)
+				require.Equal(t, DefaultEventChunkSize/4, inputs[2].ChunkSize)
+			},
+		},
+		{
+			Name: "invalid continuation token",
+			Pages: []eventPage{
+				eventChunk("1", testEvent(10, 1, 1), testEvent(11, 2, 2)),
+				{err: ErrInvalidContinuationToken},
+				// the query restarts from block 11, its events are not delivered twice
+				eventChunk("", testEvent(11, 2, 2), testEvent(11, 3, 3)),
+			},
+			ExpectedData: []uint64{1, 2, 3},
+			ExpectedInputs: func(t *testing.T, inputs []EventsInput) {
+				require.Len(t, inputs, 3)
+				require.Equal(t, uint64(11), *inputs[2].FromBlock.Number)
+				require.Equal(t, "", inputs[2].ContinuationToken)
+			},
+		},
+		{
+			Name: "invalid continuation token twice",
+			Pages: []eventPage{
+				eventChunk("1", testEvent(10, 1, 1)),
+				{err: ErrInvalidContinuationToken},
+				{err: ErrInvalidContinuationToken},
+			},
+			ExpectedData: []uint64{1},
+			ExpectedErr:  ErrInvalidContinuationToken,
+		},
+		{
+			Name: "unrecoverable page size",
+			Pages: []eventPage{
+				{err: ErrPageSizeTooBig},
+			},
+			ChunkSize:    1,
+			ExpectedData: []uint64{},
+			ExpectedErr:  ErrPageSizeTooBig,
+		},
+		{
+			Name: "pending events",
+			Pages: []eventPage{
+				eventChunk("1", testPendingEvent(1, 1), testPendingEvent(1, 2)),
+				{err: ErrInvalidContinuationToken},
+				// the query restarts, the pending events are identified by transaction and index
+				eventChunk("", testPendingEvent(1, 1), testPendingEvent(1, 2), testPendingEvent(2, 3)),
+			},
+			ToBlock:      BlockID{Tag: "pending"},
+			ExpectedData: []uint64{1, 2, 3},
+			ExpectedInputs: func(t *testing.T, inputs []EventsInput) {
+				require.Len(t, inputs, 3)
+				require.Equal(t, from, *inputs[2].FromBlock.Number)
+			},
+		},
+		{
+			Name: "follow",
+			Pages: []eventPage{
+				eventChunk("", testEvent(10, 1, 1), testEvent(11, 2, 2)),
+				// the same transaction emits two events in block 12
+				eventChunk("", testEvent(12, 3, 3), testEvent(12, 3, 4), testEvent(13, 4, 5)),
+			},
+			// no new block at the first poll
+			Latest:       []uint64{11, 11, 13},
+			ToBlock:      BlockID{Tag: "pending"},
+			Options:      &EventIteratorOptions{Follow: true, PollInterval: time.Millisecond},
+			Max:          5,
+			ExpectedData: []uint64{1, 2, 3, 4, 5},
+			ExpectedInputs: func(t *testing.T, inputs []EventsInput) {
+				require.Len(t, inputs, 2)
+				require.Equal(t, from, *inputs[0].FromBlock.Number)
+				require.Equal(t, uint64(11), *inputs[0].ToBlock.Number)
+				require.Equal(t, uint64(12), *inputs[1].FromBlock.Number)
+				require.Equal(t, uint64(13), *inputs[1].ToBlock.Number)
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			provider := &fakeEventsProvider{pages: test.Pages, latest: test.Latest}
+			input := EventsInput{
+				EventFilter:       EventFilter{FromBlock: BlockID{Number: &from}, ToBlock: test.ToBlock},
+				ResultPageRequest: ResultPageRequest{ChunkSize: test.ChunkSize},
+			}
+			it := NewEventIterator(provider, input, test.Options)
+
+			max := test.Max
+			if max == 0 {
+				max = 100
+			}
+			require.Equal(t, test.ExpectedData, collectEvents(context.Background(), it, max))
+			if test.ExpectedErr != nil {
+				require.True(t, errors.Is(it.Err(), test.ExpectedErr))
+			} else {
+				require.NoError(t, it.Err())
+			}
+			if test.ExpectedInputs != nil {
+				test.ExpectedInputs(t, provider.inputs)
+			}
+		})
+	}
+}
+
+// TestEventIteratorFollowCancel tests that following the chain stops with the error of the context.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEventIteratorFollowCancel(t *testing.T) {
+	provider := &fakeEventsProvider{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	t.Cleanup(cancel)
+	it := NewEventIterator(provider, EventsInput{}, &EventIteratorOptions{Follow: true, PollInterval: time.Millisecond})
+	require.False(t, it.Next(ctx))
+	require.True(t, errors.Is(it.Err(), context.DeadlineExceeded))
+}
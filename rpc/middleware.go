@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchMethod is the Request method of the JSON-RPC batches sent by Batch.
+const BatchMethod = "batch"
+
+// Request is a JSON-RPC request going through the middlewares.
+type Request struct {
+	// Method is the JSON-RPC method, or BatchMethod for a batch
+	Method string
+	// Params are the parameters of the request
+	Params []interface{}
+	// Batch holds the requests of a batch, their results and errors are set once it is sent
+	Batch []ethrpc.BatchElem
+}
+
+// Handler sends a Request and returns its raw JSON result.
+type Handler func(ctx context.Context, req *Request) (json.RawMessage, error)
+
+// Middleware wraps the Handler of the next middleware of the chain. It can
+// inspect the request before calling next, and the result, the error and
+// the latency after.
+type Middleware func(next Handler) Handler
+
+// middlewareClient is a callCloser sending the requests through a middleware chain.
+// Subscriptions are not observed by the middlewares.
+type middlewareClient struct {
+	c           callCloser
+	middlewares []Middleware
+	handler     Handler
+}
+
+// Use adds middlewares around every JSON-RPC request of the provider, batches
+// included. The first middleware is the outermost: it sees the request first and
+// the result last. The middlewares added by later calls are inside the previous ones.
+//
+// Parameters:
+// - middlewares: the middlewares to add
+// Returns:
+//
+//	none
+func (provider *Provider) Use(middlewares ...Middleware) {
+	client, ok := provider.c.(*middlewareClient)
+	if !ok {
+		client = &middlewareClient{c: provider.c}
+		provider.c = client
+	}
+	client.middlewares = append(client.middlewares, middlewares...)
+	client.handler = client.send
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		client.handler = client.middlewares[i](client.handler)
+	}
+}
+
+// CallContext sends the request through the middlewares and decodes its result.
+func (m *middlewareClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	raw, err := m.handler(ctx, &Request{Method: method, Params: args})
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+	if r, ok := result.(*json.RawMessage); ok {
+		*r = raw
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// BatchCallContext sends the batch through the middlewares as a single request.
+func (m *middlewareClient) BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error {
+	_, err := m.handler(ctx, &Request{Method: BatchMethod, Batch: b})
+	return err
+}
+
+// subscribe subscribes with the underlying client, without the middlewares.
+func (m *middlewareClient) subscribe(ctx context.Context, method string, params interface{}) (*clientSubscription, error) {
+	client, ok := m.c.(subscriber)
+	if !ok {
+		return nil, ErrSubscriptionNotSupported
+	}
+	return client.subscribe(ctx, method, params)
+}
+
+func (m *middlewareClient) Close() {
+	m.c.Close()
+}
+
+// send is the innermost Handler, it sends the request with the underlying client.
+func (m *middlewareClient) send(ctx context.Context, req *Request) (json.RawMessage, error) {
+	if req.Method == BatchMethod {
+		if err := batchCall(ctx, m.c, req.Batch); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	var raw json.RawMessage
+	if err := m.c.CallContext(ctx, &raw, req.Method, req.Params...); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// ResultSize returns the size in bytes of the JSON result of a request: raw
+// for a single request, the sum of the results of the elements of a batch.
+//
+// Parameters:
+// - req: the request
+// - raw: the result returned by the Handler
+// Returns:
+// - int: the size of the result
+func ResultSize(req *Request, raw json.RawMessage) int {
+	if req.Method != BatchMethod {
+		return len(raw)
+	}
+	size := 0
+	for _, elem := range req.Batch {
+		if result, ok := elem.Result.(*json.RawMessage); ok {
+			size += len(*result)
+		}
+	}
+	return size
+}
+
+// batchErrors returns the errors of the elements of a batch as *RPCError when they
+// have a code, prefixed with their method, nil for a single request.
+func batchErrors(req *Request) []error {
+	var errs []error
+	for _, elem := range req.Batch {
+		if elem.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", elem.Method, tryUnwrapToRPCErr(elem.Error)))
+		}
+	}
+	return errs
+}
+
+// errorCode returns the JSON-RPC code of err, 0 if it has none.
+func errorCode(err error) int {
+	var codeErr interface{ ErrorCode() int }
+	if errors.As(err, &codeErr) {
+		return codeErr.ErrorCode()
+	}
+	return 0
+}
+
+// LoggingMiddleware logs every request with its latency and result size at the
+// debug level, and the failed requests and elements of batches at the error level.
+//
+// Parameters:
+// - logger: the logger, slog.Default() when nil
+// Returns:
+// - Middleware: the logging middleware
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (json.RawMessage, error) {
+			start := time.Now()
+			raw, err := next(ctx, req)
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				if code := errorCode(err); code != 0 {
+					attrs = append(attrs, slog.Int("code", code))
+				}
+				logger.LogAttrs(ctx, slog.LevelError, "starknet rpc request failed", attrs...)
+				return raw, err
+			}
+			for i, elem := range req.Batch {
+				if elem.Error == nil {
+					continue
+				}
+				elemAttrs := []slog.Attr{
+					slog.String("method", elem.Method),
+					slog.Int("batch_index", i),
+					slog.String("error", elem.Error.Error()),
+				}
+				if code := errorCode(elem.Error); code != 0 {
+					elemAttrs = append(elemAttrs, slog.Int("code", code))
+				}
+				logger.LogAttrs(ctx, slog.LevelError, "starknet rpc batch request failed", elemAttrs...)
+			}
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				attrs = append(attrs, slog.Int("result_size", ResultSize(req, raw)), slog.String("params", formatParams(req)))
+				logger.LogAttrs(ctx, slog.LevelDebug, "starknet rpc request", attrs...)
+			}
+			return raw, nil
+		}
+	}
+}
+
+// formatParams returns the JSON of the params, or the methods of a batch.
+func formatParams(req *Request) string {
+	if req.Method == BatchMethod {
+		methods := make([]string, len(req.Batch))
+		for i, elem := range req.Batch {
+			methods[i] = elem.Method
+		}
+		return fmt.Sprint(methods)
+	}
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		return fmt.Sprint(req.Params)
+	}
+	return string(params)
+}
+
+// Tracer starts spans. The otelrpc package adapts an OpenTelemetry tracer.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// TracingMiddleware starts a span for every request, named after its method and
+// with the attributes of the OpenTelemetry JSON-RPC semantic conventions
+// (rpc.system, rpc.method, rpc.jsonrpc.error_code, ...). The errors of the
+// elements of a batch are recorded on its span and counted in the
+// rpc.batch.errors attribute. See the otelrpc package for OpenTelemetry spans.
+//
+// Parameters:
+// - tracer: the tracer starting the spans
+// Returns:
+// - Middleware: the tracing middleware
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (json.RawMessage, error) {
+			ctx, span := tracer.Start(ctx, req.Method)
+			defer span.End()
+			span.SetAttribute("rpc.system", "jsonrpc")
+			span.SetAttribute("rpc.jsonrpc.version", "2.0")
+			span.SetAttribute("rpc.method", req.Method)
+			if req.Method == BatchMethod {
+				span.SetAttribute("rpc.batch.size", len(req.Batch))
+			}
+
+			raw, err := next(ctx, req)
+			if err != nil {
+				if code := errorCode(err); code != 0 {
+					span.SetAttribute("rpc.jsonrpc.error_code", code)
+				}
+				span.RecordError(err)
+				return raw, err
+			}
+			if errs := batchErrors(req); len(errs) > 0 {
+				span.SetAttribute("rpc.batch.errors", len(errs))
+				for _, err := range errs {
+					span.RecordError(err)
+				}
+			}
+			span.SetAttribute("rpc.response.size", ResultSize(req, raw))
+			return raw, nil
+		}
+	}
+}
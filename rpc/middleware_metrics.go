@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects Prometheus-style metrics of the requests going through its middleware:
+//
+//   - starknet_rpc_requests_total: counter of the requests by method and status (ok or error)
+//   - starknet_rpc_request_duration_seconds: histogram of the latency of the requests by method
+//   - starknet_rpc_response_size_bytes_total: counter of the size of the results by method
+//
+// Metrics is an http.Handler serving the metrics in the Prometheus text format.
+type Metrics struct {
+	buckets []float64
+
+	mu      sync.Mutex
+	methods map[string]*methodMetrics
+}
+
+type methodMetrics struct {
+	ok, errors    uint64
+	bucketCounts  []uint64
+	durationSum   float64
+	responseBytes uint64
+}
+
+// NewMetrics creates a new Metrics.
+//
+// Parameters:
+// - buckets: the upper bounds in seconds of the latency histogram buckets, DefaultLatencyBuckets if empty
+// Returns:
+// - *Metrics: the metrics, without any request
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Metrics{buckets: sorted, methods: map[string]*methodMetrics{}}
+}
+
+// Middleware returns the middleware recording the requests in the metrics.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - Middleware: the metrics middleware
+func (m *Metrics) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (json.RawMessage, error) {
+			start := time.Now()
+			raw, err := next(ctx, req)
+			m.observe(req.Method, time.Since(start), ResultSize(req, raw), err)
+			return raw, err
+		}
+	}
+}
+
+func (m *Metrics) observe(method string, duration time.Duration, size int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics, ok := m.methods[method]
+	if !ok {
+		metrics = &methodMetrics{bucketCounts: make([]uint64, len(m.buckets))}
+		m.methods[method] = metrics
+	}
+	if err != nil {
+		metrics.errors++
+	} else {
+		metrics.ok++
+		metrics.responseBytes += uint64(size)
+	}
+	seconds := duration.Seconds()
+	metrics.durationSum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			metrics.bucketCounts[i]++
+		}
+	}
+}
+
+// Requests returns the number of requests of a method with the given status.
+//
+// Parameters:
+// - method: the JSON-RPC method
+// - failed: true for the failed requests, false for the successful ones
+// Returns:
+// - uint64: the number of requests
+func (m *Metrics) Requests(method string, failed bool) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics, ok := m.methods[method]
+	if !ok {
+		return 0
+	}
+	if failed {
+		return metrics.errors
+	}
+	return metrics.ok
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+//
+// Parameters:
+// - w: the writer
+// Returns:
+// - int64: the number of bytes written
+// - error: an error if the write failed
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	methods := make([]string, 0, len(m.methods))
+	for method := range m.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var b strings.Builder
+	b.WriteString("# HELP starknet_rpc_requests_total Number of Starknet JSON-RPC requests.\n")
+	b.WriteString("# TYPE starknet_rpc_requests_total counter\n")
+	for _, method := range methods {
+		metrics := m.methods[method]
+		fmt.Fprintf(&b, "starknet_rpc_requests_total{method=%q,status=\"error\"} %d\n", method, metrics.errors)
+		fmt.Fprintf(&b, "starknet_rpc_requests_total{method=%q,status=\"ok\"} %d\n", method, metrics.ok)
+	}
+	b.WriteString("# HELP starknet_rpc_request_duration_seconds Latency of the Starknet JSON-RPC requests.\n")
+	b.WriteString("# TYPE starknet_rpc_request_duration_seconds histogram\n")
+	for _, method := range methods {
+		metrics := m.methods[method]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(&b, "starknet_rpc_request_duration_seconds_bucket{method=%q,le=%q} %d\n", method, strconv.FormatFloat(bound, 'g', -1, 64), metrics.bucketCounts[i])
+		}
+		count := metrics.ok + metrics.errors
+		fmt.Fprintf(&b, "starknet_rpc_request_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", method, count)
+		fmt.Fprintf(&b, "starknet_rpc_request_duration_seconds_sum{method=%q} %s\n", method, strconv.FormatFloat(metrics.durationSum, 'g', -1, 64))
+		fmt.Fprintf(&b, "starknet_rpc_request_duration_seconds_count{method=%q} %d\n", method, count)
+	}
+	b.WriteString("# HELP starknet_rpc_response_size_bytes_total Size of the results of the Starknet JSON-RPC requests.\n")
+	b.WriteString("# TYPE starknet_rpc_response_size_bytes_total counter\n")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "starknet_rpc_response_size_bytes_total{method=%q} %d\n", method, m.methods[method].responseBytes)
+	}
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = m.WriteTo(w)
+}
@@ -0,0 +1,182 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/test-go/testify/require"
+)
+
+// middlewareService is an in-process node serving the methods used by the middleware tests.
+type middlewareService struct{}
+
+func (s *middlewareService) BlockNumber() uint64 {
+	return 42
+}
+
+func (s *middlewareService) GetNonce(blockID json.RawMessage, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return nil, codeError{code: ErrContractNotFound.Code(), message: ErrContractNotFound.Error()}
+}
+
+func newMiddlewareProvider(t *testing.T) *Provider {
+	server := ethrpc.NewServer()
+	require.NoError(t, server.RegisterName("starknet", &middlewareService{}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	client, err := NewClient(httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return NewProvider(client)
+}
+
+// recordingSpan is a Span keeping its attributes and errors.
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	errs       []error
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *recordingSpan) End()                                       { s.ended = true }
+
+// recordingTracer is a Tracer keeping the spans it starts.
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &recordingSpan{name: spanName, attributes: map[string]interface{}{}}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+// TestMiddleware tests that the middlewares see every request with its result or
+// error, in the order they were added.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMiddleware(t *testing.T) {
+	provider := newMiddlewareProvider(t)
+
+	var calls []string
+	var sizes []int
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (json.RawMessage, error) {
+				calls = append(calls, name+" "+req.Method)
+				raw, err := next(ctx, req)
+				if err == nil {
+					sizes = append(sizes, ResultSize(req, raw))
+				}
+				return raw, err
+			}
+		}
+	}
+	provider.Use(record("outer"))
+	provider.Use(record("inner"))
+
+	blockNumber, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), blockNumber)
+
+	_, err = provider.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+	require.True(t, errors.Is(err, ErrContractNotFound))
+
+	responses, err := provider.Batch().
+		Nonce(WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1")).
+		Send(context.Background())
+	require.NoError(t, err)
+	require.True(t, errors.Is(responses[0].Err, ErrContractNotFound))
+
+	require.Equal(t, []string{
+		"outer starknet_blockNumber", "inner starknet_blockNumber",
+		"outer starknet_getNonce", "inner starknet_getNonce",
+		"outer batch", "inner batch",
+	}, calls)
+	require.Equal(t, []int{2, 2, 0, 0}, sizes)
+}
+
+// TestBuiltinMiddlewares tests the logging, metrics and tracing middlewares.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBuiltinMiddlewares(t *testing.T) {
+	provider := newMiddlewareProvider(t)
+	var logs bytes.Buffer
+	metrics := NewMetrics()
+	tracer := &recordingTracer{}
+	provider.Use(
+		LoggingMiddleware(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		metrics.Middleware(),
+		TracingMiddleware(tracer),
+	)
+
+	_, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	_, err = provider.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+	require.Error(t, err)
+	// the batch succeeds with a failed element
+	_, err = provider.Batch().
+		ClassHashAt(WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1")).
+		Nonce(WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1")).
+		Send(context.Background())
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Contains(t, lines[0], "level=DEBUG")
+	require.Contains(t, lines[0], "method=starknet_blockNumber")
+	require.Contains(t, lines[0], "result_size=2")
+	require.Contains(t, lines[1], "level=ERROR")
+	require.Contains(t, lines[1], "method=starknet_getNonce")
+	require.Contains(t, lines[1], "code=20")
+	require.Contains(t, lines[2], "level=ERROR")
+	require.Contains(t, lines[2], "method=starknet_getClassHashAt")
+	require.Contains(t, lines[2], "batch_index=0")
+	require.Contains(t, lines[3], "method=starknet_getNonce")
+	require.Contains(t, lines[3], "batch_index=1")
+	require.Contains(t, lines[3], "code=20")
+	require.Contains(t, lines[4], "level=DEBUG")
+	require.Contains(t, lines[4], "method=batch")
+
+	require.Equal(t, uint64(1), metrics.Requests("starknet_blockNumber", false))
+	require.Equal(t, uint64(1), metrics.Requests("starknet_getNonce", true))
+	var exposition bytes.Buffer
+	_, err = metrics.WriteTo(&exposition)
+	require.NoError(t, err)
+	require.Contains(t, exposition.String(), `starknet_rpc_requests_total{method="starknet_getNonce",status="error"} 1`)
+	require.Contains(t, exposition.String(), `starknet_rpc_request_duration_seconds_count{method="starknet_blockNumber"} 1`)
+	require.Contains(t, exposition.String(), `starknet_rpc_response_size_bytes_total{method="starknet_blockNumber"} 2`)
+
+	require.Len(t, tracer.spans, 3)
+	require.Equal(t, "starknet_blockNumber", tracer.spans[0].name)
+	require.Equal(t, "jsonrpc", tracer.spans[0].attributes["rpc.system"])
+	require.True(t, tracer.spans[0].ended)
+	require.Empty(t, tracer.spans[0].errs)
+	require.Equal(t, 20, tracer.spans[1].attributes["rpc.jsonrpc.error_code"])
+	require.Len(t, tracer.spans[1].errs, 1)
+	require.Equal(t, BatchMethod, tracer.spans[2].name)
+	require.Equal(t, 2, tracer.spans[2].attributes["rpc.batch.errors"])
+	require.Len(t, tracer.spans[2].errs, 2)
+	require.True(t, errors.Is(tracer.spans[2].errs[1], ErrContractNotFound))
+}
@@ -61,6 +61,8 @@ func (r *rpcMock) CallContext(ctx context.Context, result interface{}, method st
 		return mock_starknet_estimateMessageFee(result, method, args...)
 	case "starknet_getBlockTransactionCount":
 		return mock_starknet_getBlockTransactionCount(result, method, args...)
+	case "starknet_getBlockWithReceipts":
+		return mock_starknet_getBlockWithReceipts(result, method, args...)
 	case "starknet_getBlockWithTxHashes":
 		return mock_starknet_getBlockWithTxHashes(result, method, args...)
 	case "starknet_getClass":
@@ -69,14 +71,20 @@ func (r *rpcMock) CallContext(ctx context.Context, result interface{}, method st
 		return mock_starknet_getClassAt(result, method, args...)
 	case "starknet_getClassHashAt":
 		return mock_starknet_getClassHashAt(result, method, args...)
+	case "starknet_getCompiledCasm":
+		return mock_starknet_getCompiledCasm(result, method, args...)
 	case "starknet_getEvents":
 		return mock_starknet_getEvents(result, method, args...)
+	case "starknet_getMessagesStatus":
+		return mock_starknet_getMessagesStatus(result, method, args...)
 	case "starknet_getNonce":
 		return mock_starknet_getNonce(result, method, args...)
 	case "starknet_getStateUpdate":
 		return mock_starknet_getStateUpdate(result, method, args...)
 	case "starknet_getStorageAt":
 		return mock_starknet_getStorageAt(result, method, args...)
+	case "starknet_getStorageProof":
+		return mock_starknet_getStorageProof(result, method, args...)
 	case "starknet_getTransactionByBlockIdAndIndex":
 		return mock_starknet_getTransactionByBlockIdAndIndex(result, method, args...)
 	case "starknet_getTransactionByHash":
@@ -1072,3 +1080,144 @@ func mock_starknet_traceTransaction(result interface{}, method string, args ...i
 		return ErrInvalidTxnHash
 	}
 }
+
+// mock_starknet_getBlockWithReceipts mocks the starknet_getBlockWithReceipts method.
+// The latest block is read from a file, the pending block is the same block without its hash,
+// number, status and root.
+//
+// Parameters:
+// - result: the result is expected to be a pointer to json.RawMessage
+// - method: the method to be called
+// - args: variadic parameter that can contain any number of arguments
+// Returns:
+// - error: an error if any
+func mock_starknet_getBlockWithReceipts(result interface{}, method string, args ...interface{}) error {
+	r, ok := result.(*json.RawMessage)
+	if !ok || r == nil {
+		return errWrongType
+	}
+	if len(args) != 1 {
+		return errWrongArgs
+	}
+	blockID, ok := args[0].(BlockID)
+	if !ok {
+		return errors.Wrap(errWrongArgs, fmt.Sprintf("args[0] should be BlockID, got %T\n", args[0]))
+	}
+
+	read, err := os.ReadFile("tests/block/blockWithReceipts.json")
+	if err != nil {
+		return err
+	}
+	switch blockID.Tag {
+	case "latest":
+		*r = read
+		return nil
+	case "pending":
+		var block map[string]interface{}
+		if err := json.Unmarshal(read, &block); err != nil {
+			return err
+		}
+		for _, field := range []string{"status", "block_hash", "block_number", "new_root"} {
+			delete(block, field)
+		}
+		pending, err := json.Marshal(block)
+		if err != nil {
+			return err
+		}
+		*r = pending
+		return nil
+	}
+	return ErrBlockNotFound
+}
+
+// mock_starknet_getMessagesStatus mocks the starknet_getMessagesStatus method.
+//
+// Parameters:
+// - result: the result is expected to be a pointer to json.RawMessage
+// - method: the method to be called
+// - args: variadic parameter that can contain any number of arguments
+// Returns:
+// - error: an error if any
+func mock_starknet_getMessagesStatus(result interface{}, method string, args ...interface{}) error {
+	r, ok := result.(*json.RawMessage)
+	if !ok || r == nil {
+		return errWrongType
+	}
+	if len(args) != 1 {
+		return errWrongArgs
+	}
+	transactionHash, ok := args[0].(NumAsHex)
+	if !ok {
+		return errors.Wrap(errWrongArgs, fmt.Sprintf("args[0] should be NumAsHex, got %T\n", args[0]))
+	}
+	if transactionHash != "0x6c5ca4e7eb5f7b1c2b0e1e5b1d5a0f3c9d5d8f5b1e6a2c4d8f1a3b5c7d9e1f3a" {
+		return ErrHashNotFound
+	}
+	*r = json.RawMessage(`[
+		{"transaction_hash": "0x1f2a3c", "finality_status": "ACCEPTED_ON_L2", "execution_status": "SUCCEEDED"},
+		{"transaction_hash": "0x4b5d6e", "finality_status": "ACCEPTED_ON_L2", "execution_status": "REVERTED", "failure_reason": "Assertion failed"}
+	]`)
+	return nil
+}
+
+// mock_starknet_getStorageProof mocks the starknet_getStorageProof method, it reads the proof from a file.
+//
+// Parameters:
+// - result: the result is expected to be a pointer to json.RawMessage
+// - method: the method to be called
+// - args: variadic parameter that can contain any number of arguments
+// Returns:
+// - error: an error if any
+func mock_starknet_getStorageProof(result interface{}, method string, args ...interface{}) error {
+	r, ok := result.(*json.RawMessage)
+	if !ok || r == nil {
+		return errWrongType
+	}
+	if len(args) != 4 {
+		return errWrongArgs
+	}
+	blockID, ok := args[0].(BlockID)
+	if !ok {
+		return errors.Wrap(errWrongArgs, fmt.Sprintf("args[0] should be BlockID, got %T\n", args[0]))
+	}
+	if blockID.Tag == "pending" {
+		return ErrStorageProofNotSupported
+	}
+	read, err := os.ReadFile("tests/contract/storageProof.json")
+	if err != nil {
+		return err
+	}
+	*r = read
+	return nil
+}
+
+// mock_starknet_getCompiledCasm mocks the starknet_getCompiledCasm method, it reads the class from a file.
+//
+// Parameters:
+// - result: the result is expected to be a pointer to json.RawMessage
+// - method: the method to be called
+// - args: variadic parameter that can contain any number of arguments
+// Returns:
+// - error: an error if any
+func mock_starknet_getCompiledCasm(result interface{}, method string, args ...interface{}) error {
+	r, ok := result.(*json.RawMessage)
+	if !ok || r == nil {
+		return errWrongType
+	}
+	if len(args) != 1 {
+		return errWrongArgs
+	}
+	classHash, ok := args[0].(*felt.Felt)
+	if !ok {
+		return errors.Wrap(errWrongArgs, fmt.Sprintf("args[0] should be felt, got %T\n", args[0]))
+	}
+	if classHash.String() != "0xdeadbeef" {
+		return ErrClassHashNotFound
+	}
+	read, err := os.ReadFile("../contracts/tests/hello_starknet_compiled.casm.json")
+	if err != nil {
+		return err
+	}
+	*r = read
+	return nil
+}
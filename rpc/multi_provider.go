@@ -0,0 +1,812 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries of a MultiProvider request
+	DefaultMaxRetries = 3
+	// DefaultInitialBackoff is the delay before the first retry of a MultiProvider request
+	DefaultInitialBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff is the maximum delay between the retries of a MultiProvider request
+	DefaultMaxBackoff = 5 * time.Second
+	// DefaultMaxBlockLag is the number of blocks an endpoint can lag behind the others before it is quarantined
+	DefaultMaxBlockLag = 10
+	// DefaultQuarantineDuration is the time an unhealthy endpoint is not used
+	DefaultQuarantineDuration = 30 * time.Second
+)
+
+var (
+	ErrNoProviders       = errors.New("at least one provider is required")
+	ErrNoHealthyProvider = errors.New("no healthy provider")
+)
+
+// MultiProviderOptions configures a MultiProvider.
+type MultiProviderOptions struct {
+	// MaxRetries is the number of retries of a request after a transient error, DefaultMaxRetries when zero
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, DefaultInitialBackoff when zero.
+	// The delay doubles at each retry and a random jitter of up to half the delay is removed.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries, DefaultMaxBackoff when zero
+	MaxBackoff time.Duration
+	// MaxBlockLag is the number of blocks an endpoint can lag behind the most
+	// advanced one before CheckHealth quarantines it, DefaultMaxBlockLag when zero
+	MaxBlockLag uint64
+	// QuarantineDuration is the time an unhealthy endpoint is skipped, DefaultQuarantineDuration when zero
+	QuarantineDuration time.Duration
+	// TransactionHash computes the hash of a BroadcastInvokeTxnType, BroadcastDeclareTxnType
+	// or BroadcastAddDeployTxnType. When it is set, a transaction whose submission failed
+	// with a transient error is sent again if its hash is unknown to the network, or its
+	// hash is returned if it is known or if the transaction sent again is rejected as a
+	// duplicate. When nil, the transactions are never sent twice.
+	TransactionHash func(tx interface{}) (*felt.Felt, error)
+}
+
+// endpoint is a provider of a MultiProvider with its health.
+type endpoint struct {
+	provider         RpcProvider
+	quarantinedUntil time.Time
+}
+
+// MultiProvider is a RpcProvider over several providers. Reads are retried on
+// transient errors (connection errors, timeouts, HTTP 5xx and 429) with an
+// exponential backoff, on the next healthy provider. Providers failing with a
+// connection error, or lagging behind the others when CheckHealth runs, are
+// quarantined. The providers are used in order of preference.
+type MultiProvider struct {
+	opts MultiProviderOptions
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+var _ RpcProvider = (*MultiProvider)(nil)
+
+// NewMultiProvider creates a MultiProvider over the providers, the first one being preferred.
+//
+// Parameters:
+// - providers: the underlying providers
+// - opts: the retry and health options, nil for the defaults
+// Returns:
+// - *MultiProvider: the provider
+// - error: ErrNoProviders if providers is empty
+func NewMultiProvider(providers []RpcProvider, opts *MultiProviderOptions) (*MultiProvider, error) {
+	if len(providers) == 0 {
+		return nil, ErrNoProviders
+	}
+	m := &MultiProvider{}
+	if opts != nil {
+		m.opts = *opts
+	}
+	if m.opts.MaxRetries == 0 {
+		m.opts.MaxRetries = DefaultMaxRetries
+	}
+	if m.opts.InitialBackoff == 0 {
+		m.opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if m.opts.MaxBackoff == 0 {
+		m.opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if m.opts.MaxBlockLag == 0 {
+		m.opts.MaxBlockLag = DefaultMaxBlockLag
+	}
+	if m.opts.QuarantineDuration == 0 {
+		m.opts.QuarantineDuration = DefaultQuarantineDuration
+	}
+	for _, provider := range providers {
+		m.endpoints = append(m.endpoints, &endpoint{provider: provider})
+	}
+	return m, nil
+}
+
+// DialMultiProvider creates a MultiProvider over the JSON-RPC endpoints at urls.
+//
+// Parameters:
+// - urls: the URLs of the endpoints, the first one being preferred
+// - opts: the retry and health options, nil for the defaults
+// Returns:
+// - *MultiProvider: the provider
+// - error: an error if an URL is invalid
+func DialMultiProvider(urls []string, opts *MultiProviderOptions) (*MultiProvider, error) {
+	providers := make([]RpcProvider, 0, len(urls))
+	for _, url := range urls {
+		client, err := NewClient(url)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, NewProvider(client))
+	}
+	return NewMultiProvider(providers, opts)
+}
+
+// CheckHealth queries the block number and the sync status of every provider.
+// The providers failing, or lagging more than MaxBlockLag blocks behind the most
+// advanced one, are quarantined, the others are released from quarantine.
+// Run it periodically, see MonitorHealth.
+//
+// Parameters:
+// - ctx: the context.Context for the requests
+// Returns:
+// - error: ErrNoHealthyProvider if every provider is unhealthy
+func (m *MultiProvider) CheckHealth(ctx context.Context) error {
+	m.mu.Lock()
+	endpoints := append([]*endpoint(nil), m.endpoints...)
+	m.mu.Unlock()
+
+	type health struct {
+		blockNumber uint64
+		healthy     bool
+	}
+	results := make([]health, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			blockNumber, err := ep.provider.BlockNumber(ctx)
+			if err != nil {
+				return
+			}
+			status, err := ep.provider.Syncing(ctx)
+			if err != nil || syncLag(status) > m.opts.MaxBlockLag {
+				return
+			}
+			results[i] = health{blockNumber: blockNumber, healthy: true}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, result := range results {
+		if result.healthy && result.blockNumber > highest {
+			highest = result.blockNumber
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	healthy := 0
+	for i, ep := range endpoints {
+		if results[i].healthy && highest-results[i].blockNumber <= m.opts.MaxBlockLag {
+			ep.quarantinedUntil = time.Time{}
+			healthy++
+			continue
+		}
+		ep.quarantinedUntil = time.Now().Add(m.opts.QuarantineDuration)
+	}
+	if healthy == 0 {
+		return ErrNoHealthyProvider
+	}
+	return nil
+}
+
+// MonitorHealth runs CheckHealth every interval until ctx is done.
+//
+// Parameters:
+// - ctx: the context.Context stopping the monitoring
+// - interval: the interval between the checks
+// Returns:
+//
+//	none
+func (m *MultiProvider) MonitorHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = m.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncLag returns the number of blocks a syncing node is behind the highest block.
+func syncLag(status *SyncStatus) uint64 {
+	if status == nil || !status.SyncStatus {
+		return 0
+	}
+	current, ok := new(big.Int).SetString(string(status.CurrentBlockNum), 0)
+	if !ok {
+		return 0
+	}
+	highest, ok := new(big.Int).SetString(string(status.HighestBlockNum), 0)
+	if !ok || highest.Cmp(current) <= 0 {
+		return 0
+	}
+	lag := new(big.Int).Sub(highest, current)
+	if !lag.IsUint64() {
+		return ^uint64(0)
+	}
+	return lag.Uint64()
+}
+
+// next returns the preferred healthy endpoint that was not tried yet. When every
+// endpoint was tried, they can be tried again. When every endpoint is
+// quarantined, the one released first is returned.
+func (m *MultiProvider) next(tried map[*endpoint]bool) *endpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for round := 0; round < 2; round++ {
+		for _, ep := range m.endpoints {
+			if !tried[ep] && !ep.quarantinedUntil.After(now) {
+				return ep
+			}
+		}
+		for ep := range tried {
+			delete(tried, ep)
+		}
+	}
+	first := m.endpoints[0]
+	for _, ep := range m.endpoints[1:] {
+		if ep.quarantinedUntil.Before(first.quarantinedUntil) {
+			first = ep
+		}
+	}
+	return first
+}
+
+func (m *MultiProvider) quarantine(ep *endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ep.quarantinedUntil = time.Now().Add(m.opts.QuarantineDuration)
+}
+
+// backoff waits before the retry number attempt, it returns the error of ctx if it is done first.
+func (m *MultiProvider) backoff(ctx context.Context, attempt int) error {
+	delay := m.opts.InitialBackoff << (attempt - 1)
+	if delay > m.opts.MaxBackoff || delay <= 0 {
+		delay = m.opts.MaxBackoff
+	}
+	delay -= time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retry calls fn on the endpoints until it succeeds, fails with an error that is
+// not transient, or the retries are exhausted.
+func (m *MultiProvider) retry(ctx context.Context, fn func(RpcProvider) error) error {
+	tried := map[*endpoint]bool{}
+	var err error
+	for attempt := 0; attempt <= m.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if ctxErr := m.backoff(ctx, attempt); ctxErr != nil {
+				return err
+			}
+		}
+		ep := m.next(tried)
+		tried[ep] = true
+		err = fn(ep.provider)
+		if err == nil || !isTransientError(ctx, err) {
+			return err
+		}
+		if isConnectionError(err) {
+			m.quarantine(ep)
+		}
+	}
+	return err
+}
+
+// write sends a transaction with send. After a transient error the outcome is
+// unknown, so the transaction is only sent again if TransactionHash is set and
+// the hash is unknown to the network. If the hash is known, sent is called with
+// it, as it is when a node rejects a resent transaction as a duplicate.
+func (m *MultiProvider) write(ctx context.Context, tx interface{}, send func(RpcProvider) error, sent func(hash *felt.Felt)) error {
+	tried := map[*endpoint]bool{}
+	var hash *felt.Felt
+	var err error
+	for attempt := 0; attempt <= m.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if ctxErr := m.backoff(ctx, attempt); ctxErr != nil {
+				return err
+			}
+		}
+		ep := m.next(tried)
+		tried[ep] = true
+		err = send(ep.provider)
+		if hash != nil && errors.Is(err, ErrDuplicateTx) {
+			// a previous submission reached the network after all
+			sent(hash)
+			return nil
+		}
+		if err == nil || !isTransientError(ctx, err) {
+			return err
+		}
+		if isConnectionError(err) {
+			m.quarantine(ep)
+		}
+
+		if m.opts.TransactionHash == nil {
+			return err
+		}
+		if hash == nil {
+			var hashErr error
+			if hash, hashErr = m.opts.TransactionHash(tx); hashErr != nil {
+				return err
+			}
+		}
+		known, statusErr := m.transactionKnown(ctx, ep, hash)
+		if statusErr != nil {
+			return err
+		}
+		if known {
+			sent(hash)
+			return nil
+		}
+	}
+	return err
+}
+
+// transactionKnown reports whether the network knows the transaction. The
+// endpoint the transaction was sent to is asked first, as it knows the
+// transaction before the other nodes do, then the other endpoints if it fails.
+func (m *MultiProvider) transactionKnown(ctx context.Context, ep *endpoint, hash *felt.Felt) (bool, error) {
+	_, err := ep.provider.GetTransactionStatus(ctx, hash)
+	if err != nil && !errors.Is(err, ErrHashNotFound) {
+		err = m.retry(ctx, func(provider RpcProvider) error {
+			_, err := provider.GetTransactionStatus(ctx, hash)
+			return err
+		})
+	}
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrHashNotFound):
+		return false, nil
+	}
+	return false, err
+}
+
+// isTransientError reports whether a request failing with err may succeed if it is sent again.
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var httpErr ethrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == 429
+	}
+	return errors.Is(err, context.DeadlineExceeded) || isConnectionError(err)
+}
+
+// isConnectionError reports whether err is a network error, the endpoint could not be reached.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// multiRead calls fn with the retries of the MultiProvider.
+func multiRead[T any](ctx context.Context, m *MultiProvider, fn func(RpcProvider) (T, error)) (T, error) {
+	var result T
+	err := m.retry(ctx, func(provider RpcProvider) error {
+		var err error
+		result, err = fn(provider)
+		return err
+	})
+	return result, err
+}
+
+// AddInvokeTransaction sends an invoke transaction, see MultiProviderOptions.TransactionHash for the retries.
+func (m *MultiProvider) AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error) {
+	var resp *AddInvokeTransactionResponse
+	err := m.write(ctx, invokeTxn, func(provider RpcProvider) error {
+		var err error
+		resp, err = provider.AddInvokeTransaction(ctx, invokeTxn)
+		return err
+	}, func(hash *felt.Felt) {
+		resp = &AddInvokeTransactionResponse{TransactionHash: hash}
+	})
+	return resp, err
+}
+
+// AddDeclareTransaction sends a declare transaction, see MultiProviderOptions.TransactionHash for the retries.
+// The class hash of the response is nil if the transaction was found after a failed submission.
+func (m *MultiProvider) AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error) {
+	var resp *AddDeclareTransactionResponse
+	err := m.write(ctx, declareTransaction, func(provider RpcProvider) error {
+		var err error
+		resp, err = provider.AddDeclareTransaction(ctx, declareTransaction)
+		return err
+	}, func(hash *felt.Felt) {
+		resp = &AddDeclareTransactionResponse{TransactionHash: hash}
+	})
+	return resp, err
+}
+
+// AddDeployAccountTransaction sends a deploy account transaction, see MultiProviderOptions.TransactionHash for the retries.
+// The contract address of the response is nil if the transaction was found after a failed submission.
+func (m *MultiProvider) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error) {
+	var resp *AddDeployAccountTransactionResponse
+	err := m.write(ctx, deployAccountTransaction, func(provider RpcProvider) error {
+		var err error
+		resp, err = provider.AddDeployAccountTransaction(ctx, deployAccountTransaction)
+		return err
+	}, func(hash *felt.Felt) {
+		resp = &AddDeployAccountTransactionResponse{TransactionHash: hash}
+	})
+	return resp, err
+}
+
+// BlockHashAndNumber returns the hash and the number of the latest block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - *BlockHashAndNumberOutput: the hash and the number of the block
+// - error: an error if the request fails
+func (m *MultiProvider) BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*BlockHashAndNumberOutput, error) { return p.BlockHashAndNumber(ctx) })
+}
+
+// BlockNumber returns the number of the latest block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - uint64: the number of the block
+// - error: an error if the request fails
+func (m *MultiProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (uint64, error) { return p.BlockNumber(ctx) })
+}
+
+// BlockTransactionCount returns the number of transactions in a block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - uint64: the number of transactions
+// - error: an error if the request fails
+func (m *MultiProvider) BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (uint64, error) { return p.BlockTransactionCount(ctx, blockID) })
+}
+
+// BlockWithTxHashes returns a block with the hashes of its transactions, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - *BlockTxHashesResult: the block
+// - error: an error if the request fails
+func (m *MultiProvider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*BlockTxHashesResult, error) { return p.BlockWithTxHashes(ctx, blockID) })
+}
+
+// BlockWithReceipts returns a block with its transactions and their receipts, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - *BlockWithReceiptsResult: the block
+// - error: an error if the request fails
+func (m *MultiProvider) BlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*BlockWithReceiptsResult, error) { return p.BlockWithReceipts(ctx, blockID) })
+}
+
+// BlockWithTxs returns a block with its transactions, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - *BlockWithTxsResult: the block
+// - error: an error if the request fails
+func (m *MultiProvider) BlockWithTxs(ctx context.Context, blockID BlockID) (*BlockWithTxsResult, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*BlockWithTxsResult, error) { return p.BlockWithTxs(ctx, blockID) })
+}
+
+// Call calls a function of a contract without creating a transaction, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - call: the function call
+// - block: the identifier of the block
+// Returns:
+// - []*felt.Felt: the result of the function
+// - error: an error if the request fails
+func (m *MultiProvider) Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error) {
+	return multiRead(ctx, m, func(p RpcProvider) ([]*felt.Felt, error) { return p.Call(ctx, call, block) })
+}
+
+// ChainID returns the chain id of the network, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - string: the chain id
+// - error: an error if the request fails
+func (m *MultiProvider) ChainID(ctx context.Context) (string, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (string, error) { return p.ChainID(ctx) })
+}
+
+// Class returns the definition of a class, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - classHash: the hash of the class
+// Returns:
+// - ClassOutput: the class definition
+// - error: an error if the request fails
+func (m *MultiProvider) Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (ClassOutput, error) { return p.Class(ctx, blockID, classHash) })
+}
+
+// ClassAt returns the definition of the class of a contract, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - contractAddress: the address of the contract
+// Returns:
+// - ClassOutput: the class definition
+// - error: an error if the request fails
+func (m *MultiProvider) ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (ClassOutput, error) { return p.ClassAt(ctx, blockID, contractAddress) })
+}
+
+// ClassHashAt returns the class hash of a contract, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the class hash
+// - error: an error if the request fails
+func (m *MultiProvider) ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*felt.Felt, error) { return p.ClassHashAt(ctx, blockID, contractAddress) })
+}
+
+// CompiledCasm returns the CASM of a class, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - classHash: the hash of the class
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: an error if the request fails
+func (m *MultiProvider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*contracts.CasmClass, error) { return p.CompiledCasm(ctx, classHash) })
+}
+
+// EstimateFee estimates the fees of transactions, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - requests: the transactions to estimate
+// - simulationFlags: the simulation flags
+// - blockID: the identifier of the block
+// Returns:
+// - []FeeEstimate: the fee estimates
+// - error: an error if the request fails
+func (m *MultiProvider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
+	return multiRead(ctx, m, func(p RpcProvider) ([]FeeEstimate, error) {
+		return p.EstimateFee(ctx, requests, simulationFlags, blockID)
+	})
+}
+
+// EstimateMessageFee estimates the L2 fee of a message sent from L1, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - msg: the message from L1
+// - blockID: the identifier of the block
+// Returns:
+// - *FeeEstimate: the fee estimate
+// - error: an error if the request fails
+func (m *MultiProvider) EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*FeeEstimate, error) { return p.EstimateMessageFee(ctx, msg, blockID) })
+}
+
+// Events returns a page of the events matching a filter, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - input: the query
+// Returns:
+// - *EventChunk: the events
+// - error: an error if the request fails
+func (m *MultiProvider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*EventChunk, error) { return p.Events(ctx, input) })
+}
+
+// GetMessagesStatus returns the status of the messages sent by an L1 transaction, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - transactionHash: the hash of the transaction
+// Returns:
+// - []MessageStatus: the statuses of the messages
+// - error: an error if the request fails
+func (m *MultiProvider) GetMessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatus, error) {
+	return multiRead(ctx, m, func(p RpcProvider) ([]MessageStatus, error) { return p.GetMessagesStatus(ctx, transactionHash) })
+}
+
+// GetStorageProof returns the Merkle proofs of classes, contracts and storage keys, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - input: the query
+// Returns:
+// - *StorageProofResult: the proofs
+// - error: an error if the request fails
+func (m *MultiProvider) GetStorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*StorageProofResult, error) { return p.GetStorageProof(ctx, input) })
+}
+
+// GetTransactionStatus returns the status of a transaction, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - transactionHash: the hash of the transaction
+// Returns:
+// - *TxnStatusResp: the transaction status
+// - error: an error if the request fails
+func (m *MultiProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*TxnStatusResp, error) { return p.GetTransactionStatus(ctx, transactionHash) })
+}
+
+// Nonce returns the nonce of a contract, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the nonce
+// - error: an error if the request fails
+func (m *MultiProvider) Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*felt.Felt, error) { return p.Nonce(ctx, blockID, contractAddress) })
+}
+
+// SimulateTransactions simulates transactions on a block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - txns: the transactions to simulate
+// - simulationFlags: the simulation flags
+// Returns:
+// - []SimulatedTransaction: the simulated transactions
+// - error: an error if the request fails
+func (m *MultiProvider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
+	return multiRead(ctx, m, func(p RpcProvider) ([]SimulatedTransaction, error) {
+		return p.SimulateTransactions(ctx, blockID, txns, simulationFlags)
+	})
+}
+
+// StateUpdate returns the state update of a block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - *StateUpdateOutput: the state update
+// - error: an error if the request fails
+func (m *MultiProvider) StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*StateUpdateOutput, error) { return p.StateUpdate(ctx, blockID) })
+}
+
+// StorageAt returns the value of a storage key of a contract, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - contractAddress: the address of the contract
+// - key: the storage key
+// - blockID: the identifier of the block
+// Returns:
+// - string: the storage value
+// - error: an error if the request fails
+func (m *MultiProvider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (string, error) { return p.StorageAt(ctx, contractAddress, key, blockID) })
+}
+
+// SpecVersion returns the spec version of the node, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - string: the spec version
+// - error: an error if the request fails
+func (m *MultiProvider) SpecVersion(ctx context.Context) (string, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (string, error) { return p.SpecVersion(ctx) })
+}
+
+// Syncing returns the sync status of the node, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// Returns:
+// - *SyncStatus: the sync status
+// - error: an error if the request fails
+func (m *MultiProvider) Syncing(ctx context.Context) (*SyncStatus, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (*SyncStatus, error) { return p.Syncing(ctx) })
+}
+
+// TraceBlockTransactions returns the traces of the transactions of a block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// Returns:
+// - []Trace: the traces
+// - error: an error if the request fails
+func (m *MultiProvider) TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error) {
+	return multiRead(ctx, m, func(p RpcProvider) ([]Trace, error) { return p.TraceBlockTransactions(ctx, blockID) })
+}
+
+// TransactionByBlockIdAndIndex returns a transaction by its block and its index in the block, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - blockID: the identifier of the block
+// - index: the index of the transaction in the block
+// Returns:
+// - Transaction: the transaction
+// - error: an error if the request fails
+func (m *MultiProvider) TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (Transaction, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (Transaction, error) { return p.TransactionByBlockIdAndIndex(ctx, blockID, index) })
+}
+
+// TransactionByHash returns a transaction by its hash, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - hash: the hash of the transaction
+// Returns:
+// - Transaction: the transaction
+// - error: an error if the request fails
+func (m *MultiProvider) TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (Transaction, error) { return p.TransactionByHash(ctx, hash) })
+}
+
+// TransactionReceipt returns the receipt of a transaction, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - transactionHash: the hash of the transaction
+// Returns:
+// - TransactionReceipt: the receipt
+// - error: an error if the request fails
+func (m *MultiProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (TransactionReceipt, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (TransactionReceipt, error) { return p.TransactionReceipt(ctx, transactionHash) })
+}
+
+// TraceTransaction returns the trace of a transaction, retried on the next endpoint on transient errors.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - transactionHash: the hash of the transaction
+// Returns:
+// - TxnTrace: the trace
+// - error: an error if the request fails
+func (m *MultiProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error) {
+	return multiRead(ctx, m, func(p RpcProvider) (TxnTrace, error) { return p.TraceTransaction(ctx, transactionHash) })
+}
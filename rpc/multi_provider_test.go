@@ -0,0 +1,336 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/test-go/testify/require"
+)
+
+// nodeService is an in-process node serving the methods used by the MultiProvider tests.
+type nodeService struct {
+	blockNumber uint64
+	txHash      *felt.Felt
+	// duplicate rejects the transactions as already in the mempool
+	duplicate bool
+
+	mu           sync.Mutex
+	calls        map[string]int
+	transactions map[felt.Felt]bool
+}
+
+func (s *nodeService) call(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method]++
+}
+
+func (s *nodeService) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *nodeService) BlockNumber() uint64 {
+	s.call("blockNumber")
+	return s.blockNumber
+}
+
+func (s *nodeService) Syncing() bool {
+	s.call("syncing")
+	return false
+}
+
+func (s *nodeService) GetNonce(blockID json.RawMessage, contractAddress *felt.Felt) (*felt.Felt, error) {
+	s.call("getNonce")
+	return nil, codeError{code: ErrContractNotFound.Code(), message: ErrContractNotFound.Error()}
+}
+
+func (s *nodeService) AddInvokeTransaction(tx json.RawMessage) (*AddInvokeTransactionResponse, error) {
+	s.call("addInvokeTransaction")
+	if s.duplicate {
+		return nil, codeError{code: ErrDuplicateTx.Code(), message: ErrDuplicateTx.Error()}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions[*s.txHash] = true
+	return &AddInvokeTransactionResponse{TransactionHash: s.txHash}, nil
+}
+
+func (s *nodeService) GetTransactionStatus(hash *felt.Felt) (*TxnStatusResp, error) {
+	s.call("getTransactionStatus")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.transactions[*hash] {
+		return nil, codeError{code: ErrHashNotFound.Code(), message: ErrHashNotFound.Error()}
+	}
+	return &TxnStatusResp{FinalityStatus: TxnStatus_Received}, nil
+}
+
+// testNode is a JSON-RPC server that can fail with HTTP 503 or be shut down.
+type testNode struct {
+	service *nodeService
+	server  *httptest.Server
+	fail    int32
+	// lose serves the next request but replaces its response with HTTP 503
+	lose int32
+}
+
+func newTestNode(t *testing.T, blockNumber uint64, txHash *felt.Felt) *testNode {
+	node := &testNode{service: &nodeService{
+		blockNumber:  blockNumber,
+		txHash:       txHash,
+		calls:        map[string]int{},
+		transactions: map[felt.Felt]bool{},
+	}}
+	rpcServer := ethrpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("starknet", node.service))
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&node.fail) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if atomic.CompareAndSwapInt32(&node.lose, 1, 0) {
+			rpcServer.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rpcServer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		node.server.Close()
+		rpcServer.Stop()
+	})
+	return node
+}
+
+func newTestMultiProvider(t *testing.T, opts *MultiProviderOptions, nodes ...*testNode) *MultiProvider {
+	urls := make([]string, len(nodes))
+	for i, node := range nodes {
+		urls[i] = node.server.URL
+	}
+	if opts == nil {
+		opts = &MultiProviderOptions{}
+	}
+	opts.InitialBackoff = time.Millisecond
+	opts.MaxBackoff = time.Millisecond
+	provider, err := DialMultiProvider(urls, opts)
+	require.NoError(t, err)
+	return provider
+}
+
+func (m *MultiProvider) isQuarantined(i int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.endpoints[i].quarantinedUntil.After(time.Now())
+}
+
+// TestMultiProviderReads tests the retries and the failover of the reads.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMultiProviderReads(t *testing.T) {
+	t.Run("retries HTTP 503 on the next provider", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, nil), newTestNode(t, 101, nil)
+		atomic.StoreInt32(&primary.fail, 1)
+		provider := newTestMultiProvider(t, nil, primary, secondary)
+
+		blockNumber, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(101), blockNumber)
+		require.False(t, provider.isQuarantined(0))
+
+		// the primary is preferred again once it recovers
+		atomic.StoreInt32(&primary.fail, 0)
+		blockNumber, err = provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), blockNumber)
+	})
+
+	t.Run("quarantines unreachable providers", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, nil), newTestNode(t, 101, nil)
+		provider := newTestMultiProvider(t, nil, primary, secondary)
+		primary.server.Close()
+
+		blockNumber, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(101), blockNumber)
+		require.True(t, provider.isQuarantined(0))
+
+		_, err = provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, secondary.service.count("blockNumber"))
+	})
+
+	t.Run("does not retry node errors", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, nil), newTestNode(t, 101, nil)
+		provider := newTestMultiProvider(t, nil, primary, secondary)
+
+		_, err := provider.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+		require.True(t, errors.Is(err, ErrContractNotFound))
+		require.Equal(t, 1, primary.service.count("getNonce"))
+		require.Equal(t, 0, secondary.service.count("getNonce"))
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		primary := newTestNode(t, 100, nil)
+		atomic.StoreInt32(&primary.fail, 1)
+		provider := newTestMultiProvider(t, &MultiProviderOptions{MaxRetries: 2}, primary)
+
+		_, err := provider.BlockNumber(context.Background())
+		var httpErr ethrpc.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	})
+}
+
+// TestMultiProviderCheckHealth tests that the providers lagging behind are quarantined.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMultiProviderCheckHealth(t *testing.T) {
+	lagging, synced, down := newTestNode(t, 100, nil), newTestNode(t, 120, nil), newTestNode(t, 120, nil)
+	provider := newTestMultiProvider(t, &MultiProviderOptions{MaxBlockLag: 10}, lagging, synced, down)
+	down.server.Close()
+
+	require.NoError(t, provider.CheckHealth(context.Background()))
+	require.True(t, provider.isQuarantined(0))
+	require.False(t, provider.isQuarantined(1))
+	require.True(t, provider.isQuarantined(2))
+	require.Equal(t, 1, synced.service.count("syncing"))
+
+	blockNumber, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), blockNumber)
+
+	// the lagging provider is released once it catches up
+	lagging.service.mu.Lock()
+	lagging.service.blockNumber = 115
+	lagging.service.mu.Unlock()
+	require.NoError(t, provider.CheckHealth(context.Background()))
+	require.False(t, provider.isQuarantined(0))
+}
+
+// TestMultiProviderWrites tests that transactions are only sent again when they are
+// verified to be unknown to the network.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMultiProviderWrites(t *testing.T) {
+	txHash := utils.TestHexToFelt(t, "0x42")
+	tx := BroadcastInvokev1Txn{InvokeTxnV1: InvokeTxnV1{
+		Type:          TransactionType_Invoke,
+		Version:       TransactionV1,
+		SenderAddress: utils.TestHexToFelt(t, "0x1"),
+		MaxFee:        utils.TestHexToFelt(t, "0x1"),
+		Nonce:         utils.TestHexToFelt(t, "0x1"),
+		Signature:     []*felt.Felt{},
+		Calldata:      []*felt.Felt{},
+	}}
+	hashFunc := func(interface{}) (*felt.Felt, error) { return txHash, nil }
+
+	t.Run("never sends twice without the transaction hash", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, txHash), newTestNode(t, 100, txHash)
+		atomic.StoreInt32(&primary.fail, 1)
+		provider := newTestMultiProvider(t, nil, primary, secondary)
+
+		_, err := provider.AddInvokeTransaction(context.Background(), tx)
+		require.Error(t, err)
+		require.Equal(t, 0, secondary.service.count("addInvokeTransaction"))
+	})
+
+	t.Run("sends again when the transaction is unknown", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, txHash), newTestNode(t, 100, txHash)
+		atomic.StoreInt32(&primary.fail, 1)
+		provider := newTestMultiProvider(t, &MultiProviderOptions{TransactionHash: hashFunc}, primary, secondary)
+
+		resp, err := provider.AddInvokeTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TransactionHash)
+		require.Equal(t, 1, secondary.service.count("getTransactionStatus"))
+		require.Equal(t, 1, secondary.service.count("addInvokeTransaction"))
+	})
+
+	t.Run("does not send again when the transaction is known", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, txHash), newTestNode(t, 100, txHash)
+		provider := newTestMultiProvider(t, &MultiProviderOptions{TransactionHash: hashFunc}, primary, secondary)
+		// the transaction reached the network but the response was lost
+		secondary.service.transactions[*txHash] = true
+		atomic.StoreInt32(&primary.fail, 1)
+
+		resp, err := provider.AddInvokeTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TransactionHash)
+		require.Equal(t, 0, secondary.service.count("addInvokeTransaction"))
+	})
+
+	t.Run("checks the status on the node that received the transaction", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, txHash), newTestNode(t, 100, txHash)
+		provider := newTestMultiProvider(t, &MultiProviderOptions{TransactionHash: hashFunc}, primary, secondary)
+		// the primary accepts the transaction but the response is lost
+		atomic.StoreInt32(&primary.lose, 1)
+
+		resp, err := provider.AddInvokeTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TransactionHash)
+		require.Equal(t, 1, primary.service.count("getTransactionStatus"))
+		require.Equal(t, 0, secondary.service.count("getTransactionStatus"))
+		require.Equal(t, 0, secondary.service.count("addInvokeTransaction"))
+	})
+
+	t.Run("a transaction sent again and rejected as a duplicate succeeds", func(t *testing.T) {
+		primary, secondary := newTestNode(t, 100, txHash), newTestNode(t, 100, txHash)
+		provider := newTestMultiProvider(t, &MultiProviderOptions{TransactionHash: hashFunc}, primary, secondary)
+		// the transaction reached the mempool of the secondary before its status
+		secondary.service.duplicate = true
+		atomic.StoreInt32(&primary.fail, 1)
+
+		resp, err := provider.AddInvokeTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TransactionHash)
+		require.Equal(t, 1, secondary.service.count("addInvokeTransaction"))
+	})
+}
+
+// TestSyncLag tests the lag of syncing nodes decoded from starknet_syncing results.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSyncLag(t *testing.T) {
+	type testSetType struct {
+		Result      string
+		ExpectedLag uint64
+	}
+	testSet := []testSetType{
+		{Result: `false`, ExpectedLag: 0},
+		{Result: `{"starting_block_hash":"0x1","starting_block_num":"0x1","current_block_hash":"0x2","current_block_num":"0x64","highest_block_hash":"0x3","highest_block_num":"0xc8"}`, ExpectedLag: 100},
+	}
+	for _, test := range testSet {
+		var status SyncStatus
+		require.NoError(t, json.Unmarshal([]byte(test.Result), &status))
+		require.Equal(t, test.Result != "false", status.SyncStatus)
+		require.Equal(t, test.ExpectedLag, syncLag(&status))
+	}
+}
@@ -0,0 +1,99 @@
+// Package otelrpc traces the JSON-RPC requests of an rpc.Provider with OpenTelemetry.
+//
+// The spans are started by rpc.TracingMiddleware, through an adapter of an
+// OpenTelemetry tracer:
+//
+//	provider.Use(otelrpc.Middleware(otel.GetTracerProvider()))
+package otelrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer created by Middleware.
+const InstrumentationName = "github.com/NethermindEth/starknet.go/rpc/otelrpc"
+
+// Middleware returns the tracing middleware of the provider, starting the spans
+// with its tracer named InstrumentationName.
+//
+// Parameters:
+// - provider: the OpenTelemetry tracer provider, e.g. otel.GetTracerProvider()
+// Returns:
+// - rpc.Middleware: the tracing middleware
+func Middleware(provider trace.TracerProvider) rpc.Middleware {
+	return rpc.TracingMiddleware(NewTracer(provider.Tracer(InstrumentationName)))
+}
+
+// tracer adapts an OpenTelemetry tracer to rpc.Tracer.
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer adapts an OpenTelemetry tracer to rpc.Tracer. The spans are
+// client spans.
+//
+// Parameters:
+// - t: the OpenTelemetry tracer
+// Returns:
+// - rpc.Tracer: the tracer to pass to rpc.TracingMiddleware
+func NewTracer(t trace.Tracer) rpc.Tracer {
+	return &tracer{tracer: t}
+}
+
+// Start starts a client span.
+func (t *tracer) Start(ctx context.Context, spanName string) (context.Context, rpc.Span) {
+	ctx, otelSpan := t.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, &span{span: otelSpan}
+}
+
+// span adapts an OpenTelemetry span to rpc.Span.
+type span struct {
+	span trace.Span
+}
+
+// SetAttribute sets an attribute, converted to the OpenTelemetry type of its value.
+func (s *span) SetAttribute(key string, value interface{}) {
+	s.span.SetAttributes(attributeOf(key, value))
+}
+
+// RecordError records the error as an event and sets the status of the span to Error.
+func (s *span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span.
+func (s *span) End() {
+	s.span.End()
+}
+
+// attributeOf returns the attribute of a value, formatted as a string if its type
+// has no OpenTelemetry equivalent.
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case uint64:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
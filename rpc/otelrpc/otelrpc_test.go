@@ -0,0 +1,127 @@
+package otelrpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/fakenode"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/rpc/otelrpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan is an OpenTelemetry span keeping what is recorded on it.
+type recordingSpan struct {
+	noop.Span
+	name       string
+	kind       trace.SpanKind
+	attributes map[attribute.Key]attribute.Value
+	errs       []error
+	status     codes.Code
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+func (s *recordingSpan) SetStatus(code codes.Code, _ string)           { s.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)                    { s.ended = true }
+
+// recordingProvider is an OpenTelemetry tracer provider keeping the name of its tracer.
+type recordingProvider struct {
+	noop.TracerProvider
+	name   string
+	tracer *recordingTracer
+}
+
+func (p *recordingProvider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	p.name = name
+	return p.tracer
+}
+
+// recordingTracer is an OpenTelemetry tracer keeping the spans it starts.
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	span := &recordingSpan{
+		name:       spanName,
+		kind:       config.SpanKind(),
+		attributes: map[attribute.Key]attribute.Value{},
+	}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+// TestMiddleware tests the spans of the requests, the failed requests and the
+// batches with failed elements.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMiddleware(t *testing.T) {
+	server := httptest.NewServer(fakenode.NewNode(nil))
+	t.Cleanup(server.Close)
+	client, err := rpc.NewClient(server.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	provider := rpc.NewProvider(client)
+	tracer := &recordingTracer{}
+	tracerProvider := &recordingProvider{tracer: tracer}
+	provider.Use(otelrpc.Middleware(tracerProvider))
+	ctx := context.Background()
+	unknown := utils.TestHexToFelt(t, "0xdead")
+
+	_, err = provider.BlockNumber(ctx)
+	require.NoError(t, err)
+	_, err = provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), unknown)
+	require.True(t, errors.Is(err, rpc.ErrContractNotFound))
+	_, err = provider.Batch().
+		ClassHashAt(rpc.WithBlockTag("latest"), fakenode.ETHAddress).
+		ClassHashAt(rpc.WithBlockTag("latest"), unknown).
+		Send(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, otelrpc.InstrumentationName, tracerProvider.name)
+	require.Len(t, tracer.spans, 3)
+	for _, span := range tracer.spans {
+		require.Equal(t, trace.SpanKindClient, span.kind)
+		require.True(t, span.ended)
+		require.Equal(t, "jsonrpc", span.attributes["rpc.system"].AsString())
+	}
+
+	ok := tracer.spans[0]
+	require.Equal(t, "starknet_blockNumber", ok.name)
+	require.Equal(t, codes.Unset, ok.status)
+	require.Empty(t, ok.errs)
+	require.Equal(t, attribute.INT64, ok.attributes["rpc.response.size"].Type())
+
+	failed := tracer.spans[1]
+	require.Equal(t, "starknet_getClassHashAt", failed.name)
+	require.Equal(t, codes.Error, failed.status)
+	require.Equal(t, int64(rpc.ErrContractNotFound.Code()), failed.attributes["rpc.jsonrpc.error_code"].AsInt64())
+	require.Len(t, failed.errs, 1)
+
+	batch := tracer.spans[2]
+	require.Equal(t, rpc.BatchMethod, batch.name)
+	require.Equal(t, int64(2), batch.attributes["rpc.batch.size"].AsInt64())
+	require.Equal(t, int64(1), batch.attributes["rpc.batch.errors"].AsInt64())
+	require.Equal(t, codes.Error, batch.status)
+	require.Len(t, batch.errs, 1)
+	require.True(t, errors.Is(batch.errs[0], rpc.ErrContractNotFound))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -17,13 +18,50 @@ var (
 type Provider struct {
 	c       callCloser
 	chainID string
+	// spec negotiates the spec version of the node, nil if it is not checked
+	spec *specNegotiator
 }
 
 // NewProvider creates a new Provider instance with the given RPC (`go-ethereum/rpc`) client.
 //
 // It takes a *rpc.Client as a parameter and returns a pointer to a Provider struct.
-func NewProvider(c *rpc.Client) *Provider {
-	return &Provider{c: c}
+// The options can check the spec version of the node, see WithSpecVersionCheck.
+func NewProvider(c *rpc.Client, opts ...ProviderOption) *Provider {
+	return newProvider(c, opts...)
+}
+
+// NewWebsocketProvider creates a new Provider connected to the websocket endpoint of a node.
+// Unlike the providers of NewProvider, it supports the subscriptions, e.g. SubscribeNewHeads.
+//
+// Parameters:
+// - ctx: the context of the connection
+// - url: the URL of the websocket endpoint, e.g. "ws://localhost:6061/v0_8"
+// - opts: the options of the provider, see NewProvider
+// Returns:
+// - *Provider: the provider, its connection is closed by Close
+// - error: an error if the connection fails
+func NewWebsocketProvider(ctx context.Context, url string, opts ...ProviderOption) (*Provider, error) {
+	c, err := NewWsClient(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(c, opts...), nil
+}
+
+// Close closes the connection of the provider.
+func (provider *Provider) Close() {
+	provider.c.Close()
+}
+
+func newProvider(c callCloser, opts ...ProviderOption) *Provider {
+	provider := &Provider{c: c}
+	for _, opt := range opts {
+		opt(provider)
+	}
+	if provider.spec != nil {
+		provider.Use(provider.spec.middleware)
+	}
+	return provider
 }
 
 //go:generate mockgen -destination=../mocks/mock_rpc_provider.go -package=mocks -source=provider.go api
@@ -34,16 +72,20 @@ type RpcProvider interface {
 	BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error)
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error)
-	BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error)
-	BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error)
+	BlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error)
+	BlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error)
+	BlockWithTxs(ctx context.Context, blockID BlockID) (*BlockWithTxsResult, error)
 	Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error)
 	ChainID(ctx context.Context) (string, error)
 	Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error)
 	ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error)
 	ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error)
 	EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error)
 	EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error)
 	Events(ctx context.Context, input EventsInput) (*EventChunk, error)
+	GetMessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatus, error)
+	GetStorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error)
 	GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error)
 	Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
 	SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error)
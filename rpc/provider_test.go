@@ -72,6 +72,7 @@ var (
 //	none
 func TestMain(m *testing.M) {
 	flag.StringVar(&testEnv, "env", "mock", "set the test environment")
+	flag.BoolVar(&updateGolden, "update", false, "update the golden files")
 	flag.Parse()
 
 	os.Exit(m.Run())
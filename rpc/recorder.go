@@ -0,0 +1,409 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Interaction is a recorded JSON-RPC request with its result or error.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+	// Status is the HTTP status of a response other than 200 OK, whose body is Body
+	// instead of a JSON-RPC response, e.g. 503 for an overloaded node
+	Status int    `json:"status,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Fixture is the content of a fixture file written by a RecordTransport.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadFixture reads a fixture file.
+//
+// Parameters:
+// - path: the path of the fixture file
+// Returns:
+// - *Fixture: the fixture
+// - error: an error if the file could not be read or decoded
+func LoadFixture(path string) (*Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("decoding fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// Save writes the fixture to a file.
+//
+// Parameters:
+// - path: the path of the fixture file
+// Returns:
+// - error: an error if the file could not be written
+func (f *Fixture) Save(path string) error {
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// jsonrpcMessage is a JSON-RPC request or response.
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// decodeMessages decodes a JSON-RPC message or batch of messages.
+func decodeMessages(body []byte) ([]jsonrpcMessage, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []jsonrpcMessage
+		err := json.Unmarshal(body, &messages)
+		return messages, true, err
+	}
+	var message jsonrpcMessage
+	err := json.Unmarshal(body, &message)
+	return []jsonrpcMessage{message}, false, err
+}
+
+// RecordTransport is an http.RoundTripper sending the JSON-RPC requests to a node
+// and recording them with their responses, batches and HTTP errors included:
+//
+//	recorder := rpc.NewRecordTransport(nil)
+//	client, err := rpc.NewClientWithTransport(url, recorder)
+//	provider := rpc.NewProvider(client)
+//	// ... use the provider
+//	err = recorder.Save("testdata/fixture.json")
+type RecordTransport struct {
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecordTransport creates a new RecordTransport.
+//
+// Parameters:
+// - base: the transport sending the requests, http.DefaultTransport if nil
+// Returns:
+// - *RecordTransport: a new RecordTransport without interactions
+func NewRecordTransport(base http.RoundTripper) *RecordTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RecordTransport{base: base}
+}
+
+// RoundTrip sends the request and records its JSON-RPC interactions.
+func (r *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	requests, _, err := decodeMessages(body)
+	if err != nil {
+		return resp, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		// the HTTP error is the response of every request of a batch
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, request := range requests {
+			r.interactions = append(r.interactions, Interaction{
+				Method: request.Method,
+				Params: request.Params,
+				Status: resp.StatusCode,
+				Body:   string(respBody),
+			})
+		}
+		return resp, nil
+	}
+	responses, _, err := decodeMessages(respBody)
+	if err != nil {
+		return resp, nil
+	}
+	byID := make(map[string]jsonrpcMessage, len(responses))
+	for _, response := range responses {
+		byID[string(response.ID)] = response
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, request := range requests {
+		response, ok := byID[string(request.ID)]
+		if !ok {
+			continue
+		}
+		r.interactions = append(r.interactions, Interaction{
+			Method: request.Method,
+			Params: request.Params,
+			Result: response.Result,
+			Error:  response.Error,
+		})
+	}
+	return resp, nil
+}
+
+// Interactions returns the recorded interactions, in the order of the requests.
+func (r *RecordTransport) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to a fixture file.
+//
+// Parameters:
+// - path: the path of the fixture file
+// Returns:
+// - error: an error if the file could not be written
+func (r *RecordTransport) Save(path string) error {
+	fixture := Fixture{Interactions: r.Interactions()}
+	return fixture.Save(path)
+}
+
+// MatchMode is how a ReplayTransport matches the requests with the recorded interactions.
+type MatchMode int
+
+const (
+	// MatchStrict matches the requests with the same method and params.
+	MatchStrict MatchMode = iota
+	// MatchMethod matches the requests with the same method, whatever their params.
+	MatchMethod
+)
+
+// NoInteractionCode is the code of the JSON-RPC error returned by a ReplayTransport
+// for the requests without a recorded interaction.
+const NoInteractionCode = -32099
+
+// ReplayTransport is an http.RoundTripper serving recorded JSON-RPC interactions
+// without a node. Each interaction is replayed once, in the recorded order among
+// the interactions matching a request. The requests without a matching
+// interaction get a JSON-RPC error with the code NoInteractionCode and are
+// reported by Unexpected. The recorded HTTP errors are replayed as the response
+// to the whole request.
+type ReplayTransport struct {
+	mode MatchMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	unexpected   []Interaction
+}
+
+// NewReplayTransport creates a new ReplayTransport serving interactions.
+//
+// Parameters:
+// - interactions: the recorded interactions
+// - mode: how the requests are matched with the interactions
+// Returns:
+// - *ReplayTransport: a new ReplayTransport
+func NewReplayTransport(interactions []Interaction, mode MatchMode) *ReplayTransport {
+	return &ReplayTransport{
+		mode:         mode,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// LoadReplayTransport creates a new ReplayTransport serving the interactions of a fixture file.
+//
+// Parameters:
+// - path: the path of the fixture file
+// - mode: how the requests are matched with the interactions
+// Returns:
+// - *ReplayTransport: a new ReplayTransport
+// - error: an error if the fixture could not be read
+func LoadReplayTransport(path string, mode MatchMode) (*ReplayTransport, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayTransport(fixture.Interactions, mode), nil
+}
+
+// RoundTrip answers the JSON-RPC requests with the recorded interactions.
+func (r *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return nil, errors.New("replay: request without body")
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	requests, isBatch, err := decodeMessages(body)
+	if err != nil {
+		return nil, fmt.Errorf("replay: decoding request: %w", err)
+	}
+
+	responses := make([]jsonrpcMessage, 0, len(requests))
+	var httpErr *Interaction
+	for _, request := range requests {
+		if request.ID == nil {
+			continue
+		}
+		interaction := r.match(request)
+		if interaction.Status != 0 && httpErr == nil {
+			httpErr = &interaction
+		}
+		responses = append(responses, jsonrpcMessage{
+			Version: "2.0",
+			ID:      request.ID,
+			Result:  interaction.Result,
+			Error:   interaction.Error,
+		})
+	}
+	if httpErr != nil {
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", httpErr.Status, http.StatusText(httpErr.Status)),
+			StatusCode:    httpErr.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{},
+			Body:          io.NopCloser(strings.NewReader(httpErr.Body)),
+			ContentLength: int64(len(httpErr.Body)),
+			Request:       req,
+		}, nil
+	}
+	var respBody []byte
+	if isBatch {
+		respBody, err = json.Marshal(responses)
+	} else if len(responses) > 0 {
+		respBody, err = json.Marshal(responses[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// match returns the first unused interaction matching the request, or an
+// interaction with a NoInteractionCode error.
+func (r *ReplayTransport) match(request jsonrpcMessage) Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	params := canonicalJSON(request.Params)
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Method != request.Method {
+			continue
+		}
+		if r.mode == MatchStrict && canonicalJSON(interaction.Params) != params {
+			continue
+		}
+		r.used[i] = true
+		if interaction.Result == nil && interaction.Error == nil && interaction.Status == 0 {
+			interaction.Result = json.RawMessage("null")
+		}
+		return interaction
+	}
+
+	r.unexpected = append(r.unexpected, Interaction{Method: request.Method, Params: request.Params})
+	message, _ := json.Marshal(fmt.Sprintf("replay: no recorded interaction for %s %s", request.Method, params))
+	return Interaction{Error: json.RawMessage(fmt.Sprintf(`{"code":%d,"message":%s}`, NoInteractionCode, message))}
+}
+
+// canonicalJSON returns raw with sorted object keys and without spaces, so that
+// params encoded differently compare equal.
+func canonicalJSON(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return "[]"
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return string(raw)
+	}
+	return string(canonical)
+}
+
+// Unexpected returns the requests without a matching interaction.
+func (r *ReplayTransport) Unexpected() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.unexpected...)
+}
+
+// Unused returns the interactions that were not replayed.
+func (r *ReplayTransport) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Check returns an error listing the unexpected requests and the unused
+// interactions, nil if every request was replayed and every interaction used.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error describing the mismatches, or nil
+func (r *ReplayTransport) Check() error {
+	var problems []string
+	for _, interaction := range r.Unexpected() {
+		problems = append(problems, fmt.Sprintf("unexpected request %s %s", interaction.Method, canonicalJSON(interaction.Params)))
+	}
+	for _, interaction := range r.Unused() {
+		problems = append(problems, fmt.Sprintf("unused interaction %s %s", interaction.Method, canonicalJSON(interaction.Params)))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("replay: %s", strings.Join(problems, "; "))
+}
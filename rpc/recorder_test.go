@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/test-go/testify/require"
+)
+
+// recordFixture records the requests of use against an in-process node into a fixture file.
+func recordFixture(t *testing.T, use func(provider *Provider)) string {
+	server := ethrpc.NewServer()
+	require.NoError(t, server.RegisterName("starknet", &batchService{unknownHash: utils.TestHexToFelt(t, "0xdead")}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	recorder := NewRecordTransport(nil)
+	client, err := NewClientWithTransport(httpServer.URL, recorder)
+	require.NoError(t, err)
+	defer client.Close()
+	use(NewProvider(client))
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(path))
+	return path
+}
+
+func newReplayProvider(t *testing.T, path string, mode MatchMode) (*Provider, *ReplayTransport) {
+	replayer, err := LoadReplayTransport(path, mode)
+	require.NoError(t, err)
+	client, err := NewClientWithTransport("http://replay.invalid", replayer)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return NewProvider(client), replayer
+}
+
+// TestRecordReplay tests that the recorded interactions, batches and errors
+// included, are replayed without a node.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRecordReplay(t *testing.T) {
+	contract := utils.TestHexToFelt(t, "0xc0ffee")
+	unknownHash := utils.TestHexToFelt(t, "0xdead")
+	var recordedNonce *felt.Felt
+	path := recordFixture(t, func(provider *Provider) {
+		var err error
+		recordedNonce, err = provider.Nonce(context.Background(), WithBlockTag("latest"), contract)
+		require.NoError(t, err)
+		_, err = provider.TransactionReceipt(context.Background(), unknownHash)
+		require.True(t, errors.Is(err, ErrHashNotFound))
+		_, err = provider.Batch().
+			StorageAt(contract, "balance", WithBlockTag("latest")).
+			Nonce(WithBlockTag("latest"), contract).
+			Send(context.Background())
+		require.NoError(t, err)
+	})
+	fixture, err := LoadFixture(path)
+	require.NoError(t, err)
+	require.Len(t, fixture.Interactions, 4)
+	require.Equal(t, "starknet_getNonce", fixture.Interactions[0].Method)
+
+	t.Run("strict", func(t *testing.T) {
+		provider, replayer := newReplayProvider(t, path, MatchStrict)
+
+		nonce, err := provider.Nonce(context.Background(), WithBlockTag("latest"), contract)
+		require.NoError(t, err)
+		require.Equal(t, recordedNonce, nonce)
+
+		_, err = provider.TransactionReceipt(context.Background(), unknownHash)
+		require.True(t, errors.Is(err, ErrHashNotFound))
+
+		responses, err := provider.Batch().
+			StorageAt(contract, "balance", WithBlockTag("latest")).
+			Nonce(WithBlockTag("latest"), contract).
+			Send(context.Background())
+		require.NoError(t, err)
+		storage, err := BatchResult[string](responses[0])
+		require.NoError(t, err)
+		require.Equal(t, utils.GetSelectorFromNameFelt("balance").String(), storage)
+		require.NoError(t, replayer.Check())
+
+		// the interactions are only replayed once
+		_, err = provider.Nonce(context.Background(), WithBlockTag("latest"), contract)
+		require.Error(t, err)
+		require.Len(t, replayer.Unexpected(), 1)
+		require.Error(t, replayer.Check())
+	})
+
+	t.Run("strict reports different params", func(t *testing.T) {
+		provider, replayer := newReplayProvider(t, path, MatchStrict)
+
+		_, err := provider.Nonce(context.Background(), WithBlockTag("pending"), contract)
+		var codeErr interface{ ErrorCode() int }
+		require.True(t, errors.As(err, &codeErr))
+		require.Equal(t, NoInteractionCode, codeErr.ErrorCode())
+		require.Equal(t, "starknet_getNonce", replayer.Unexpected()[0].Method)
+		require.Len(t, replayer.Unused(), 4)
+	})
+
+	t.Run("method", func(t *testing.T) {
+		provider, replayer := newReplayProvider(t, path, MatchMethod)
+
+		nonce, err := provider.Nonce(context.Background(), WithBlockTag("pending"), utils.TestHexToFelt(t, "0x1"))
+		require.NoError(t, err)
+		require.Equal(t, recordedNonce, nonce)
+		require.Empty(t, replayer.Unexpected())
+		require.Len(t, replayer.Unused(), 3)
+	})
+}
+
+// TestRecordReplayHTTPError tests that the responses other than 200 OK are
+// recorded and replayed.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRecordReplayHTTPError(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("overloaded"))
+	}))
+	t.Cleanup(httpServer.Close)
+
+	recorder := NewRecordTransport(nil)
+	client, err := NewClientWithTransport(httpServer.URL, recorder)
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = NewProvider(client).BlockNumber(context.Background())
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(path))
+	fixture, err := LoadFixture(path)
+	require.NoError(t, err)
+	require.Equal(t, []Interaction{{Method: "starknet_blockNumber", Params: fixture.Interactions[0].Params, Status: http.StatusServiceUnavailable, Body: "overloaded"}}, fixture.Interactions)
+
+	provider, replayer := newReplayProvider(t, path, MatchStrict)
+	_, err = provider.BlockNumber(context.Background())
+	var httpErr ethrpc.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	require.Equal(t, "overloaded", string(httpErr.Body))
+	require.NoError(t, replayer.Check())
+}
@@ -0,0 +1,419 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// byteArrayMagic is the felt preceding a ByteArray serialized in the panic data of a Cairo contract.
+var byteArrayMagic, _ = new(felt.Felt).SetString("0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3")
+
+var (
+	// revertCallRegexp matches the header of a call of the call stack of a revert reason,
+	// either "(contract address: 0x.., class hash: 0x.., selector: 0x..)" or "(0x..)"
+	revertCallRegexp = regexp.MustCompile(`Error in the (?:called contract|contract class constructor) \(([^)]*)\)`)
+	// revertPCRegexp matches the pc of the error of a call
+	revertPCRegexp = regexp.MustCompile(`Error at pc=(\d+:\d+)`)
+	// revertFieldRegexp matches the fields of the header of a call
+	revertFieldRegexp = regexp.MustCompile(`(contract address|class hash|selector): (0x[0-9a-fA-F]+)`)
+	// panicDataRegexp matches the start of the panic data of a revert reason
+	panicDataRegexp = regexp.MustCompile(`(?i)failure reason:\s*`)
+)
+
+// DecodedRevert is a revert reason decoded by a RevertDecoder.
+type DecodedRevert struct {
+	// Calls The call stack of the revert, from the outermost call to the call that failed
+	Calls []RevertCall
+	// PanicData The panic data of the call that failed
+	PanicData []*felt.Felt
+	// Message The panic data decoded as short strings and ByteArrays, joined with ", "
+	Message string
+	// Raw The revert reason as sent by the node
+	Raw string
+}
+
+// RevertCall is a call of the call stack of a revert.
+type RevertCall struct {
+	ContractAddress *felt.Felt
+	// ClassHash The class of the contract, not set by nodes older than Starknet 0.13.2
+	ClassHash *felt.Felt
+	// Selector The selector of the function called, not set by nodes older than Starknet 0.13.2
+	Selector *felt.Felt
+	// FunctionName The name of Selector, if it is in an ABI added to the decoder
+	FunctionName string
+	// PC The program counter of the error, e.g. "0:4573"
+	PC string
+}
+
+// String returns the call stack and the message of the revert, one line per call.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the description of the revert
+func (r *DecodedRevert) String() string {
+	var b strings.Builder
+	for i, call := range r.Calls {
+		fmt.Fprintf(&b, "%d: %s", i, feltString(call.ContractAddress))
+		if call.ClassHash != nil {
+			fmt.Fprintf(&b, " (class %s)", call.ClassHash)
+		}
+		switch {
+		case call.FunctionName != "":
+			fmt.Fprintf(&b, " %s", call.FunctionName)
+		case call.Selector != nil:
+			fmt.Fprintf(&b, " selector %s", call.Selector)
+		}
+		if call.PC != "" {
+			fmt.Fprintf(&b, " at pc=%s", call.PC)
+		}
+		b.WriteString("\n")
+	}
+	if r.Message != "" {
+		fmt.Fprintf(&b, "reason: %s", r.Message)
+	} else if len(r.Calls) == 0 {
+		b.WriteString(r.Raw)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func feltString(f *felt.Felt) string {
+	if f == nil {
+		return "unknown contract"
+	}
+	return f.String()
+}
+
+// RevertDecoder decodes the revert reasons of the receipts, of the traces and
+// of the contract errors into call stacks and panic messages.
+type RevertDecoder struct {
+	// functionNames are the names of the functions of the ABIs, by selector
+	functionNames map[felt.Felt]string
+}
+
+// NewRevertDecoder creates a RevertDecoder with no ABI.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *RevertDecoder: the decoder
+func NewRevertDecoder() *RevertDecoder {
+	return &RevertDecoder{functionNames: map[felt.Felt]string{}}
+}
+
+// DecodeRevertReason decodes a revert reason without resolving the selectors.
+//
+// Parameters:
+// - reason: the revert reason, e.g. the RevertReason of a receipt
+// Returns:
+// - *DecodedRevert: the decoded revert
+func DecodeRevertReason(reason string) *DecodedRevert {
+	return NewRevertDecoder().Decode(reason)
+}
+
+// AddABI adds the functions of the ABI of a Cairo 0 contract to the names of the selectors.
+//
+// Parameters:
+// - abi: the ABI of the contract, e.g. of a DeprecatedContractClass
+// Returns:
+//
+//	none
+func (d *RevertDecoder) AddABI(abi ABI) {
+	for _, entry := range abi {
+		if function, ok := entry.(*FunctionABIEntry); ok {
+			d.addFunction(function.Name)
+		}
+	}
+}
+
+// AddSierraABI adds the functions of the ABI of a Cairo 1 contract to the names
+// of the selectors, including the functions of its interfaces.
+//
+// Parameters:
+// - abi: the JSON ABI of the contract, e.g. the ABI of a ContractClass
+// Returns:
+// - error: an error if the ABI cannot be unmarshaled
+func (d *RevertDecoder) AddSierraABI(abi string) error {
+	type sierraABIEntry struct {
+		Type  string           `json:"type"`
+		Name  string           `json:"name"`
+		Items []sierraABIEntry `json:"items"`
+	}
+	var entries []sierraABIEntry
+	if err := json.Unmarshal([]byte(abi), &entries); err != nil {
+		return fmt.Errorf("unmarshal sierra abi: %w", err)
+	}
+	for len(entries) > 0 {
+		entry := entries[0]
+		entries = append(entries[1:], entry.Items...)
+		switch ABIType(entry.Type) {
+		case ABITypeFunction, ABITypeL1Handler, ABITypeConstructor:
+			d.addFunction(entry.Name)
+		}
+	}
+	return nil
+}
+
+func (d *RevertDecoder) addFunction(name string) {
+	d.functionNames[*utils.GetSelectorFromNameFelt(name)] = name
+}
+
+// Decode decodes a revert reason. The parts of the reason that cannot be
+// parsed are left unset, the reason is kept in Raw.
+//
+// Parameters:
+// - reason: the revert reason
+// Returns:
+// - *DecodedRevert: the decoded revert
+func (d *RevertDecoder) Decode(reason string) *DecodedRevert {
+	decoded := &DecodedRevert{Raw: reason}
+
+	headers := revertCallRegexp.FindAllStringSubmatchIndex(reason, -1)
+	for i, header := range headers {
+		end := len(reason)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		call := d.parseCall(reason[header[2]:header[3]])
+		if pc := revertPCRegexp.FindStringSubmatch(reason[header[1]:end]); pc != nil {
+			call.PC = pc[1]
+		}
+		decoded.Calls = append(decoded.Calls, call)
+	}
+
+	// the panic data of the call that failed is the last one of the reason
+	if starts := panicDataRegexp.FindAllStringIndex(reason, -1); starts != nil {
+		decoded.PanicData = parsePanicData(reason[starts[len(starts)-1][1]:])
+		decoded.Message = d.DecodePanicData(decoded.PanicData)
+	}
+	return decoded
+}
+
+// parseCall parses the header of a call, the text between the parentheses.
+func (d *RevertDecoder) parseCall(header string) RevertCall {
+	var call RevertCall
+	fields := revertFieldRegexp.FindAllStringSubmatch(header, -1)
+	if fields == nil {
+		call.ContractAddress, _ = new(felt.Felt).SetString(strings.TrimSpace(header))
+		return call
+	}
+	for _, field := range fields {
+		value, err := new(felt.Felt).SetString(field[2])
+		if err != nil {
+			continue
+		}
+		switch field[1] {
+		case "contract address":
+			call.ContractAddress = value
+		case "class hash":
+			call.ClassHash = value
+		case "selector":
+			call.Selector = value
+			call.FunctionName = d.functionNames[*value]
+		}
+	}
+	return call
+}
+
+// parsePanicData parses the felts following "Failure reason:", either one felt
+// or a list of felts in parentheses or brackets, each felt possibly followed by
+// its short string in the form ('...').
+func parsePanicData(s string) []*felt.Felt {
+	var data []*felt.Felt
+	list := strings.HasPrefix(s, "(") || strings.HasPrefix(s, "[")
+	if list {
+		s = s[1:]
+	}
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		end := 0
+		for end < len(s) && (s[end] == 'x' || s[end] == 'X' || isHexDigit(s[end])) {
+			end++
+		}
+		value, err := new(felt.Felt).SetString(s[:end])
+		if end == 0 || err != nil {
+			return data
+		}
+		data = append(data, value)
+		s = strings.TrimLeftFunc(s[end:], unicode.IsSpace)
+
+		// skip the short string of the felt
+		if strings.HasPrefix(s, "('") {
+			closing := strings.Index(s, "')")
+			if closing < 0 {
+				return data
+			}
+			s = strings.TrimLeftFunc(s[closing+2:], unicode.IsSpace)
+		}
+		if !list || !strings.HasPrefix(s, ",") {
+			return data
+		}
+		s = s[1:]
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// DecodePanicData decodes the panic data of a Cairo contract. The ByteArrays
+// are decoded between double quotes and the printable short strings between
+// single quotes, as in Cairo, the other felts are kept in hex.
+//
+// Parameters:
+// - data: the panic data
+// Returns:
+// - string: the decoded felts, joined with ", "
+func (d *RevertDecoder) DecodePanicData(data []*felt.Felt) string {
+	var messages []string
+	for i := 0; i < len(data); i++ {
+		if data[i].Equal(byteArrayMagic) {
+			if message, n, ok := decodeByteArray(data[i+1:]); ok {
+				messages = append(messages, strconv.Quote(message))
+				i += n
+				continue
+			}
+		}
+		messages = append(messages, decodeShortString(data[i]))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// decodeByteArray decodes a serialized ByteArray: the number of its full words,
+// the words of 31 bytes, the pending word and the length of the pending word.
+// It returns the string and the number of felts of the ByteArray.
+func decodeByteArray(data []*felt.Felt) (string, int, bool) {
+	if len(data) == 0 {
+		return "", 0, false
+	}
+	words, ok := feltUint64(data[0])
+	// words comes from the contract, words+3 can overflow
+	if !ok || len(data) < 3 || words > uint64(len(data))-3 {
+		return "", 0, false
+	}
+	var b []byte
+	for _, word := range data[1 : words+1] {
+		bytes := word.Bytes()
+		b = append(b, bytes[len(bytes)-31:]...)
+	}
+	pendingLen, ok := feltUint64(data[words+2])
+	if !ok || pendingLen > 30 {
+		return "", 0, false
+	}
+	bytes := data[words+1].Bytes()
+	b = append(b, bytes[len(bytes)-int(pendingLen):]...)
+	return string(b), int(words) + 3, true
+}
+
+func feltUint64(f *felt.Felt) (uint64, bool) {
+	n := f.BigInt(new(big.Int))
+	return n.Uint64(), n.IsUint64()
+}
+
+// decodeShortString returns the short string of a felt between quotes if it is
+// printable ASCII, the felt in hex otherwise.
+func decodeShortString(f *felt.Felt) string {
+	bytes := f.Bytes()
+	s := strings.TrimLeft(string(bytes[:]), "\x00")
+	if s == "" {
+		return f.String()
+	}
+	for _, c := range []byte(s) {
+		if c < 0x20 || c > 0x7e {
+			return f.String()
+		}
+	}
+	return "'" + s + "'"
+}
+
+// DecodeReceipt decodes the revert reason of a receipt.
+//
+// Parameters:
+// - receipt: the receipt of a transaction
+// Returns:
+// - *DecodedRevert: the decoded revert
+// - bool: false if the transaction did not revert
+func (d *RevertDecoder) DecodeReceipt(receipt TransactionReceipt) (*DecodedRevert, bool) {
+	if receipt == nil || receipt.GetExecutionStatus() != TxnExecutionStatusREVERTED {
+		return nil, false
+	}
+	var reason string
+	switch r := receipt.(type) {
+	case CommonTransactionReceipt:
+		reason = r.RevertReason
+	case InvokeTransactionReceipt:
+		reason = r.RevertReason
+	case DeclareTransactionReceipt:
+		reason = r.RevertReason
+	case DeployTransactionReceipt:
+		reason = r.RevertReason
+	case DeployAccountTransactionReceipt:
+		reason = r.RevertReason
+	case L1HandlerTransactionReceipt:
+		reason = r.RevertReason
+	case PendingInvokeTransactionReceipt:
+		reason = r.RevertReason
+	case PendingDeclareTransactionReceipt:
+		reason = r.RevertReason
+	case PendingDeployAccountTransactionReceipt:
+		reason = r.RevertReason
+	case PendingL1HandlerTransactionReceipt:
+		reason = r.RevertReason
+	case UnknownTransactionReceipt:
+		return d.DecodeReceipt(r.TransactionReceipt)
+	}
+	return d.Decode(reason), true
+}
+
+// DecodeInvocation decodes the revert reason of the execution of a trace.
+//
+// Parameters:
+// - invocation: the execute invocation of a trace, e.g. of an InvokeTxnTrace
+// Returns:
+// - *DecodedRevert: the decoded revert
+// - bool: false if the execution did not revert
+func (d *RevertDecoder) DecodeInvocation(invocation ExecInvocation) (*DecodedRevert, bool) {
+	if invocation.RevertReason == "" {
+		return nil, false
+	}
+	return d.Decode(invocation.RevertReason), true
+}
+
+// DecodeError decodes the revert reason of an ErrContractError or the execution
+// error of an ErrTxnExec returned by a Provider.
+//
+// Parameters:
+// - err: the error of a request
+// Returns:
+// - *DecodedRevert: the decoded revert
+// - bool: false if the error has no revert reason
+func (d *RevertDecoder) DecodeError(err error) (*DecodedRevert, bool) {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return nil, false
+	}
+	switch data := rpcErr.Data().(type) {
+	case *ContractErrData:
+		return d.Decode(data.RevertError), true
+	case *TransactionExecErrData:
+		return d.Decode(data.ExecutionError), true
+	case string:
+		if rpcErr.Is(ErrContractError) || rpcErr.Is(ErrTxnExec) {
+			return d.Decode(data), true
+		}
+	}
+	return nil, false
+}
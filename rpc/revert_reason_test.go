@@ -0,0 +1,201 @@
+package rpc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+)
+
+// TestDecodeRevertReason tests the decoding of the revert reasons sent by the nodes.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestDecodeRevertReason(t *testing.T) {
+	type testSetType struct {
+		Name              string
+		Reason            string
+		SierraABI         string
+		ExpectedCalls     []RevertCall
+		ExpectedPanicData []*felt.Felt
+		ExpectedMessage   string
+		ExpectedString    string
+	}
+	account := utils.TestHexToFelt(t, "0x36d67ab362562a97f9fba8a1051cf8e37ff1a1449530fb9f1f0e32ac2da7d06")
+	accountClass := utils.TestHexToFelt(t, "0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f")
+	token := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	tokenClass := utils.TestHexToFelt(t, "0x7f3777c99f3700505ea966676aac4a0d692c2a9f5e667f4c606b51ca1dd3420")
+	execute := utils.GetSelectorFromNameFelt("__execute__")
+	transfer := utils.GetSelectorFromNameFelt("transfer")
+
+	testSet := []testSetType{
+		{
+			Name: "call stack",
+			Reason: fmt.Sprintf("Transaction execution has failed:\n"+
+				"0: Error in the called contract (contract address: %s, class hash: %s, selector: %s):\n"+
+				"Error at pc=0:4573:\nCairo traceback (most recent call last):\nUnknown location (pc=0:67)\nUnknown location (pc=0:1835)\n\n"+
+				"1: Error in the called contract (contract address: %s, class hash: %s, selector: %s):\n"+
+				"Execution failed. Failure reason: 0x753235365f737562204f766572666c6f77 ('u256_sub Overflow').\n",
+				account, accountClass, execute, token, tokenClass, transfer),
+			SierraABI: `[{"type":"impl","name":"ERC20Impl","interface_name":"IERC20"},
+				{"type":"interface","name":"IERC20","items":[{"type":"function","name":"transfer","inputs":[],"outputs":[],"state_mutability":"external"}]},
+				{"type":"constructor","name":"constructor","inputs":[]}]`,
+			ExpectedCalls: []RevertCall{
+				{ContractAddress: account, ClassHash: accountClass, Selector: execute, PC: "0:4573"},
+				{ContractAddress: token, ClassHash: tokenClass, Selector: transfer, FunctionName: "transfer"},
+			},
+			ExpectedPanicData: []*felt.Felt{utils.TestHexToFelt(t, "0x753235365f737562204f766572666c6f77")},
+			ExpectedMessage:   "'u256_sub Overflow'",
+			ExpectedString: fmt.Sprintf("0: %s (class %s) selector %s at pc=0:4573\n1: %s (class %s) transfer\nreason: 'u256_sub Overflow'",
+				account, accountClass, execute, token, tokenClass),
+		},
+		{
+			Name: "contract address only",
+			Reason: fmt.Sprintf("Error in the called contract (%s):\nError at pc=0:104:\n"+
+				"Got an exception while executing a hint: Custom Hint Error: Execution failed. Failure reason: 0x496e76616c6964207369676e6174757265 ('Invalid signature').", account),
+			ExpectedCalls:     []RevertCall{{ContractAddress: account, PC: "0:104"}},
+			ExpectedPanicData: []*felt.Felt{utils.TestHexToFelt(t, "0x496e76616c6964207369676e6174757265")},
+			ExpectedMessage:   "'Invalid signature'",
+			ExpectedString:    fmt.Sprintf("0: %s at pc=0:104\nreason: 'Invalid signature'", account),
+		},
+		{
+			Name: "byte array",
+			Reason: "Execution failed. Failure reason:\n(0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0x0, " +
+				"0x4e6f7420656e6f7567682062616c616e6365 ('Not enough balance'), 0x12, 0x454e545259504f494e545f4641494c4544 ('ENTRYPOINT_FAILED')).",
+			ExpectedPanicData: utils.TestHexArrToFelt(t, []string{
+				"0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3",
+				"0x0",
+				"0x4e6f7420656e6f7567682062616c616e6365",
+				"0x12",
+				"0x454e545259504f494e545f4641494c4544",
+			}),
+			ExpectedMessage: `"Not enough balance", 'ENTRYPOINT_FAILED'`,
+			ExpectedString:  `reason: "Not enough balance", 'ENTRYPOINT_FAILED'`,
+		},
+		{
+			Name:              "not a short string",
+			Reason:            "Execution was reverted; failure reason: [0x1, 0x496e73756666696369656e742062616c616e6365].",
+			ExpectedPanicData: utils.TestHexArrToFelt(t, []string{"0x1", "0x496e73756666696369656e742062616c616e6365"}),
+			ExpectedMessage:   "0x1, 'Insufficient balance'",
+			ExpectedString:    "reason: 0x1, 'Insufficient balance'",
+		},
+		{
+			Name: "byte array with an invalid length",
+			Reason: "Execution failed. Failure reason:\n(0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, " +
+				"0xffffffffffffffff, 0x0, 0x0).",
+			ExpectedPanicData: utils.TestHexArrToFelt(t, []string{
+				"0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3",
+				"0xffffffffffffffff",
+				"0x0",
+				"0x0",
+			}),
+			ExpectedMessage: "0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0xffffffffffffffff, 0x0, 0x0",
+			ExpectedString:  "reason: 0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0xffffffffffffffff, 0x0, 0x0",
+		},
+		{
+			Name:           "unknown format",
+			Reason:         "Insufficient max fee: max_fee: 100, actual_fee: 200",
+			ExpectedString: "Insufficient max fee: max_fee: 100, actual_fee: 200",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			decoder := NewRevertDecoder()
+			if test.SierraABI != "" {
+				require.NoError(t, decoder.AddSierraABI(test.SierraABI))
+			}
+			decoded := decoder.Decode(test.Reason)
+			require.Equal(t, test.Reason, decoded.Raw)
+			require.Equal(t, test.ExpectedCalls, decoded.Calls)
+			require.Equal(t, test.ExpectedPanicData, decoded.PanicData)
+			require.Equal(t, test.ExpectedMessage, decoded.Message)
+			require.Equal(t, test.ExpectedString, decoded.String())
+		})
+	}
+}
+
+// TestRevertDecoderSources tests the decoding of the revert reasons of the
+// receipts, of the traces and of the errors.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRevertDecoderSources(t *testing.T) {
+	type testSetType struct {
+		Name     string
+		Decode   func(decoder *RevertDecoder) (*DecodedRevert, bool)
+		Reverted bool
+	}
+	reason := "Execution failed. Failure reason: 0x4f7574206f6620676173 ('Out of gas')."
+	testSet := []testSetType{
+		{
+			Name: "reverted receipt",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeReceipt(InvokeTransactionReceipt{ExecutionStatus: TxnExecutionStatusREVERTED, RevertReason: reason})
+			},
+			Reverted: true,
+		},
+		{
+			Name: "reverted pending receipt",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeReceipt(UnknownTransactionReceipt{PendingInvokeTransactionReceipt{
+					PendingCommonTransactionReceiptProperties: PendingCommonTransactionReceiptProperties{ExecutionStatus: TxnExecutionStatusREVERTED, RevertReason: reason},
+				}})
+			},
+			Reverted: true,
+		},
+		{
+			Name: "succeeded receipt",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeReceipt(DeployAccountTransactionReceipt{CommonTransactionReceipt: CommonTransactionReceipt{ExecutionStatus: TxnExecutionStatusSUCCEEDED}})
+			},
+		},
+		{
+			Name: "reverted trace",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeInvocation(InvokeTxnTrace{ExecuteInvocation: ExecInvocation{RevertReason: reason}}.ExecuteInvocation)
+			},
+			Reverted: true,
+		},
+		{
+			Name: "contract error",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeError(&RPCError{code: 40, message: "Contract error", data: &ContractErrData{RevertError: reason}})
+			},
+			Reverted: true,
+		},
+		{
+			Name: "transaction execution error",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeError(&RPCError{code: 41, message: "Transaction execution error", data: &TransactionExecErrData{ExecutionError: reason}})
+			},
+			Reverted: true,
+		},
+		{
+			Name: "other error",
+			Decode: func(decoder *RevertDecoder) (*DecodedRevert, bool) {
+				return decoder.DecodeError(ErrBlockNotFound)
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			decoded, ok := test.Decode(NewRevertDecoder())
+			require.Equal(t, test.Reverted, ok)
+			if !test.Reverted {
+				require.Nil(t, decoded)
+				return
+			}
+			require.Equal(t, "'Out of gas'", decoded.Message)
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// SupportedSpecVersions are the major.minor versions of the Starknet JSON-RPC
+// specification the Provider encodes and decodes.
+var SupportedSpecVersions = []string{"0.5", "0.6", "0.7"}
+
+// ErrIncompatibleSpecVersion is returned by the requests of a Provider checking
+// the spec version with SpecVersionFail when the node speaks an unsupported version.
+var ErrIncompatibleSpecVersion = errors.New("incompatible Starknet JSON-RPC spec version")
+
+// SpecVersionCheck defines what a Provider does when the node speaks an unsupported spec version.
+type SpecVersionCheck int
+
+const (
+	// SpecVersionWarn logs a warning and decodes the responses as the latest supported version
+	SpecVersionWarn SpecVersionCheck = iota
+	// SpecVersionFail fails every request with ErrIncompatibleSpecVersion
+	SpecVersionFail
+)
+
+// ProviderOption is an option of NewProvider.
+type ProviderOption func(*Provider)
+
+// WithSpecVersionCheck detects the spec version of the node with starknet_specVersion
+// on the first request of the provider. The requests and the responses are then
+// translated between the version of the node and the types of this package.
+// The detection is retried by the next request if the node cannot be reached.
+//
+// Parameters:
+// - check: what to do when the node speaks an unsupported version
+// - logger: the logger of the warnings, slog.Default() if nil
+// Returns:
+// - ProviderOption: the option
+func WithSpecVersionCheck(check SpecVersionCheck, logger *slog.Logger) ProviderOption {
+	return func(provider *Provider) {
+		negotiator := provider.specNegotiator()
+		negotiator.check = check
+		if logger != nil {
+			negotiator.logger = logger
+		}
+	}
+}
+
+// WithSpecVersion sets the spec version of the node instead of detecting it.
+//
+// Parameters:
+// - version: the version of the node, e.g. "0.6.0"
+// Returns:
+// - ProviderOption: the option
+func WithSpecVersion(version string) ProviderOption {
+	return func(provider *Provider) {
+		provider.specNegotiator().pinned = version
+	}
+}
+
+// specNegotiator returns the negotiator of the provider, creating it if needed.
+func (provider *Provider) specNegotiator() *specNegotiator {
+	if provider.spec == nil {
+		provider.spec = &specNegotiator{logger: slog.Default()}
+	}
+	return provider.spec
+}
+
+// CheckSpecVersion returns the spec version of the node and checks that it is
+// supported. For a provider created with WithSpecVersionCheck or WithSpecVersion,
+// it negotiates the version if no request did yet.
+//
+// Parameters:
+// - ctx: the context of the request
+// Returns:
+// - string: the spec version of the node
+// - error: ErrIncompatibleSpecVersion if the version is not supported, unless
+// the provider checks it with SpecVersionWarn, or an error of the request
+func (provider *Provider) CheckSpecVersion(ctx context.Context) (string, error) {
+	if provider.spec != nil {
+		if _, err := provider.spec.negotiate(ctx, provider.spec.next); err != nil {
+			return provider.spec.negotiated(), err
+		}
+		return provider.spec.negotiated(), nil
+	}
+	version, err := provider.SpecVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := specCodecs[majorMinor(version)]; !ok {
+		return version, incompatibleSpecVersion(version)
+	}
+	return version, nil
+}
+
+func incompatibleSpecVersion(version string) error {
+	return fmt.Errorf("%w: the node speaks %q, supported versions are %s", ErrIncompatibleSpecVersion, version, strings.Join(SupportedSpecVersions, ", "))
+}
+
+// majorMinor returns the major.minor part of a semver version, "0.6" for "0.6.0".
+func majorMinor(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// specNegotiator negotiates the spec version on the first request and
+// translates the requests and the responses with the codec of the version.
+type specNegotiator struct {
+	check  SpecVersionCheck
+	logger *slog.Logger
+	// pinned is the version set with WithSpecVersion
+	pinned string
+	// next is the Handler after the negotiator in the middleware chain
+	next Handler
+
+	mu      sync.Mutex
+	version string
+	codec   specCodec
+	// err is set once the version is found incompatible with SpecVersionFail
+	err error
+}
+
+func (s *specNegotiator) negotiated() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+// negotiate returns the codec of the version of the node, detecting the version
+// with next if it is not known yet.
+func (s *specNegotiator) negotiate(ctx context.Context, next Handler) (specCodec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.codec != nil || s.err != nil {
+		return s.codec, s.err
+	}
+
+	version := s.pinned
+	if version == "" {
+		raw, err := next(ctx, &Request{Method: "starknet_specVersion"})
+		if err == nil {
+			err = json.Unmarshal(raw, &version)
+		}
+		if err != nil && errorCode(err) == 0 {
+			// the node could not be reached, the next request detects the version again
+			if s.check == SpecVersionFail {
+				return nil, fmt.Errorf("detect the spec version: %w", err)
+			}
+			s.logger.Warn("cannot detect the Starknet JSON-RPC spec version", "error", err)
+			return specCodecs[latestSpecVersion()], nil
+		}
+		if err != nil {
+			// the node answered but does not know starknet_specVersion, it predates 0.4
+			version = "unknown"
+		}
+	}
+
+	s.version = version
+	if codec, ok := specCodecs[majorMinor(version)]; ok {
+		s.codec = codec
+		return codec, nil
+	}
+	if s.check == SpecVersionFail {
+		s.err = incompatibleSpecVersion(version)
+		return nil, s.err
+	}
+	s.logger.Warn("unsupported Starknet JSON-RPC spec version, the responses may not decode",
+		"version", version, "supported", strings.Join(SupportedSpecVersions, ", "))
+	s.codec = specCodecs[latestSpecVersion()]
+	return s.codec, nil
+}
+
+// middleware negotiates the version and translates the requests with its codec.
+func (s *specNegotiator) middleware(next Handler) Handler {
+	s.next = next
+	return func(ctx context.Context, req *Request) (json.RawMessage, error) {
+		if req.Method == "starknet_specVersion" {
+			return next(ctx, req)
+		}
+		codec, err := s.negotiate(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Method == BatchMethod {
+			for i := range req.Batch {
+				req.Batch[i].Args = codec.encodeParams(req.Batch[i].Method, req.Batch[i].Args)
+			}
+			if _, err := next(ctx, req); err != nil {
+				return nil, err
+			}
+			for i, elem := range req.Batch {
+				result, ok := elem.Result.(*json.RawMessage)
+				if elem.Error != nil || !ok || len(*result) == 0 {
+					continue
+				}
+				*result, req.Batch[i].Error = codec.decodeResult(elem.Method, *result)
+			}
+			return nil, nil
+		}
+
+		raw, err := next(ctx, &Request{Method: req.Method, Params: codec.encodeParams(req.Method, req.Params)})
+		if err != nil || len(raw) == 0 {
+			return raw, err
+		}
+		return codec.decodeResult(req.Method, raw)
+	}
+}
+
+// specCodec translates the requests and the responses between a version of the
+// spec and the types of this package, which follow the latest supported version.
+type specCodec interface {
+	// encodeParams returns the params of a request as expected by the version
+	encodeParams(method string, params []interface{}) []interface{}
+	// decodeResult returns the result of a request as decoded by the types of this package
+	decodeResult(method string, raw json.RawMessage) (json.RawMessage, error)
+}
+
+// specCodecs are the codecs of SupportedSpecVersions.
+var specCodecs = map[string]specCodec{
+	"0.5": specV05{},
+	"0.6": specIdentity{},
+	// the fields added by 0.7 are optional fields of the types
+	"0.7": specIdentity{},
+}
+
+func latestSpecVersion() string {
+	return SupportedSpecVersions[len(SupportedSpecVersions)-1]
+}
+
+// specIdentity is the codec of the versions encoded as the types of this package.
+type specIdentity struct{}
+
+func (specIdentity) encodeParams(method string, params []interface{}) []interface{} {
+	return params
+}
+
+func (specIdentity) decodeResult(method string, raw json.RawMessage) (json.RawMessage, error) {
+	return raw, nil
+}
+
+// specV05 is the codec of the spec version 0.5: starknet_estimateFee has no
+// simulation flags, the fee estimates have no unit and the actual fee of the
+// receipts is a felt in wei.
+type specV05 struct{}
+
+func (specV05) encodeParams(method string, params []interface{}) []interface{} {
+	if method == "starknet_estimateFee" && len(params) == 3 {
+		return []interface{}{params[0], params[2]}
+	}
+	return params
+}
+
+func (specV05) decodeResult(method string, raw json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case "starknet_getTransactionReceipt":
+		return rewriteObject(raw, feePaymentV05)
+	case "starknet_estimateFee":
+		return rewriteArray(raw, func(raw json.RawMessage) (json.RawMessage, error) {
+			return rewriteObject(raw, feeEstimateV05)
+		})
+	case "starknet_estimateMessageFee":
+		return rewriteObject(raw, feeEstimateV05)
+	case "starknet_simulateTransactions":
+		return rewriteArray(raw, func(raw json.RawMessage) (json.RawMessage, error) {
+			return rewriteObject(raw, func(fields map[string]json.RawMessage) error {
+				estimate, ok := fields["fee_estimation"]
+				if !ok {
+					return nil
+				}
+				estimate, err := rewriteObject(estimate, feeEstimateV05)
+				fields["fee_estimation"] = estimate
+				return err
+			})
+		})
+	}
+	return raw, nil
+}
+
+// feePaymentV05 converts the actual fee of a receipt to a FeePayment in wei.
+func feePaymentV05(fields map[string]json.RawMessage) error {
+	fee, ok := fields["actual_fee"]
+	if !ok || !bytes.HasPrefix(bytes.TrimSpace(fee), []byte(`"`)) {
+		return nil
+	}
+	payment, err := json.Marshal(map[string]json.RawMessage{"amount": fee, "unit": json.RawMessage(`"` + UnitWei + `"`)})
+	fields["actual_fee"] = payment
+	return err
+}
+
+// feeEstimateV05 sets the unit of a fee estimate, always wei.
+func feeEstimateV05(fields map[string]json.RawMessage) error {
+	if _, ok := fields["unit"]; !ok {
+		fields["unit"] = json.RawMessage(`"` + UnitWei + `"`)
+	}
+	return nil
+}
+
+// rewriteObject decodes a JSON object, rewrites its fields and encodes it back.
+// Results other than objects are returned unchanged.
+func rewriteObject(raw json.RawMessage, rewrite func(fields map[string]json.RawMessage) error) (json.RawMessage, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		return raw, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if err := rewrite(fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// rewriteArray decodes a JSON array, rewrites its elements and encodes it back.
+// Results other than arrays are returned unchanged.
+func rewriteArray(raw json.RawMessage, rewrite func(json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return raw, nil
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, err
+	}
+	for i := range elems {
+		elem, err := rewrite(elems[i])
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = elem
+	}
+	return json.Marshal(elems)
+}
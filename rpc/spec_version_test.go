@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+)
+
+// updateGolden rewrites the golden files of the tests instead of comparing them
+var updateGolden bool
+
+// specNode is a JSON-RPC server answering with the fixtures of a spec version.
+type specNode struct {
+	version string
+	// fixtures are the results of the methods, by method
+	fixtures map[string]json.RawMessage
+
+	mu sync.Mutex
+	// params are the params received, by method
+	params map[string]json.RawMessage
+}
+
+func (n *specNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	n.params[req.Method] = req.Params
+	n.mu.Unlock()
+
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if req.Method == "starknet_specVersion" {
+		response["result"] = n.version
+	} else if result, ok := n.fixtures[req.Method]; ok {
+		response["result"] = result
+	} else {
+		response["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// newSpecNodeProvider starts a specNode and returns a provider with its options.
+func newSpecNodeProvider(t *testing.T, node *specNode, opts ...ProviderOption) *Provider {
+	node.params = map[string]json.RawMessage{}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return NewProvider(client, opts...)
+}
+
+// TestSpecVersionGolden tests the requests and the responses of each supported
+// spec version against golden files. Run the tests with -update to rewrite them.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSpecVersionGolden(t *testing.T) {
+	type testSetType struct {
+		Name    string
+		Method  string
+		Request func(ctx context.Context, provider *Provider) (interface{}, error)
+	}
+	hash := new(felt.Felt).SetUint64(1)
+	testSet := []testSetType{
+		{
+			Name:   "receipt",
+			Method: "starknet_getTransactionReceipt",
+			Request: func(ctx context.Context, provider *Provider) (interface{}, error) {
+				return provider.TransactionReceipt(ctx, hash)
+			},
+		},
+		{
+			Name:   "estimate_fee",
+			Method: "starknet_estimateFee",
+			Request: func(ctx context.Context, provider *Provider) (interface{}, error) {
+				return provider.EstimateFee(ctx, []BroadcastTxn{}, []SimulationFlag{SKIP_VALIDATE}, WithBlockTag("latest"))
+			},
+		},
+		{
+			Name:   "block",
+			Method: "starknet_getBlockWithTxHashes",
+			Request: func(ctx context.Context, provider *Provider) (interface{}, error) {
+				return provider.BlockWithTxHashes(ctx, WithBlockTag("latest"))
+			},
+		},
+	}
+
+	for _, version := range SupportedSpecVersions {
+		t.Run(version, func(t *testing.T) {
+			dir := filepath.Join("tests", "spec", version)
+			node := &specNode{version: version + ".0", fixtures: map[string]json.RawMessage{}}
+			for _, test := range testSet {
+				fixture, err := os.ReadFile(filepath.Join(dir, test.Name+".json"))
+				require.NoError(t, err)
+				node.fixtures[test.Method] = fixture
+			}
+			provider := newSpecNodeProvider(t, node, WithSpecVersionCheck(SpecVersionFail, nil))
+
+			for _, test := range testSet {
+				result, err := test.Request(context.Background(), provider)
+				require.NoError(t, err, test.Name)
+				got, err := json.MarshalIndent(map[string]interface{}{
+					"params": node.params[test.Method],
+					"result": result,
+				}, "", "  ")
+				require.NoError(t, err)
+				got = append(got, '\n')
+
+				golden := filepath.Join(dir, test.Name+".golden.json")
+				if updateGolden {
+					require.NoError(t, os.WriteFile(golden, got, 0o644))
+					continue
+				}
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				require.Equal(t, string(want), string(got), test.Name)
+			}
+		})
+	}
+}
+
+// TestSpecVersionCheck tests the checks of the spec version of the node.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSpecVersionCheck(t *testing.T) {
+	type testSetType struct {
+		Name            string
+		NodeVersion     string
+		Options         []ProviderOption
+		ExpectedVersion string
+		ExpectedError   error
+		ExpectedWarning bool
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	testSet := []testSetType{
+		{
+			Name:            "supported",
+			NodeVersion:     "0.6.0",
+			Options:         []ProviderOption{WithSpecVersionCheck(SpecVersionFail, logger)},
+			ExpectedVersion: "0.6.0",
+		},
+		{
+			Name:            "unsupported warns",
+			NodeVersion:     "0.8.0",
+			Options:         []ProviderOption{WithSpecVersionCheck(SpecVersionWarn, logger)},
+			ExpectedVersion: "0.8.0",
+			ExpectedWarning: true,
+		},
+		{
+			Name:            "unsupported fails",
+			NodeVersion:     "0.4.0",
+			Options:         []ProviderOption{WithSpecVersionCheck(SpecVersionFail, logger)},
+			ExpectedVersion: "0.4.0",
+			ExpectedError:   ErrIncompatibleSpecVersion,
+		},
+		{
+			Name:            "pinned",
+			NodeVersion:     "0.4.0",
+			Options:         []ProviderOption{WithSpecVersion("0.6.1"), WithSpecVersionCheck(SpecVersionFail, logger)},
+			ExpectedVersion: "0.6.1",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			logs.Reset()
+			node := &specNode{version: test.NodeVersion, fixtures: map[string]json.RawMessage{"starknet_blockNumber": json.RawMessage("42")}}
+			provider := newSpecNodeProvider(t, node, test.Options...)
+
+			blockNumber, err := provider.BlockNumber(context.Background())
+			version, checkErr := provider.CheckSpecVersion(context.Background())
+			require.Equal(t, test.ExpectedVersion, version)
+			require.Equal(t, test.ExpectedWarning, bytes.Contains(logs.Bytes(), []byte("unsupported Starknet JSON-RPC spec version")))
+			if test.ExpectedError != nil {
+				require.True(t, errors.Is(err, test.ExpectedError))
+				require.True(t, errors.Is(checkErr, test.ExpectedError))
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, checkErr)
+			require.Equal(t, uint64(42), blockNumber)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// The subscription methods of the Starknet websocket spec. Each subscription
+// type has its own notification method, and the reorganisations of the chain
+// are notified on starknet_subscriptionReorg.
+const (
+	subscribeNewHeadsMethod             = "starknet_subscribeNewHeads"
+	subscribeEventsMethod               = "starknet_subscribeEvents"
+	subscribeTransactionStatusMethod    = "starknet_subscribeTransactionStatus"
+	unsubscribeMethod                   = "starknet_unsubscribe"
+	subscriptionNewHeadsMethod          = "starknet_subscriptionNewHeads"
+	subscriptionEventsMethod            = "starknet_subscriptionEvents"
+	subscriptionTransactionStatusMethod = "starknet_subscriptionTransactionStatus"
+	subscriptionReorgMethod             = "starknet_subscriptionReorg"
+)
+
+var ErrSubscriptionNotSupported = errors.New("subscriptions require a websocket connection, see NewWebsocketProvider")
+
+type subscriber interface {
+	subscribe(ctx context.Context, method string, params interface{}) (*clientSubscription, error)
+}
+
+// Subscription delivers the notifications of a subscription on typed channels.
+// The channels are closed when the subscription ends, either because Unsubscribe
+// is called, the context given to the subscribe method is done, or the
+// connection fails, in which case the error is sent on Err first.
+type Subscription[T any] struct {
+	sub *clientSubscription
+	// method is the notification method of the results
+	method  string
+	results chan T
+	reorgs  chan *ReorgEvent
+	err     chan error
+	quit    chan struct{}
+	once    sync.Once
+}
+
+// Results returns the channel of the notifications.
+func (s *Subscription[T]) Results() <-chan T {
+	return s.results
+}
+
+// Reorgs returns the channel of the reorganisations affecting the notifications.
+func (s *Subscription[T]) Reorgs() <-chan *ReorgEvent {
+	return s.reorgs
+}
+
+// Err returns the channel receiving the error that ended the subscription.
+// Nothing is sent when the subscription is unsubscribed.
+func (s *Subscription[T]) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe sends starknet_unsubscribe to the node and closes the channels.
+// It can be called more than once.
+func (s *Subscription[T]) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+		s.sub.unsubscribe()
+	})
+}
+
+// SubscribeNewHeads subscribes to the headers of the new blocks.
+//
+// Parameters:
+// - ctx: the context of the subscription, it is unsubscribed when ctx is done
+// - blockID: the block from which the headers are sent, the latest block when nil
+// Returns:
+// - *Subscription[*BlockHeader]: the subscription
+// - error: an error if the subscription fails
+func (provider *Provider) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (*Subscription[*BlockHeader], error) {
+	params := map[string]interface{}{}
+	if blockID != nil {
+		params["block_id"] = blockID
+	}
+	return subscribe[*BlockHeader](ctx, provider.c, subscribeNewHeadsMethod, subscriptionNewHeadsMethod, params)
+}
+
+// SubscribeEvents subscribes to the events matching the filter.
+//
+// Parameters:
+// - ctx: the context of the subscription, it is unsubscribed when ctx is done
+// - input: the filter of the events
+// Returns:
+// - *Subscription[*EmittedEvent]: the subscription
+// - error: an error if the subscription fails
+func (provider *Provider) SubscribeEvents(ctx context.Context, input EventSubscriptionInput) (*Subscription[*EmittedEvent], error) {
+	return subscribe[*EmittedEvent](ctx, provider.c, subscribeEventsMethod, subscriptionEventsMethod, input)
+}
+
+// SubscribeTransactionStatus subscribes to the status changes of a transaction.
+//
+// Parameters:
+// - ctx: the context of the subscription, it is unsubscribed when ctx is done
+// - transactionHash: the hash of the transaction
+// Returns:
+// - *Subscription[*NewTxnStatus]: the subscription
+// - error: an error if the subscription fails
+func (provider *Provider) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*Subscription[*NewTxnStatus], error) {
+	params := map[string]interface{}{"transaction_hash": transactionHash}
+	return subscribe[*NewTxnStatus](ctx, provider.c, subscribeTransactionStatusMethod, subscriptionTransactionStatusMethod, params)
+}
+
+// subscribe sends the subscribe method and starts forwarding the notifications
+// of the notification method and the reorganisations.
+func subscribe[T any](ctx context.Context, c callCloser, method, notificationMethod string, params interface{}) (*Subscription[T], error) {
+	client, ok := c.(subscriber)
+	if !ok {
+		return nil, ErrSubscriptionNotSupported
+	}
+	sub, err := client.subscribe(ctx, method, params)
+	if err != nil {
+		return nil, tryUnwrapToRPCErr(err)
+	}
+	s := &Subscription[T]{
+		sub:     sub,
+		method:  notificationMethod,
+		results: make(chan T),
+		reorgs:  make(chan *ReorgEvent),
+		err:     make(chan error, 1),
+		quit:    make(chan struct{}),
+	}
+	go s.forward(ctx)
+	return s, nil
+}
+
+// forward decodes the notifications until the subscription ends.
+func (s *Subscription[T]) forward(ctx context.Context) {
+	defer func() {
+		close(s.results)
+		close(s.reorgs)
+		close(s.err)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			s.Unsubscribe()
+			return
+		case <-s.quit:
+			return
+		case err := <-s.sub.err:
+			// the error channel is closed without error on unsubscribe
+			if err != nil {
+				s.err <- err
+			}
+			return
+		case n := <-s.sub.notifications:
+			var ok bool
+			switch n.method {
+			case subscriptionReorgMethod:
+				ok = forwardNotification(ctx, s, s.reorgs, n.result)
+			case s.method:
+				ok = forwardNotification(ctx, s, s.results, n.result)
+			default:
+				// the notifications of the other methods are not for this subscription
+				ok = true
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// forwardNotification decodes a notification and sends it on ch, it returns
+// false if the subscription ended meanwhile or the notification can't be decoded.
+func forwardNotification[T, V any](ctx context.Context, s *Subscription[T], ch chan<- V, raw json.RawMessage) bool {
+	var value V
+	if err := json.Unmarshal(raw, &value); err != nil {
+		s.err <- err
+		s.once.Do(func() {
+			close(s.quit)
+			s.sub.unsubscribe()
+		})
+		return false
+	}
+	select {
+	case ch <- value:
+		return true
+	case <-ctx.Done():
+		s.Unsubscribe()
+		return false
+	case <-s.quit:
+		return false
+	}
+}
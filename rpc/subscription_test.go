@@ -0,0 +1,272 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/test-go/testify/require"
+)
+
+// wsNode is a websocket node speaking the wire format of the Starknet websocket
+// spec: starknet_subscribe<Type> requests, starknet_subscription<Type> and
+// starknet_subscriptionReorg notifications with named params.
+type wsNode struct {
+	t      *testing.T
+	header *BlockHeader
+	reorg  *ReorgEvent
+	// requests receives the method and the params of the requests
+	requests chan wsMessage
+	// unsubscribed receives the ids of the unsubscribed subscriptions
+	unsubscribed chan string
+}
+
+func (n *wsNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	var writeMu sync.Mutex
+	send := func(msg interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.WriteJSON(msg)
+	}
+	notify := func(method string, id interface{}, result interface{}) {
+		send(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  method,
+			"params":  map[string]interface{}{"subscription_id": id, "result": result},
+		})
+	}
+	respond := func(id *uint64, result interface{}) {
+		send(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result})
+	}
+
+	for {
+		var req wsMessage
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		n.requests <- req
+		switch req.Method {
+		case "starknet_chainId":
+			respond(req.ID, "0x534e5f5345504f4c4941")
+		case "starknet_getClassHashAt":
+			send(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": map[string]interface{}{"code": 20, "message": "Contract not found"}})
+		case "starknet_subscribeNewHeads":
+			// a string subscription id
+			respond(req.ID, "7")
+			// the notifications of the geth convention are not part of the spec
+			send(map[string]interface{}{"jsonrpc": "2.0", "method": "starknet_subscription", "params": map[string]interface{}{"subscription": "7", "result": "ignored"}})
+			notify("starknet_subscriptionNewHeads", "7", n.header)
+			notify("starknet_subscriptionReorg", "7", n.reorg)
+		case "starknet_subscribeEvents":
+			// a number subscription id
+			respond(req.ID, 8)
+			var input EventSubscriptionInput
+			require.NoError(n.t, json.Unmarshal(req.Params, &input))
+			notify("starknet_subscriptionEvents", 8, &EmittedEvent{
+				Event: Event{
+					FromAddress: input.FromAddress,
+					Keys:        []*felt.Felt{input.Keys[0][0]},
+					Data:        []*felt.Felt{new(felt.Felt).SetUint64(1)},
+				},
+				BlockHash:       n.header.BlockHash,
+				BlockNumber:     n.header.BlockNumber,
+				TransactionHash: new(felt.Felt).SetUint64(0x42),
+			})
+		case "starknet_subscribeTransactionStatus":
+			respond(req.ID, "9")
+			var params struct {
+				TransactionHash *felt.Felt `json:"transaction_hash"`
+			}
+			require.NoError(n.t, json.Unmarshal(req.Params, &params))
+			notify("starknet_subscriptionTransactionStatus", "9", &NewTxnStatus{
+				TransactionHash: params.TransactionHash,
+				Status:          TxnStatusResp{FinalityStatus: TxnStatus_Accepted_On_L2, ExecutionStatus: TxnExecutionStatusSUCCEEDED},
+			})
+			// a notification that cannot be decoded
+			notify("starknet_subscriptionTransactionStatus", "9", "not a status")
+		case "starknet_unsubscribe":
+			var params wsNotification
+			require.NoError(n.t, json.Unmarshal(req.Params, &params))
+			respond(req.ID, true)
+			n.unsubscribed <- subscriptionKey(params.SubscriptionID)
+		}
+	}
+}
+
+// newSubscriptionProvider serves the node over a websocket and returns a
+// Provider connected to it.
+func newSubscriptionProvider(t *testing.T, node *wsNode) *Provider {
+	httpServer := httptest.NewServer(node)
+	t.Cleanup(httpServer.Close)
+
+	provider, err := NewWebsocketProvider(context.Background(), "ws"+strings.TrimPrefix(httpServer.URL, "http"))
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	return provider
+}
+
+// TestSubscriptions tests the new heads, events and transaction status subscriptions
+// against a websocket node speaking the wire format of the spec.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSubscriptions(t *testing.T) {
+	node := &wsNode{
+		t: t,
+		header: &BlockHeader{
+			BlockHash:        utils.TestHexToFelt(t, "0xb10c"),
+			ParentHash:       utils.TestHexToFelt(t, "0xb10b"),
+			BlockNumber:      100,
+			NewRoot:          utils.TestHexToFelt(t, "0x1"),
+			Timestamp:        1700000000,
+			SequencerAddress: utils.TestHexToFelt(t, "0x2"),
+			L1GasPrice:       ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x3"), PriceInFRI: utils.TestHexToFelt(t, "0x4")},
+			StarknetVersion:  "0.13.0",
+		},
+		reorg: &ReorgEvent{
+			StartBlockHash: utils.TestHexToFelt(t, "0xb10a"),
+			StartBlockNum:  98,
+			EndBlockHash:   utils.TestHexToFelt(t, "0xb10b"),
+			EndBlockNum:    99,
+		},
+		requests:     make(chan wsMessage, 10),
+		unsubscribed: make(chan string, 3),
+	}
+	provider := newSubscriptionProvider(t, node)
+
+	t.Run("calls", func(t *testing.T) {
+		chainID, err := provider.ChainID(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "SN_SEPOLIA", chainID)
+		require.Equal(t, "starknet_chainId", receive(t, node.requests).Method)
+
+		_, err = provider.ClassHashAt(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+		require.True(t, errors.Is(err, ErrContractNotFound))
+		require.JSONEq(t, `["latest","0x1"]`, string(receive(t, node.requests).Params))
+	})
+
+	t.Run("new heads", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub, err := provider.SubscribeNewHeads(ctx, &BlockID{Tag: "latest"})
+		require.NoError(t, err)
+		req := receive(t, node.requests)
+		require.Equal(t, "starknet_subscribeNewHeads", req.Method)
+		require.JSONEq(t, `{"block_id":"latest"}`, string(req.Params))
+
+		require.Equal(t, node.header, receive(t, sub.Results()))
+		require.Equal(t, node.reorg, receive(t, sub.Reorgs()))
+
+		// cancelling the context unsubscribes and closes the channels
+		cancel()
+		require.Equal(t, "7", receive(t, node.unsubscribed))
+		require.Equal(t, "starknet_unsubscribe", receive(t, node.requests).Method)
+		_, ok := <-sub.Results()
+		require.False(t, ok)
+		_, ok = <-sub.Err()
+		require.False(t, ok)
+		sub.Unsubscribe()
+	})
+
+	t.Run("events", func(t *testing.T) {
+		contract := utils.TestHexToFelt(t, "0xc0ffee")
+		key := utils.GetSelectorFromNameFelt("Transfer")
+		sub, err := provider.SubscribeEvents(context.Background(), EventSubscriptionInput{FromAddress: contract, Keys: [][]*felt.Felt{{key}}})
+		require.NoError(t, err)
+		require.Equal(t, "starknet_subscribeEvents", receive(t, node.requests).Method)
+
+		event := receive(t, sub.Results())
+		require.Equal(t, contract, event.FromAddress)
+		require.Equal(t, key, event.Keys[0])
+		require.Equal(t, node.header.BlockNumber, event.BlockNumber)
+
+		sub.Unsubscribe()
+		require.Equal(t, "8", receive(t, node.unsubscribed))
+		receive(t, node.requests)
+		_, ok := <-sub.Results()
+		require.False(t, ok)
+	})
+
+	t.Run("transaction status", func(t *testing.T) {
+		txHash := utils.TestHexToFelt(t, "0x42")
+		sub, err := provider.SubscribeTransactionStatus(context.Background(), txHash)
+		require.NoError(t, err)
+		require.Equal(t, "starknet_subscribeTransactionStatus", receive(t, node.requests).Method)
+
+		status := receive(t, sub.Results())
+		require.Equal(t, txHash, status.TransactionHash)
+		require.Equal(t, TxnStatus_Accepted_On_L2, status.Status.FinalityStatus)
+
+		// a notification that cannot be decoded ends the subscription with an error
+		require.Error(t, receive(t, sub.Err()))
+		require.Equal(t, "9", receive(t, node.unsubscribed))
+		receive(t, node.requests)
+	})
+
+	t.Run("closed connection", func(t *testing.T) {
+		sub, err := provider.SubscribeNewHeads(context.Background(), nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{}`, string(receive(t, node.requests).Params))
+		receive(t, sub.Results())
+		receive(t, sub.Reorgs())
+
+		// closing the provider ends the subscriptions and the calls with an error
+		provider.Close()
+		require.True(t, errors.Is(receive(t, sub.Err()), ErrClientClosed))
+		_, err = provider.ClassHashAt(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+		require.True(t, errors.Is(err, ErrClientClosed))
+	})
+}
+
+// TestSubscriptionNotSupported tests that subscribing without a websocket provider fails.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSubscriptionNotSupported(t *testing.T) {
+	server := ethrpc.NewServer()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	client, err := NewClient(httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	for _, provider := range []*Provider{NewProvider(client), {c: &rpcMock{}}} {
+		_, err = provider.SubscribeNewHeads(context.Background(), nil)
+		require.True(t, errors.Is(err, ErrSubscriptionNotSupported))
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a notification")
+	}
+	var zero T
+	return zero
+}
@@ -132,3 +132,21 @@ func (provider *Provider) GetTransactionStatus(ctx context.Context, transactionH
 	}
 	return &receipt, nil
 }
+
+// GetMessagesStatus gets the status of the L1 to L2 messages sent by an L1 transaction,
+// in the order they were sent.
+//
+// Parameters:
+// - ctx: the context.Context object for cancellation and timeouts.
+// - transactionHash: the hash of the L1 transaction sending the messages
+// Returns:
+// - []MessageStatus: the status of the messages
+// - error, if one arose.
+func (provider *Provider) GetMessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatus, error) {
+	var statuses []MessageStatus
+	err := do(ctx, provider.c, "starknet_getMessagesStatus", &statuses, transactionHash)
+	if err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
+	}
+	return statuses, nil
+}
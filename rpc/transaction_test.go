@@ -439,3 +439,57 @@ func TestGetTransactionStatus(t *testing.T) {
 		require.Equal(t, *resp, test.ExpectedResp)
 	}
 }
+
+// TestGetMessagesStatus tests starknet_getMessagesStatus
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGetMessagesStatus(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	type testSetType struct {
+		TxnHash      NumAsHex
+		ExpectedResp []MessageStatus
+		ExpectedErr  error
+	}
+
+	testSet := map[string][]testSetType{
+		"mock": {
+			{
+				TxnHash: "0x6c5ca4e7eb5f7b1c2b0e1e5b1d5a0f3c9d5d8f5b1e6a2c4d8f1a3b5c7d9e1f3a",
+				ExpectedResp: []MessageStatus{
+					{
+						TransactionHash: utils.TestHexToFelt(t, "0x1f2a3c"),
+						FinalityStatus:  TxnStatus_Accepted_On_L2,
+						ExecutionStatus: TxnExecutionStatusSUCCEEDED,
+					},
+					{
+						TransactionHash: utils.TestHexToFelt(t, "0x4b5d6e"),
+						FinalityStatus:  TxnStatus_Accepted_On_L2,
+						ExecutionStatus: TxnExecutionStatusREVERTED,
+						FailureReason:   "Assertion failed",
+					},
+				},
+			},
+			{
+				TxnHash:     "0x1",
+				ExpectedErr: ErrHashNotFound,
+			},
+		},
+		"testnet": {},
+		"mainnet": {},
+	}[testEnv]
+
+	for _, test := range testSet {
+		resp, err := testConfig.provider.GetMessagesStatus(context.Background(), test.TxnHash)
+		if test.ExpectedErr != nil {
+			require.Equal(t, test.ExpectedErr, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedResp, resp)
+	}
+}
@@ -144,25 +144,18 @@ func (s SyncStatus) MarshalJSON() ([]byte, error) {
 // Returns:
 // - error: an error if the unmarshaling fails
 func (s *SyncStatus) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, s)
-
-	// if string(data) == "false" {
-	// 	s.SyncStatus = false
-	// 	return nil
-	// }
-	// s.SyncStatus = true
-	// output := map[string]interface{}{}
-	// err := json.Unmarshal(data, &output)
-	// if err != nil {
-	// 	return err
-	// }
-	// s.StartingBlockHash = output["starting_block_hash"].(string)
-	// s.StartingBlockNum = utils.NumAsHex(output["starting_block_num"].(string))
-	// s.CurrentBlockHash = output["current_block_hash"].(string)
-	// s.CurrentBlockNum = utils.NumAsHex(output["current_block_num"].(string))
-	// s.HighestBlockHash = output["highest_block_hash"].(string)
-	// s.HighestBlockNum = utils.NumAsHex(output["highest_block_num"].(string))
-	// return nil
+	if string(data) == "false" {
+		*s = SyncStatus{}
+		return nil
+	}
+	type syncStatus SyncStatus
+	var status syncStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	*s = SyncStatus(status)
+	s.SyncStatus = true
+	return nil
 }
 
 // AddDeclareTransactionOutput provides the output for AddDeclareTransaction.
@@ -194,7 +187,14 @@ type FeeEstimate struct {
 	// The gas price (in gwei or fri, depending on the tx version) that was used in the cost estimation
 	GasPrice *felt.Felt `json:"gas_price"`
 
-	// The estimated fee for the transaction (in gwei or fri, depending on the tx version), product of gas_consumed and gas_price
+	// The L1 data gas consumed by the transaction, set from the spec version 0.7
+	DataGasConsumed *felt.Felt `json:"data_gas_consumed,omitempty"`
+
+	// The L1 data gas price (in gwei or fri, depending on the tx version), set from the spec version 0.7
+	DataGasPrice *felt.Felt `json:"data_gas_price,omitempty"`
+
+	// The estimated fee for the transaction (in gwei or fri, depending on the tx version), gas_consumed * gas_price,
+	// plus data_gas_consumed * data_gas_price from the spec version 0.7
 	OverallFee *felt.Felt `json:"overall_fee"`
 
 	// Units in which the fee is given
@@ -130,11 +130,210 @@ type BlockTxHashes struct {
 	Transactions []*felt.Felt `json:"transactions"`
 }
 
+// TransactionWithReceipt is a transaction of a block with its receipt.
+type TransactionWithReceipt struct {
+	Transaction Transaction        `json:"transaction"`
+	Receipt     TransactionReceipt `json:"receipt"`
+}
+
+type BlockWithReceipts struct {
+	BlockHeader
+	Status BlockStatus `json:"status"`
+	// Transactions The transactions in this block with their receipts
+	Transactions []TransactionWithReceipt `json:"transactions"`
+}
+
+type PendingBlockWithReceipts struct {
+	PendingBlockHeader
+	// Transactions The transactions in this block with their receipts
+	Transactions []TransactionWithReceipt `json:"transactions"`
+}
+
+// rawBlockWithReceipts is a block returned by starknet_getBlockWithReceipts.
+// Its receipts have no block hash and number, they are set from the header.
+type rawBlockWithReceipts struct {
+	BlockHeader
+	Status       BlockStatus `json:"status"`
+	Transactions []struct {
+		Transaction UnknownTransaction     `json:"transaction"`
+		Receipt     map[string]interface{} `json:"receipt"`
+	} `json:"transactions"`
+}
+
 type PendingBlockTxHashes struct {
 	PendingBlockHeader
 	Transactions []*felt.Felt `json:"transactions"`
 }
 
+// BlockTxHashesResult is the result of BlockWithTxHashes, it holds either
+// Block or PendingBlock. Pending reports which one is set.
+type BlockTxHashesResult struct {
+	Block        *BlockTxHashes
+	PendingBlock *PendingBlockTxHashes
+}
+
+// Pending reports whether the result is a pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if PendingBlock is set
+func (r *BlockTxHashesResult) Pending() bool {
+	return r.PendingBlock != nil
+}
+
+// BlockHash returns the hash of the block, nil for a pending block.
+func (r *BlockTxHashesResult) BlockHash() *felt.Felt {
+	if r.Pending() {
+		return nil
+	}
+	return r.Block.BlockHash
+}
+
+// ParentHash returns the hash of the parent of the block.
+func (r *BlockTxHashesResult) ParentHash() *felt.Felt {
+	return r.header().ParentHash
+}
+
+// Timestamp returns the time in which the block was created, encoded in Unix time.
+func (r *BlockTxHashesResult) Timestamp() uint64 {
+	return r.header().Timestamp
+}
+
+// SequencerAddress returns the address of the sequencer of the block.
+func (r *BlockTxHashesResult) SequencerAddress() *felt.Felt {
+	return r.header().SequencerAddress
+}
+
+// L1GasPrice returns the price of L1 gas in the block.
+func (r *BlockTxHashesResult) L1GasPrice() ResourcePrice {
+	return r.header().L1GasPrice
+}
+
+// StarknetVersion returns the version of the Starknet protocol of the block.
+func (r *BlockTxHashesResult) StarknetVersion() string {
+	return r.header().StarknetVersion
+}
+
+// Transactions returns the hashes of the transactions of the block.
+func (r *BlockTxHashesResult) Transactions() []*felt.Felt {
+	if r.Pending() {
+		return r.PendingBlock.Transactions
+	}
+	return r.Block.Transactions
+}
+
+func (r *BlockTxHashesResult) header() PendingBlockHeader {
+	if r.Pending() {
+		return r.PendingBlock.PendingBlockHeader
+	}
+	return r.Block.BlockHeader.pendingHeader()
+}
+
+// BlockWithReceiptsResult is the result of BlockWithReceipts, it holds either
+// Block or PendingBlock. Pending reports which one is set.
+type BlockWithReceiptsResult struct {
+	Block        *BlockWithReceipts
+	PendingBlock *PendingBlockWithReceipts
+}
+
+// Pending reports whether the result is a pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if PendingBlock is set
+func (r *BlockWithReceiptsResult) Pending() bool {
+	return r.PendingBlock != nil
+}
+
+// BlockHash returns the hash of the block, nil for a pending block.
+func (r *BlockWithReceiptsResult) BlockHash() *felt.Felt {
+	if r.Pending() {
+		return nil
+	}
+	return r.Block.BlockHash
+}
+
+// Transactions returns the transactions of the block with their receipts.
+func (r *BlockWithReceiptsResult) Transactions() []TransactionWithReceipt {
+	if r.Pending() {
+		return r.PendingBlock.Transactions
+	}
+	return r.Block.Transactions
+}
+
+// BlockWithTxsResult is the result of BlockWithTxs, it holds either
+// Block or PendingBlock. Pending reports which one is set.
+type BlockWithTxsResult struct {
+	Block        *Block
+	PendingBlock *PendingBlock
+}
+
+// Pending reports whether the result is a pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if PendingBlock is set
+func (r *BlockWithTxsResult) Pending() bool {
+	return r.PendingBlock != nil
+}
+
+// BlockHash returns the hash of the block, nil for a pending block.
+func (r *BlockWithTxsResult) BlockHash() *felt.Felt {
+	if r.Pending() {
+		return nil
+	}
+	return r.Block.BlockHash
+}
+
+// ParentHash returns the hash of the parent of the block.
+func (r *BlockWithTxsResult) ParentHash() *felt.Felt {
+	return r.header().ParentHash
+}
+
+// Timestamp returns the time in which the block was created, encoded in Unix time.
+func (r *BlockWithTxsResult) Timestamp() uint64 {
+	return r.header().Timestamp
+}
+
+// SequencerAddress returns the address of the sequencer of the block.
+func (r *BlockWithTxsResult) SequencerAddress() *felt.Felt {
+	return r.header().SequencerAddress
+}
+
+// L1GasPrice returns the price of L1 gas in the block.
+func (r *BlockWithTxsResult) L1GasPrice() ResourcePrice {
+	return r.header().L1GasPrice
+}
+
+// StarknetVersion returns the version of the Starknet protocol of the block.
+func (r *BlockWithTxsResult) StarknetVersion() string {
+	return r.header().StarknetVersion
+}
+
+// Transactions returns the transactions of the block.
+func (r *BlockWithTxsResult) Transactions() BlockTransactions {
+	if r.Pending() {
+		return r.PendingBlock.BlockTransactions
+	}
+	return r.Block.Transactions
+}
+
+func (r *BlockWithTxsResult) header() PendingBlockHeader {
+	if r.Pending() {
+		return r.PendingBlock.PendingBlockHeader
+	}
+	return r.Block.BlockHeader.pendingHeader()
+}
+
 type BlockHeader struct {
 	// BlockHash The hash of this block
 	BlockHash *felt.Felt `json:"block_hash"`
@@ -150,10 +349,27 @@ type BlockHeader struct {
 	SequencerAddress *felt.Felt `json:"sequencer_address"`
 	// The price of l1 gas in the block
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
+	// The price of l1 data gas in the block, set from the spec version 0.7
+	L1DataGasPrice *ResourcePrice `json:"l1_data_gas_price,omitempty"`
+	// How the state diff of the block is published on L1, set from the spec version 0.7
+	L1DAMode L1DAMode `json:"l1_da_mode,omitempty"`
 	// Semver of the current Starknet protocol
 	StarknetVersion string `json:"starknet_version"`
 }
 
+// pendingHeader returns the fields of the header shared with pending blocks.
+func (h BlockHeader) pendingHeader() PendingBlockHeader {
+	return PendingBlockHeader{
+		ParentHash:       h.ParentHash,
+		Timestamp:        h.Timestamp,
+		SequencerAddress: h.SequencerAddress,
+		L1GasPrice:       h.L1GasPrice,
+		L1DataGasPrice:   h.L1DataGasPrice,
+		L1DAMode:         h.L1DAMode,
+		StarknetVersion:  h.StarknetVersion,
+	}
+}
+
 type PendingBlockHeader struct {
 	// ParentHash The hash of this block's parent
 	ParentHash *felt.Felt `json:"parent_hash"`
@@ -163,13 +379,24 @@ type PendingBlockHeader struct {
 	SequencerAddress *felt.Felt `json:"sequencer_address"`
 	// The price of l1 gas in the block
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
+	// The price of l1 data gas in the block, set from the spec version 0.7
+	L1DataGasPrice *ResourcePrice `json:"l1_data_gas_price,omitempty"`
+	// How the state diff of the block is published on L1, set from the spec version 0.7
+	L1DAMode L1DAMode `json:"l1_da_mode,omitempty"`
 	// Semver of the current Starknet protocol
 	StarknetVersion string `json:"starknet_version"`
 }
 
+type L1DAMode string
+
+const (
+	L1DAModeBlob     L1DAMode = "BLOB"
+	L1DAModeCalldata L1DAMode = "CALLDATA"
+)
+
 type ResourcePrice struct {
 	// the price of one unit of the given resource, denominated in fri (10^-18 strk)
-	PriceInFRI *felt.Felt `json:"price_in_strk,omitempty"`
+	PriceInFRI *felt.Felt `json:"price_in_fri,omitempty"`
 	// The price of one unit of the given resource, denominated in wei
 	PriceInWei *felt.Felt `json:"price_in_wei"`
 }
@@ -122,3 +122,67 @@ func TestBlock_Unmarshal(t *testing.T) {
 		t.Fatalf("Unmarshalling block: %v", err)
 	}
 }
+
+// TestBlockResults tests the accessors of BlockTxHashesResult and BlockWithTxsResult
+// on blocks and pending blocks.
+//
+// Parameters:
+// - t: the testing object for running the test
+// Returns:
+//  none
+func TestBlockResults(t *testing.T) {
+	header := BlockHeader{
+		BlockHash:        new(felt.Felt).SetUint64(2),
+		ParentHash:       new(felt.Felt).SetUint64(1),
+		BlockNumber:      2,
+		Timestamp:        123,
+		SequencerAddress: new(felt.Felt).SetUint64(3),
+		L1GasPrice:       ResourcePrice{PriceInWei: new(felt.Felt).SetUint64(4), PriceInFRI: new(felt.Felt).SetUint64(5)},
+		StarknetVersion:  "0.13.0",
+	}
+	pendingHeader := PendingBlockHeader{
+		ParentHash:       header.ParentHash,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		StarknetVersion:  header.StarknetVersion,
+	}
+	txHashes := []*felt.Felt{new(felt.Felt).SetUint64(6)}
+	txs := BlockTransactions{BlockInvokeTxnV1{TransactionHash: txHashes[0]}}
+
+	type blockResult interface {
+		Pending() bool
+		BlockHash() *felt.Felt
+		ParentHash() *felt.Felt
+		Timestamp() uint64
+		SequencerAddress() *felt.Felt
+		L1GasPrice() ResourcePrice
+		StarknetVersion() string
+	}
+	testSet := []struct {
+		result  blockResult
+		pending bool
+	}{
+		{&BlockTxHashesResult{Block: &BlockTxHashes{BlockHeader: header, Transactions: txHashes}}, false},
+		{&BlockTxHashesResult{PendingBlock: &PendingBlockTxHashes{pendingHeader, txHashes}}, true},
+		{&BlockWithTxsResult{Block: &Block{BlockHeader: header, Transactions: txs}}, false},
+		{&BlockWithTxsResult{PendingBlock: &PendingBlock{pendingHeader, txs}}, true},
+	}
+	for _, test := range testSet {
+		result := test.result
+		if result.Pending() != test.pending {
+			t.Fatalf("expected pending %v, got %v", test.pending, result.Pending())
+		}
+		if test.pending && result.BlockHash() != nil {
+			t.Fatalf("expected no block hash, got %s", result.BlockHash())
+		}
+		if !test.pending && result.BlockHash() != header.BlockHash {
+			t.Fatalf("expected block hash %s, got %s", header.BlockHash, result.BlockHash())
+		}
+		if result.ParentHash() != header.ParentHash || result.Timestamp() != header.Timestamp ||
+			result.SequencerAddress() != header.SequencerAddress || result.L1GasPrice() != header.L1GasPrice ||
+			result.StarknetVersion() != header.StarknetVersion {
+			t.Fatalf("unexpected header accessors for %T", result)
+		}
+	}
+}
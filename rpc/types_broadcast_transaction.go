@@ -7,10 +7,12 @@ type BroadcastTxn interface{}
 var (
 	_ BroadcastTxn = BroadcastInvokev0Txn{}
 	_ BroadcastTxn = BroadcastInvokev1Txn{}
+	_ BroadcastTxn = BroadcastInvokev3Txn{}
 	_ BroadcastTxn = BroadcastDeclareTxnV1{}
 	_ BroadcastTxn = BroadcastDeclareTxnV2{}
 	_ BroadcastTxn = BroadcastDeclareTxnV3{}
 	_ BroadcastTxn = BroadcastDeployAccountTxn{}
+	_ BroadcastTxn = BroadcastDeployAccountTxnV3{}
 )
 
 type BroadcastInvokeTxnType interface{}
@@ -83,7 +85,7 @@ type BroadcastDeclareTxnV3 struct {
 	// The data needed to allow the paymaster to pay for the transaction in native tokens
 	PayMasterData []*felt.Felt `json:"paymaster_data"`
 	// The data needed to deploy the account contract from which this tx will be initiated
-	AccountDeploymentData *felt.Felt `json:"account_deployment_data"`
+	AccountDeploymentData []*felt.Felt `json:"account_deployment_data"`
 	// The storage domain of the account's nonce (an account has a nonce per DA mode)
 	NonceDataMode DataAvailabilityMode `json:"nonce_data_availability_mode"`
 	// The storage domain of the account's balance from which fee will be charged
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// StorageProofInput is the input of GetStorageProof.
+type StorageProofInput struct {
+	// BlockID The block of the proofs, the pending block is not supported
+	BlockID BlockID
+	// ClassHashes The classes to prove in the classes trie
+	ClassHashes []*felt.Felt
+	// ContractAddresses The contracts to prove in the contracts trie
+	ContractAddresses []*felt.Felt
+	// ContractsStorageKeys The storage keys to prove in the storage tries of the contracts
+	ContractsStorageKeys []ContractStorageKeys
+}
+
+// ContractStorageKeys are storage keys of a contract.
+type ContractStorageKeys struct {
+	ContractAddress *felt.Felt   `json:"contract_address"`
+	StorageKeys     []*felt.Felt `json:"storage_keys"`
+}
+
+// StorageProofResult is the result of GetStorageProof: the Merkle paths of the
+// requested classes, contracts and storage keys, and the roots they lead to.
+type StorageProofResult struct {
+	ClassesProof   []NodeHashToNode `json:"classes_proof"`
+	ContractsProof ContractsProof   `json:"contracts_proof"`
+	// ContractsStorageProofs The proofs of the storage keys, in the order of StorageProofInput.ContractsStorageKeys
+	ContractsStorageProofs [][]NodeHashToNode `json:"contracts_storage_proofs"`
+	GlobalRoots            GlobalRoots        `json:"global_roots"`
+}
+
+// NodeHashToNode is a node of a Merkle-Patricia trie with its hash.
+type NodeHashToNode struct {
+	NodeHash *felt.Felt `json:"node_hash"`
+	Node     MerkleNode `json:"node"`
+}
+
+// MerkleNode is a node of a Merkle-Patricia trie, either Binary or Edge.
+type MerkleNode struct {
+	Binary *BinaryNode
+	Edge   *EdgeNode
+}
+
+// BinaryNode is an internal node of a trie, with the hashes of its children.
+type BinaryNode struct {
+	Left  *felt.Felt `json:"left"`
+	Right *felt.Felt `json:"right"`
+}
+
+// EdgeNode is a path of a trie without branches, leading to Child.
+type EdgeNode struct {
+	// Path The bits of the path, the first bit is the most significant one
+	Path *felt.Felt `json:"path"`
+	// Length The number of bits of the path
+	Length uint64 `json:"length"`
+	// Child The hash of the node at the end of the path
+	Child *felt.Felt `json:"child"`
+}
+
+// MarshalJSON marshals the node that is set.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON of the node
+// - error: an error if no node is set
+func (n MerkleNode) MarshalJSON() ([]byte, error) {
+	switch {
+	case n.Binary != nil:
+		return json.Marshal(n.Binary)
+	case n.Edge != nil:
+		return json.Marshal(n.Edge)
+	}
+	return nil, errors.New("empty merkle node")
+}
+
+// UnmarshalJSON unmarshals a binary node if the JSON has a left child, an edge node otherwise.
+//
+// Parameters:
+// - data: the JSON of the node
+// Returns:
+// - error: an error if the unmarshaling fails
+func (n *MerkleNode) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if _, ok := fields["left"]; ok {
+		var binary BinaryNode
+		if err := json.Unmarshal(data, &binary); err != nil {
+			return err
+		}
+		*n = MerkleNode{Binary: &binary}
+		return nil
+	}
+	if _, ok := fields["path"]; !ok {
+		return errors.New("merkle node is neither a binary nor an edge node")
+	}
+	var edge EdgeNode
+	if err := json.Unmarshal(data, &edge); err != nil {
+		return err
+	}
+	*n = MerkleNode{Edge: &edge}
+	return nil
+}
+
+// ContractsProof is the proof of the contracts in the contracts trie.
+type ContractsProof struct {
+	Nodes []NodeHashToNode `json:"nodes"`
+	// ContractLeavesData The leaves of the contracts, in the order of StorageProofInput.ContractAddresses
+	ContractLeavesData []ContractLeafData `json:"contract_leaves_data"`
+}
+
+// ContractLeafData is the data of a contract hashed in its leaf of the contracts trie.
+type ContractLeafData struct {
+	Nonce       *felt.Felt `json:"nonce"`
+	ClassHash   *felt.Felt `json:"class_hash"`
+	StorageRoot *felt.Felt `json:"storage_root,omitempty"`
+}
+
+// GlobalRoots are the roots of the tries of the block of a storage proof.
+type GlobalRoots struct {
+	ContractsTreeRoot *felt.Felt `json:"contracts_tree_root"`
+	ClassesTreeRoot   *felt.Felt `json:"classes_tree_root"`
+	// BlockHash The hash of the block, committing to the roots
+	BlockHash *felt.Felt `json:"block_hash"`
+}
@@ -0,0 +1,32 @@
+package rpc
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// ReorgEvent is notified to the subscriptions, on starknet_subscriptionReorg, when the chain reorganises. The
+// notifications of the blocks from StartBlockNum to EndBlockNum are no longer valid.
+type ReorgEvent struct {
+	// StartBlockHash the hash of the first known block of the orphaned chain
+	StartBlockHash *felt.Felt `json:"starting_block_hash"`
+	// StartBlockNum the number of the first known block of the orphaned chain
+	StartBlockNum uint64 `json:"starting_block_number"`
+	// EndBlockHash the hash of the last known block of the orphaned chain
+	EndBlockHash *felt.Felt `json:"ending_block_hash"`
+	// EndBlockNum the number of the last known block of the orphaned chain
+	EndBlockNum uint64 `json:"ending_block_number"`
+}
+
+// EventSubscriptionInput is the filter of an event subscription.
+type EventSubscriptionInput struct {
+	// FromAddress filters the events emitted by this contract
+	FromAddress *felt.Felt `json:"from_address,omitempty"`
+	// Keys the values used to filter the events
+	Keys [][]*felt.Felt `json:"keys,omitempty"`
+	// BlockID the block from which the events are sent, the latest block when nil
+	BlockID *BlockID `json:"block_id,omitempty"`
+}
+
+// NewTxnStatus is the notification of a transaction status subscription.
+type NewTxnStatus struct {
+	TransactionHash *felt.Felt    `json:"transaction_hash"`
+	Status          TxnStatusResp `json:"status"`
+}
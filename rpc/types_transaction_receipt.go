@@ -394,6 +394,16 @@ type ExecutionResources struct {
 	KeccakApps int `json:"keccak_builtin_applications,omitempty"`
 	// The number of accesses to the segment arena
 	SegmentArenaBuiltin int `json:"segment_arena_builtin,omitempty"`
+	// The data availability resources of the transaction, set from the spec version 0.7
+	DataAvailability *DataAvailability `json:"data_availability,omitempty"`
+}
+
+// DataAvailability is the gas consumed by the data availability of a transaction
+type DataAvailability struct {
+	// The gas consumed by the data availability of the transaction, when it is published in the calldata
+	L1Gas uint64 `json:"l1_gas"`
+	// The data gas consumed by the data availability of the transaction, when it is published in blobs
+	L1DataGas uint64 `json:"l1_data_gas"`
 }
 
 // Validate checks if the fields are non-zero (to match the starknet-specs)
@@ -565,3 +575,13 @@ type TxnStatusResp struct {
 	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
 	FinalityStatus  TxnStatus          `json:"finality_status"`
 }
+
+// MessageStatus is the status of an L1 to L2 message, returned by GetMessagesStatus.
+type MessageStatus struct {
+	// TransactionHash The hash of the L1 handler transaction consuming the message on L2
+	TransactionHash *felt.Felt         `json:"transaction_hash"`
+	FinalityStatus  TxnStatus          `json:"finality_status"`
+	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
+	// FailureReason The reason of the failure of the L1 handler transaction, set when it is reverted
+	FailureReason string `json:"failure_reason,omitempty"`
+}
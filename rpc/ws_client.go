@@ -0,0 +1,332 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrClientClosed         = errors.New("websocket client is closed")
+	ErrSubscriptionOverflow = errors.New("subscription notifications are not read fast enough")
+)
+
+const (
+	// maxSubscriptionBuffer is the number of notifications buffered per subscription
+	maxSubscriptionBuffer = 1000
+	// unsubscribeTimeout bounds the starknet_unsubscribe request sent on Unsubscribe
+	unsubscribeTimeout = 5 * time.Second
+)
+
+// wsMessage is a JSON-RPC request, response or notification.
+type wsMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *wsError        `json:"error,omitempty"`
+}
+
+// wsError is a JSON-RPC error, converted to an *RPCError by tryUnwrapToRPCErr.
+type wsError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *wsError) Error() string  { return e.Message }
+func (e *wsError) ErrorCode() int { return e.Code }
+func (e *wsError) ErrorData() interface{} {
+	var data interface{}
+	if len(e.Data) == 0 || json.Unmarshal(e.Data, &data) != nil {
+		return nil
+	}
+	return data
+}
+
+// wsNotification is the params of a subscription notification, e.g. starknet_subscriptionNewHeads.
+type wsNotification struct {
+	SubscriptionID json.RawMessage `json:"subscription_id"`
+	Result         json.RawMessage `json:"result"`
+}
+
+// notification is a notification received by a subscription.
+type notification struct {
+	method string
+	result json.RawMessage
+}
+
+// pendingCall is a request waiting for its response. When sub is set, the
+// subscription is registered by the reader as soon as its id is received, so
+// that no notification sent right after the response is lost.
+type pendingCall struct {
+	response chan *wsMessage
+	sub      *clientSubscription
+}
+
+// WsClient is a JSON-RPC client over a websocket, supporting the subscriptions
+// of the Starknet websocket spec (starknet_subscribeNewHeads, ...), whose
+// notifications are sent on a method per subscription type.
+type WsClient struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*pendingCall
+	subs    map[string]*clientSubscription
+	err     error
+	closed  chan struct{}
+}
+
+// NewWsClient connects to the websocket endpoint of a node.
+//
+// Parameters:
+// - ctx: the context of the connection
+// - url: the URL of the websocket endpoint, e.g. "ws://localhost:6061/v0_8"
+// Returns:
+// - *WsClient: the client
+// - error: an error if the connection fails
+func NewWsClient(ctx context.Context, url string) (*WsClient, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := &WsClient{
+		conn:    conn,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[string]*clientSubscription),
+		closed:  make(chan struct{}),
+	}
+	go c.read()
+	return c, nil
+}
+
+// CallContext sends a request with positional parameters and decodes its result.
+func (c *WsClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	resp, err := c.request(ctx, method, args, nil)
+	if err != nil {
+		return err
+	}
+	if r, ok := result.(*json.RawMessage); ok {
+		*r = resp.Result
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// Close closes the connection, ending the subscriptions.
+func (c *WsClient) Close() {
+	c.fail(ErrClientClosed)
+}
+
+// subscribe sends a subscribe request, e.g. starknet_subscribeNewHeads, and
+// returns the subscription receiving its notifications.
+func (c *WsClient) subscribe(ctx context.Context, method string, params interface{}) (*clientSubscription, error) {
+	sub := &clientSubscription{
+		client:        c,
+		notifications: make(chan *notification, maxSubscriptionBuffer),
+		err:           make(chan error, 1),
+	}
+	if _, err := c.request(ctx, method, params, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// request sends a request and waits for its response.
+func (c *WsClient) request(ctx context.Context, method string, params interface{}, sub *clientSubscription) (*wsMessage, error) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	call := &pendingCall{response: make(chan *wsMessage, 1), sub: sub}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = call
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(ctx, &wsMessage{Version: "2.0", ID: &id, Method: method, Params: rawParams}); err != nil {
+		return nil, err
+	}
+	select {
+	case resp := <-call.response:
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp, nil
+	case <-c.closed:
+		return nil, c.closeErr()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *WsClient) write(ctx context.Context, msg *wsMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return c.conn.WriteJSON(msg)
+}
+
+// read dispatches the responses and the notifications until the connection fails.
+func (c *WsClient) read() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.ID != nil:
+			c.handleResponse(&msg)
+		case msg.Method != "":
+			c.handleNotification(&msg)
+		}
+	}
+}
+
+func (c *WsClient) handleResponse(msg *wsMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.pending[*msg.ID]
+	if !ok {
+		return
+	}
+	if call.sub != nil && msg.Error == nil {
+		call.sub.id = msg.Result
+		c.subs[subscriptionKey(msg.Result)] = call.sub
+	}
+	call.response <- msg
+}
+
+func (c *WsClient) handleNotification(msg *wsMessage) {
+	var params wsNotification
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	c.mu.Lock()
+	sub, ok := c.subs[subscriptionKey(params.SubscriptionID)]
+	c.mu.Unlock()
+	if ok {
+		sub.push(&notification{method: msg.Method, result: params.Result})
+	}
+}
+
+// fail closes the connection and ends the pending calls and the subscriptions with err.
+func (c *WsClient) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.closed)
+	c.conn.Close()
+	for key, sub := range c.subs {
+		sub.end(err)
+		delete(c.subs, key)
+	}
+}
+
+func (c *WsClient) closeErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// subscriptionKey normalizes a subscription id, which is a string or a number
+// depending on the node.
+func subscriptionKey(id json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(id, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(id, &n); err == nil {
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return strconv.FormatUint(u, 10)
+		}
+	}
+	return string(id)
+}
+
+// clientSubscription receives the notifications of a subscription of a WsClient.
+type clientSubscription struct {
+	client        *WsClient
+	id            json.RawMessage
+	notifications chan *notification
+	// err receives the error ending the subscription, it is closed on unsubscribe
+	err  chan error
+	once sync.Once
+}
+
+// push queues a notification, the subscription fails if its consumer is too slow.
+// It is called by the reader of the client, the client mutex is not held.
+func (s *clientSubscription) push(n *notification) {
+	select {
+	case s.notifications <- n:
+	default:
+		s.client.mu.Lock()
+		delete(s.client.subs, subscriptionKey(s.id))
+		s.client.mu.Unlock()
+		s.end(ErrSubscriptionOverflow)
+		go s.sendUnsubscribe()
+	}
+}
+
+// end ends the subscription with err, or without error if err is nil.
+func (s *clientSubscription) end(err error) {
+	s.once.Do(func() {
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+	})
+}
+
+// unsubscribe stops the notifications and sends starknet_unsubscribe.
+func (s *clientSubscription) unsubscribe() {
+	s.client.mu.Lock()
+	delete(s.client.subs, subscriptionKey(s.id))
+	s.client.mu.Unlock()
+	s.end(nil)
+	s.sendUnsubscribe()
+}
+
+func (s *clientSubscription) sendUnsubscribe() {
+	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	defer cancel()
+	// the node may already have ended the subscription, the result doesn't matter
+	_, _ = s.client.request(ctx, unsubscribeMethod, map[string]json.RawMessage{"subscription_id": s.id}, nil)
+}
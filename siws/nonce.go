@@ -0,0 +1,101 @@
+package siws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// NonceStore keeps track of the nonces of the issued challenges, so that each
+// signed challenge is accepted only once, and only for the account and the
+// issuance time it was issued for.
+type NonceStore interface {
+	// Issue records the nonce of a new challenge for an account, issued at issuedAt
+	// and valid until expiration (forever if zero).
+	Issue(ctx context.Context, nonce string, address *felt.Felt, issuedAt, expiration time.Time) error
+	// Consume marks the nonce as used. It returns ErrInvalidNonce if the nonce
+	// was not issued for the account at issuedAt, has already been used or has expired.
+	Consume(ctx context.Context, nonce string, address *felt.Felt, issuedAt time.Time) error
+}
+
+// issuedNonce is the challenge a nonce was issued for.
+type issuedNonce struct {
+	address    felt.Felt
+	issuedAt   time.Time
+	expiration time.Time
+}
+
+// MemNonceStore implements the NonceStore interface in memory, for a single server.
+type MemNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]issuedNonce
+}
+
+// NewMemNonceStore initializes and returns a new instance of MemNonceStore.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *MemNonceStore: a pointer to MemNonceStore
+func NewMemNonceStore() *MemNonceStore {
+	return &MemNonceStore{
+		nonces: make(map[string]issuedNonce),
+	}
+}
+
+// Issue records the nonce of a new challenge and forgets the expired nonces.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - nonce: the nonce of the challenge
+// - address: the address of the account the challenge is issued for
+// - issuedAt: the issuance time of the challenge
+// - expiration: the expiration time of the challenge, no expiration if zero
+// Returns:
+// - error: ErrInvalidNonce if the nonce has already been issued
+func (s *MemNonceStore) Issue(ctx context.Context, nonce string, address *felt.Felt, issuedAt, expiration time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for n, issued := range s.nonces {
+		if !issued.expiration.IsZero() && !now.Before(issued.expiration) {
+			delete(s.nonces, n)
+		}
+	}
+	if _, ok := s.nonces[nonce]; ok || address == nil {
+		return ErrInvalidNonce
+	}
+	s.nonces[nonce] = issuedNonce{address: *address, issuedAt: issuedAt, expiration: expiration}
+	return nil
+}
+
+// Consume marks the nonce as used. A nonce presented for another account or
+// issuance time is kept, so that it can't be burnt by another account.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - nonce: the nonce of the signed challenge
+// - address: the address of the account of the signed challenge
+// - issuedAt: the issuance time of the signed challenge
+// Returns:
+// - error: ErrInvalidNonce if the nonce was not issued for the account at issuedAt,
+// has already been used or has expired
+func (s *MemNonceStore) Consume(ctx context.Context, nonce string, address *felt.Felt, issuedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.nonces[nonce]
+	if !ok || address == nil || !issued.address.Equal(address) || !issued.issuedAt.Equal(issuedAt) {
+		return ErrInvalidNonce
+	}
+	delete(s.nonces, nonce)
+	if !issued.expiration.IsZero() && !time.Now().Before(issued.expiration) {
+		return ErrInvalidNonce
+	}
+	return nil
+}
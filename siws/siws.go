@@ -0,0 +1,303 @@
+// Package siws implements Sign-In with Starknet: a server issues a challenge
+// message, the user signs it with their account as SNIP-12 typed data and the
+// server verifies the signature with the account contract.
+package siws
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typed"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrInvalidMessage = errors.New("Invalid sign-in message")
+	ErrWrongDomain    = errors.New("Sign-in message is for another domain")
+	ErrWrongURI       = errors.New("Sign-in message is for another URI")
+	ErrWrongChain     = errors.New("Sign-in message is for another chain")
+	ErrNotYetValid    = errors.New("Sign-in message is not yet valid")
+	ErrExpired        = errors.New("Sign-in message has expired")
+	ErrBadSignature   = errors.New("Invalid sign-in signature")
+	ErrInvalidNonce   = errors.New("Unknown or already used sign-in nonce")
+)
+
+const (
+	// DomainName is the name of the SNIP-12 domain of the sign-in messages
+	DomainName = "Sign In With Starknet"
+	// DomainVersion is the version of the SNIP-12 domain of the sign-in messages
+	DomainVersion = "1"
+	// DefaultTTL is the validity of the challenges issued by a Verifier
+	DefaultTTL = 5 * time.Minute
+)
+
+// Message is a sign-in challenge for an account.
+type Message struct {
+	// Domain The domain of the site requesting the sign-in, e.g. "example.com"
+	Domain string
+	// Address The address of the account signing in
+	Address *felt.Felt
+	// Statement A human-readable statement shown to the user by the wallet
+	Statement string
+	// URI The URI of the resource the user is signing in to
+	URI string
+	// ChainId The chain ID of the account, e.g. "SN_MAIN"
+	ChainId string
+	// Nonce A random value protecting against replays
+	Nonce string
+	// IssuedAt The time at which the challenge was issued
+	IssuedAt time.Time
+	// ExpirationTime The time after which the challenge is no longer valid, no expiration if zero
+	ExpirationTime time.Time
+}
+
+// messageTypes are the SNIP-12 revision 1 types of the sign-in messages.
+func messageTypes() map[string]typed.TypeDef {
+	return map[string]typed.TypeDef{
+		"StarknetDomain": {Definitions: []typed.Definition{
+			{Name: "name", Type: "shortstring"},
+			{Name: "version", Type: "shortstring"},
+			{Name: "chainId", Type: "shortstring"},
+			{Name: "revision", Type: "shortstring"},
+		}},
+		"Message": {Definitions: []typed.Definition{
+			{Name: "domain", Type: "string"},
+			{Name: "address", Type: "ContractAddress"},
+			{Name: "statement", Type: "string"},
+			{Name: "uri", Type: "string"},
+			{Name: "nonce", Type: "string"},
+			{Name: "issuedAt", Type: "timestamp"},
+			{Name: "expirationTime", Type: "timestamp"},
+		}},
+	}
+}
+
+// TypedData returns the SNIP-12 typed data of the message, as signed by the wallet.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - typed.TypedData: the typed data of the message
+// - error: an error if the message is incomplete
+func (msg Message) TypedData() (typed.TypedData, error) {
+	if msg.Address == nil || msg.Domain == "" || msg.ChainId == "" || msg.Nonce == "" {
+		return typed.TypedData{}, ErrInvalidMessage
+	}
+	td, err := typed.NewTypedData(messageTypes(), "Message", typed.Domain{
+		Name:     DomainName,
+		Version:  DomainVersion,
+		ChainId:  msg.ChainId,
+		Revision: "1",
+	})
+	if err != nil {
+		return typed.TypedData{}, err
+	}
+
+	var expirationTime int64
+	if !msg.ExpirationTime.IsZero() {
+		expirationTime = msg.ExpirationTime.Unix()
+	}
+	td.Message = typed.Message{
+		"domain":         msg.Domain,
+		"address":        msg.Address.String(),
+		"statement":      msg.Statement,
+		"uri":            msg.URI,
+		"nonce":          msg.Nonce,
+		"issuedAt":       msg.IssuedAt.Unix(),
+		"expirationTime": expirationTime,
+	}
+	return td, nil
+}
+
+// ParseMessage reads a sign-in message from its typed data, e.g. as sent back
+// by the client with the signature.
+//
+// Parameters:
+// - td: the typed data of the message
+// Returns:
+// - *Message: the sign-in message
+// - error: ErrInvalidMessage if the typed data is not a sign-in message
+func ParseMessage(td typed.TypedData) (*Message, error) {
+	data, ok := td.Message.(typed.Message)
+	if !ok || td.PrimaryType != "Message" || td.Domain.Name != DomainName {
+		return nil, ErrInvalidMessage
+	}
+
+	fields := make(map[string]string, len(data))
+	for _, def := range messageTypes()["Message"].Definitions {
+		value, ok := data[def.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing field %s", ErrInvalidMessage, def.Name)
+		}
+		fields[def.Name] = fmt.Sprint(value)
+	}
+
+	address, err := utils.HexToFelt(fields["address"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid address %s", ErrInvalidMessage, fields["address"])
+	}
+	issuedAt, err := strconv.ParseInt(fields["issuedAt"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid issuedAt %s", ErrInvalidMessage, fields["issuedAt"])
+	}
+	expirationTime, err := strconv.ParseInt(fields["expirationTime"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid expirationTime %s", ErrInvalidMessage, fields["expirationTime"])
+	}
+
+	msg := &Message{
+		Domain:    fields["domain"],
+		Address:   address,
+		Statement: fields["statement"],
+		URI:       fields["uri"],
+		ChainId:   td.Domain.ChainId,
+		Nonce:     fields["nonce"],
+		IssuedAt:  time.Unix(issuedAt, 0),
+	}
+	if expirationTime != 0 {
+		msg.ExpirationTime = time.Unix(expirationTime, 0)
+	}
+	return msg, nil
+}
+
+// Verifier issues the sign-in challenges of a site and verifies their signatures.
+type Verifier struct {
+	provider rpc.RpcProvider
+	nonces   NonceStore
+	// Domain The domain of the site, e.g. "example.com"
+	Domain string
+	// URI The URI set in the challenges
+	URI string
+	// ChainId The chain ID of the accounts, e.g. "SN_MAIN"
+	ChainId string
+	// TTL The validity of the challenges, no expiration if zero
+	TTL time.Duration
+}
+
+// NewVerifier creates a new Verifier for a site.
+//
+// Parameters:
+// - provider: the provider used to call the account contracts
+// - domain: the domain of the site
+// - uri: the URI set in the challenges
+// - chainId: the chain ID of the accounts
+// - nonces: the store of the issued nonces, a MemNonceStore if nil
+// Returns:
+// - *Verifier: a pointer to the new Verifier, issuing challenges valid for DefaultTTL
+func NewVerifier(provider rpc.RpcProvider, domain, uri, chainId string, nonces NonceStore) *Verifier {
+	if nonces == nil {
+		nonces = NewMemNonceStore()
+	}
+	return &Verifier{
+		provider: provider,
+		nonces:   nonces,
+		Domain:   domain,
+		URI:      uri,
+		ChainId:  chainId,
+		TTL:      DefaultTTL,
+	}
+}
+
+// NewChallenge issues a new sign-in challenge for an account.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - address: the address of the account signing in
+// - statement: the statement shown to the user by the wallet
+// Returns:
+// - *Message: the challenge, to be signed as typed data
+// - error: an error if the nonce can't be generated or stored
+func (v *Verifier) NewChallenge(ctx context.Context, address *felt.Felt, statement string) (*Message, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{
+		Domain:    v.Domain,
+		Address:   address,
+		Statement: statement,
+		URI:       v.URI,
+		ChainId:   v.ChainId,
+		Nonce:     nonce,
+		IssuedAt:  time.Now().Truncate(time.Second),
+	}
+	if v.TTL != 0 {
+		msg.ExpirationTime = msg.IssuedAt.Add(v.TTL)
+	}
+	if err := v.nonces.Issue(ctx, nonce, address, msg.IssuedAt, msg.ExpirationTime); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Verify verifies a signed sign-in message and consumes its nonce, which must
+// have been issued for the account and the issuance time of the message.
+//
+// The signature is verified with the is_valid_signature entry point of the
+// account contract, so any account signature scheme is supported.
+//
+// Parameters:
+// - ctx: the context.Context for the function execution
+// - msg: the signed message
+// - signature: the signature of the typed data of the message
+// Returns:
+// - error: ErrWrongDomain, ErrWrongURI, ErrWrongChain, ErrNotYetValid, ErrExpired, ErrBadSignature
+// or ErrInvalidNonce if the sign-in is rejected, or the error of the node call
+func (v *Verifier) Verify(ctx context.Context, msg Message, signature []*felt.Felt) error {
+	if msg.Domain != v.Domain {
+		return ErrWrongDomain
+	}
+	if msg.URI != v.URI {
+		return ErrWrongURI
+	}
+	if msg.ChainId != v.ChainId {
+		return ErrWrongChain
+	}
+	now := time.Now()
+	if now.Before(msg.IssuedAt) {
+		return ErrNotYetValid
+	}
+	if !msg.ExpirationTime.IsZero() && !now.Before(msg.ExpirationTime) {
+		return ErrExpired
+	}
+
+	td, err := msg.TypedData()
+	if err != nil {
+		return err
+	}
+	// without a public key, the signature is always verified by the account contract
+	acnt, err := account.NewAccount(v.provider, msg.Address, "", account.NewMemKeystore(), 0)
+	if err != nil {
+		return err
+	}
+	valid, err := acnt.VerifyTypedData(ctx, td, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrBadSignature
+	}
+
+	// the nonce is consumed once the signature is checked, so that invalid
+	// signatures can't burn the nonce of a pending sign-in
+	return v.nonces.Consume(ctx, msg.Nonce, msg.Address, msg.IssuedAt)
+}
+
+// newNonce generates a random nonce of 32 hexadecimal characters.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
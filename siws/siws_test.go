@@ -0,0 +1,223 @@
+package siws_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/mocks"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/siws"
+	"github.com/NethermindEth/starknet.go/typed"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+)
+
+// TestMessage_TypedData tests the round trip of a sign-in message through its
+// JSON typed data.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMessage_TypedData(t *testing.T) {
+	msg := siws.Message{
+		Domain:         "example.com",
+		Address:        utils.TestHexToFelt(t, "0x5b5e9f6f6fb7d2647d81a8b2c2b99cbc9cc9d03d705576d7061812324dca5c0"),
+		Statement:      "Sign in to example.com",
+		URI:            "https://example.com/login",
+		ChainId:        "SN_MAIN",
+		Nonce:          "8f5c2e7a9b1d4f6e",
+		IssuedAt:       time.Unix(1700000000, 0),
+		ExpirationTime: time.Unix(1700000300, 0),
+	}
+	td, err := msg.TypedData()
+	require.NoError(t, err)
+	require.Equal(t, typed.RevisionV1, td.Revision)
+
+	content, err := json.Marshal(td)
+	require.NoError(t, err)
+	var received typed.TypedData
+	require.NoError(t, json.Unmarshal(content, &received))
+
+	parsed, err := siws.ParseMessage(received)
+	require.NoError(t, err)
+	require.Equal(t, msg, *parsed)
+
+	// the wallet signs the same hash
+	expectedHash, err := td.GetMessageHash(utils.FeltToBigInt(msg.Address), td.Message, curve.Curve)
+	require.NoError(t, err)
+	hash, err := received.GetMessageHash(utils.FeltToBigInt(msg.Address), received.Message, curve.Curve)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, hash)
+
+	_, err = siws.ParseMessage(typed.TypedData{PrimaryType: "Mail"})
+	require.True(t, errors.Is(err, siws.ErrInvalidMessage))
+}
+
+// TestVerifier_Verify tests the verification of the signed sign-in messages.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestVerifier_Verify(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_MAIN", nil).AnyTimes()
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x5b5e9f6f6fb7d2647d81a8b2c2b99cbc9cc9d03d705576d7061812324dca5c0")
+	user, err := account.NewAccount(mockRpcProvider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	type testSetType struct {
+		Name   string
+		Modify func(msg *siws.Message)
+		Replay bool
+		// the number of calls to the account contract and whether it accepts the signature
+		ExpectedCalls int
+		Accepted      bool
+		ExpectedErr   error
+	}
+	testSet := []testSetType{
+		{
+			Name:          "valid",
+			ExpectedCalls: 1,
+			Accepted:      true,
+		},
+		{
+			Name:          "replayed",
+			Replay:        true,
+			ExpectedCalls: 2,
+			Accepted:      true,
+			ExpectedErr:   siws.ErrInvalidNonce,
+		},
+		{
+			Name:        "wrong domain",
+			Modify:      func(msg *siws.Message) { msg.Domain = "evil.com" },
+			ExpectedErr: siws.ErrWrongDomain,
+		},
+		{
+			Name:        "wrong uri",
+			Modify:      func(msg *siws.Message) { msg.URI = "https://evil.com/login" },
+			ExpectedErr: siws.ErrWrongURI,
+		},
+		{
+			Name:        "wrong chain",
+			Modify:      func(msg *siws.Message) { msg.ChainId = "SN_SEPOLIA" },
+			ExpectedErr: siws.ErrWrongChain,
+		},
+		{
+			Name:        "expired",
+			Modify:      func(msg *siws.Message) { msg.ExpirationTime = time.Now().Add(-time.Minute) },
+			ExpectedErr: siws.ErrExpired,
+		},
+		{
+			Name:        "not yet valid",
+			Modify:      func(msg *siws.Message) { msg.IssuedAt = time.Now().Add(time.Hour) },
+			ExpectedErr: siws.ErrNotYetValid,
+		},
+		{
+			// is_valid_signature and isValidSignature both reject the signature
+			Name:          "bad signature",
+			Modify:        func(msg *siws.Message) { msg.Statement = "Transfer all my tokens" },
+			ExpectedCalls: 2,
+			ExpectedErr:   siws.ErrBadSignature,
+		},
+		{
+			Name:          "unknown nonce",
+			Modify:        func(msg *siws.Message) { msg.Nonce = "0123456789abcdef" },
+			ExpectedCalls: 1,
+			Accepted:      true,
+			ExpectedErr:   siws.ErrInvalidNonce,
+		},
+		{
+			// another account signs the challenge issued for the user
+			Name:          "nonce of another account",
+			Modify:        func(msg *siws.Message) { msg.Address = utils.TestHexToFelt(t, "0xbad") },
+			ExpectedCalls: 1,
+			Accepted:      true,
+			ExpectedErr:   siws.ErrInvalidNonce,
+		},
+		{
+			Name:          "nonce of another issuance time",
+			Modify:        func(msg *siws.Message) { msg.IssuedAt = msg.IssuedAt.Add(-time.Second) },
+			ExpectedCalls: 1,
+			Accepted:      true,
+			ExpectedErr:   siws.ErrInvalidNonce,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			verifier := siws.NewVerifier(mockRpcProvider, "example.com", "https://example.com/login", "SN_MAIN", nil)
+			msg, err := verifier.NewChallenge(context.Background(), address, "Sign in to example.com")
+			require.NoError(t, err)
+			require.Equal(t, msg.IssuedAt.Add(siws.DefaultTTL), msg.ExpirationTime)
+
+			td, err := msg.TypedData()
+			require.NoError(t, err)
+			signature, err := user.SignTypedData(context.Background(), td)
+			require.NoError(t, err)
+
+			if test.Modify != nil {
+				test.Modify(msg)
+			}
+			mockRpcProvider.EXPECT().Call(context.Background(), gomock.Any(), rpc.WithBlockTag("latest")).DoAndReturn(
+				func(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+					require.Equal(t, msg.Address, call.ContractAddress)
+					if test.Accepted {
+						return []*felt.Felt{account.VALID}, nil
+					}
+					return nil, rpc.ErrContractError
+				}).Times(test.ExpectedCalls)
+
+			if test.Replay {
+				require.NoError(t, verifier.Verify(context.Background(), *msg, signature))
+			}
+			err = verifier.Verify(context.Background(), *msg, signature)
+			if test.ExpectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errors.Is(err, test.ExpectedErr), "got %v", err)
+		})
+	}
+}
+
+// TestMemNonceStore tests that a nonce is consumed once, only for the account and
+// the issuance time it was issued for.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestMemNonceStore(t *testing.T) {
+	ctx := context.Background()
+	store := siws.NewMemNonceStore()
+	address := utils.TestHexToFelt(t, "0x1234")
+	issuedAt := time.Unix(1700000000, 0)
+
+	require.NoError(t, store.Issue(ctx, "nonce", address, issuedAt, time.Time{}))
+	require.True(t, errors.Is(store.Issue(ctx, "nonce", address, issuedAt, time.Time{}), siws.ErrInvalidNonce))
+
+	// a mismatched binding doesn't consume the nonce
+	require.True(t, errors.Is(store.Consume(ctx, "nonce", utils.TestHexToFelt(t, "0xbad"), issuedAt), siws.ErrInvalidNonce))
+	require.True(t, errors.Is(store.Consume(ctx, "nonce", address, issuedAt.Add(time.Second)), siws.ErrInvalidNonce))
+	require.NoError(t, store.Consume(ctx, "nonce", address, issuedAt))
+	require.True(t, errors.Is(store.Consume(ctx, "nonce", address, issuedAt), siws.ErrInvalidNonce))
+
+	require.NoError(t, store.Issue(ctx, "expired", address, issuedAt, time.Now().Add(-time.Second)))
+	require.True(t, errors.Is(store.Consume(ctx, "expired", address, issuedAt), siws.ErrInvalidNonce))
+}
@@ -0,0 +1,450 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Revision is the revision of the SNIP-12 specification of a TypedData.
+type Revision uint8
+
+const (
+	// RevisionV0 is the legacy revision: the StarkNetDomain type, Pedersen hashes and felt, array and struct types
+	RevisionV0 Revision = iota
+	// RevisionV1 is the active revision: the StarknetDomain type, Poseidon hashes, escaped type
+	// encodings and the basic, preset, enum and merkletree types
+	RevisionV1
+)
+
+// presetTypes are the types every revision 1 TypedData can use without defining them.
+var presetTypes = map[string]TypeDef{
+	"u256": {Definitions: []Definition{
+		{Name: "low", Type: "u128"},
+		{Name: "high", Type: "u128"},
+	}},
+	"TokenAmount": {Definitions: []Definition{
+		{Name: "token_address", Type: "ContractAddress"},
+		{Name: "amount", Type: "u256"},
+	}},
+	"NftId": {Definitions: []Definition{
+		{Name: "collection_address", Type: "ContractAddress"},
+		{Name: "token_id", Type: "u256"},
+	}},
+}
+
+var (
+	// enumVariantRegexp matches the types of the parameters of an enum variant, e.g. (u128,felt*)
+	enumVariantRegexp = regexp.MustCompile(`^\(.*\)$`)
+	// hexRegexp matches the hexadecimal values of the selectors
+	hexRegexp = regexp.MustCompile(`^0[xX][0-9a-fA-F]*$`)
+
+	maxU128 = new(big.Int).Lsh(big.NewInt(1), 128)
+	maxI128 = new(big.Int).Lsh(big.NewInt(1), 127)
+	minI128 = new(big.Int).Neg(maxI128)
+)
+
+// parseRevision parses the revision of a domain.
+func parseRevision(revision string) (Revision, error) {
+	switch revision {
+	case "", "0":
+		return RevisionV0, nil
+	case "1":
+		return RevisionV1, nil
+	}
+	return RevisionV0, fmt.Errorf("unsupported typed data revision %q", revision)
+}
+
+// domainType returns the name of the domain type of the revision.
+func (r Revision) domainType() string {
+	if r == RevisionV1 {
+		return "StarknetDomain"
+	}
+	return "StarkNetDomain"
+}
+
+// Message is a typed message as decoded from JSON: the values of the fields
+// by name. The values are strings, numbers, booleans, Messages or
+// map[string]interface{} for structs and []interface{} for arrays.
+type Message map[string]interface{}
+
+// FmtDefinitionEncoding formats the encoding of a felt field of the message.
+// The Message is hashed with GetStructHash, which encodes all the types.
+//
+// Parameters:
+// - field: the field to format the encoding for
+// Returns:
+// - fmtEnc: a slice of big integers, empty if the field is not a felt
+func (m Message) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
+	if value, err := feltValue(m[field]); err == nil {
+		fmtEnc = append(fmtEnc, value)
+	}
+	return fmtEnc
+}
+
+// message returns the domain as a Message, to be hashed as the StarknetDomain type.
+func (dm Domain) message() Message {
+	return Message{
+		"name":     dm.Name,
+		"version":  dm.Version,
+		"chainId":  dm.ChainId,
+		"revision": dm.Revision,
+	}
+}
+
+// typeDef returns the definition of a type of the TypedData or a preset type of its revision.
+func (td TypedData) typeDef(typeName string) (TypeDef, bool) {
+	if typeDef, ok := td.Types[typeName]; ok {
+		return typeDef, true
+	}
+	if td.Revision == RevisionV1 {
+		typeDef, ok := presetTypes[typeName]
+		return typeDef, ok
+	}
+	return TypeDef{}, false
+}
+
+// escape returns a name of the encoding of a type, between quotes for revision 1.
+func (td TypedData) escape(name string) string {
+	if td.Revision == RevisionV1 {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// isEnumVariant reports whether a type is the parameter types of an enum variant.
+func isEnumVariant(typeName string) bool {
+	return enumVariantRegexp.MatchString(typeName)
+}
+
+// hashElements hashes the elements with the hash function of the revision:
+// Pedersen for revision 0 and Poseidon for revision 1.
+func (td TypedData) hashElements(elements []*big.Int, sc curve.StarkCurve) (*big.Int, error) {
+	if td.Revision == RevisionV1 {
+		return utils.FeltToBigInt(sc.PoseidonArray(utils.Map(elements, utils.BigIntToFelt)...)), nil
+	}
+	return sc.ComputeHashOnElements(elements)
+}
+
+// hashPair hashes two nodes of a merkle tree, the smallest first.
+func (td TypedData) hashPair(a, b *big.Int, sc curve.StarkCurve) (*big.Int, error) {
+	if a.Cmp(b) > 0 {
+		a, b = b, a
+	}
+	if td.Revision == RevisionV1 {
+		return utils.FeltToBigInt(sc.Poseidon(utils.BigIntToFelt(a), utils.BigIntToFelt(b))), nil
+	}
+	return sc.PedersenHash([]*big.Int{a, b})
+}
+
+// GetStructHash calculates the hash of a struct of the TypedData or of a preset type.
+// The values of the fields are encoded according to their types and to the
+// revision of the TypedData.
+//
+// Parameters:
+// - inType: the struct type
+// - data: the values of the fields of the struct
+// - sc: the StarkCurve used for hashing
+// Returns:
+// - hash: the calculated hash
+// - err: an error if a value is missing or does not match its type
+func (td TypedData) GetStructHash(inType string, data Message, sc curve.StarkCurve) (hash *big.Int, err error) {
+	typeDef, ok := td.typeDef(inType)
+	if !ok {
+		return hash, fmt.Errorf("can't parse type %s from types %v", inType, td.Types)
+	}
+	typeHash := typeDef.Encoding
+	if typeHash == nil {
+		if typeHash, err = td.GetTypeHash(inType); err != nil {
+			return hash, err
+		}
+	}
+
+	elements := []*big.Int{typeHash}
+	for _, def := range typeDef.Definitions {
+		value, ok := data[def.Name]
+		if !ok || (value == nil && def.Type != "enum") {
+			return hash, fmt.Errorf("missing data for %s of type %s", def.Name, inType)
+		}
+		enc, err := td.encodeValue(def.Type, value, &def, sc)
+		if err != nil {
+			return hash, fmt.Errorf("can't encode %s of type %s: %w", def.Name, inType, err)
+		}
+		elements = append(elements, enc)
+	}
+	return td.hashElements(elements, sc)
+}
+
+// encodeValue encodes a value of a type. field is the definition of the field
+// of the value, nil for the elements of the arrays and of the enums.
+func (td TypedData) encodeValue(typeName string, value interface{}, field *Definition, sc curve.StarkCurve) (*big.Int, error) {
+	if _, ok := td.typeDef(typeName); ok {
+		data, ok := asMessage(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid struct %v of type %s", value, typeName)
+		}
+		return td.GetStructHash(typeName, data, sc)
+	}
+
+	if strings.HasSuffix(typeName, "*") {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid array %v of type %s", value, typeName)
+		}
+		hashes := make([]*big.Int, len(values))
+		for i, elem := range values {
+			hash, err := td.encodeValue(strings.TrimSuffix(typeName, "*"), elem, nil, sc)
+			if err != nil {
+				return nil, err
+			}
+			hashes[i] = hash
+		}
+		return td.hashElements(hashes, sc)
+	}
+
+	if td.Revision == RevisionV0 {
+		switch typeName {
+		case "merkletree":
+			return td.encodeMerkleTree(value, field, sc)
+		case "selector":
+			return encodeSelector(value)
+		}
+		return feltValue(value)
+	}
+
+	switch typeName {
+	case "enum":
+		return td.encodeEnum(value, field, sc)
+	case "merkletree":
+		return td.encodeMerkleTree(value, field, sc)
+	case "selector":
+		return encodeSelector(value)
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid string %v", value)
+		}
+		return td.hashElements(encodeByteArray(s), sc)
+	case "i128":
+		n, err := feltValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if n.Cmp(minI128) < 0 || n.Cmp(maxI128) >= 0 {
+			return nil, fmt.Errorf("%v is out of the range of i128", value)
+		}
+		if n.Sign() < 0 {
+			n.Add(n, curve.Curve.P)
+		}
+		return n, nil
+	case "u128", "timestamp":
+		n, err := feltValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 || n.Cmp(maxU128) >= 0 {
+			return nil, fmt.Errorf("%v is out of the range of %s", value, typeName)
+		}
+		return n, nil
+	case "felt", "shortstring", "ContractAddress", "ClassHash":
+		n, err := feltValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 || n.Cmp(curve.Curve.P) >= 0 {
+			return nil, fmt.Errorf("%v is out of the range of %s", value, typeName)
+		}
+		return n, nil
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("invalid bool %v", value)
+		}
+		return feltValue(value)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typeName)
+}
+
+// encodeEnum encodes the variant of an enum as the hash of the index of the
+// variant followed by its encoded parameters.
+func (td TypedData) encodeEnum(value interface{}, field *Definition, sc curve.StarkCurve) (*big.Int, error) {
+	data, ok := asMessage(value)
+	if !ok || len(data) != 1 || field == nil {
+		return nil, fmt.Errorf("invalid enum %v", value)
+	}
+	enumDef, ok := td.typeDef(field.Contains)
+	if !ok {
+		return nil, fmt.Errorf("can't parse enum type %s from types %v", field.Contains, td.Types)
+	}
+	for index, variant := range enumDef.Definitions {
+		params, ok := data[variant.Name]
+		if !ok {
+			continue
+		}
+		values, _ := params.([]interface{})
+		elements := []*big.Int{big.NewInt(int64(index))}
+		for i, paramType := range strings.Split(variant.Type[1:len(variant.Type)-1], ",") {
+			if paramType == "" {
+				// the variants without parameters are hashed with a zero, as by starknet.js
+				elements = append(elements, big.NewInt(0))
+				continue
+			}
+			if i >= len(values) {
+				return nil, fmt.Errorf("missing parameter %d of the variant %s", i, variant.Name)
+			}
+			enc, err := td.encodeValue(paramType, values[i], nil, sc)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, enc)
+		}
+		return td.hashElements(elements, sc)
+	}
+	return nil, fmt.Errorf("invalid variant of the enum %s: %v", field.Contains, value)
+}
+
+// encodeMerkleTree encodes the leaves of a merkle tree as the root of the tree.
+// A node without sibling is hashed with a zero.
+func (td TypedData) encodeMerkleTree(value interface{}, field *Definition, sc curve.StarkCurve) (*big.Int, error) {
+	leaves, ok := value.([]interface{})
+	if !ok || len(leaves) == 0 || field == nil {
+		return nil, fmt.Errorf("invalid merkle tree %v", value)
+	}
+	if strings.HasSuffix(field.Contains, "*") {
+		return nil, fmt.Errorf("the leaves of the merkle tree %s must not be arrays", field.Name)
+	}
+	nodes := make([]*big.Int, len(leaves))
+	for i, leaf := range leaves {
+		hash, err := td.encodeValue(field.Contains, leaf, nil, sc)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = hash
+	}
+	for len(nodes) > 1 {
+		var parents []*big.Int
+		for i := 0; i < len(nodes); i += 2 {
+			sibling := big.NewInt(0)
+			if i+1 < len(nodes) {
+				sibling = nodes[i+1]
+			}
+			parent, err := td.hashPair(nodes[i], sibling, sc)
+			if err != nil {
+				return nil, err
+			}
+			parents = append(parents, parent)
+		}
+		nodes = parents
+	}
+	return nodes[0], nil
+}
+
+// encodeSelector encodes a selector given as a function name or as a hexadecimal value.
+func encodeSelector(value interface{}) (*big.Int, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid selector %v", value)
+	}
+	if hexRegexp.MatchString(s) {
+		return feltValue(s)
+	}
+	return utils.GetSelectorFromName(s), nil
+}
+
+// encodeByteArray returns the elements of the ByteArray of a string: the number
+// of its words of 31 bytes, the words, the pending word and its length.
+func encodeByteArray(s string) []*big.Int {
+	b := []byte(s)
+	var words []*big.Int
+	for len(b) >= 31 {
+		words = append(words, new(big.Int).SetBytes(b[:31]))
+		b = b[31:]
+	}
+	elements := []*big.Int{big.NewInt(int64(len(words)))}
+	elements = append(elements, words...)
+	return append(elements, new(big.Int).SetBytes(b), big.NewInt(int64(len(b))))
+}
+
+// asMessage returns the value of a struct or of an enum as a Message.
+func asMessage(value interface{}) (Message, bool) {
+	switch v := value.(type) {
+	case Message:
+		return v, true
+	case map[string]interface{}:
+		return Message(v), true
+	}
+	return nil, false
+}
+
+// feltValue converts a value to an integer: the numbers, the decimal and
+// hexadecimal strings, the booleans, and the other strings as short strings.
+func feltValue(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	case *felt.Felt:
+		return utils.FeltToBigInt(v), nil
+	case bool:
+		if v {
+			return big.NewInt(1), nil
+		}
+		return big.NewInt(0), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		n, accuracy := big.NewFloat(v).Int(nil)
+		if accuracy != big.Exact {
+			return nil, fmt.Errorf("invalid integer %v", v)
+		}
+		return n, nil
+	case json.Number:
+		n, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %v", v)
+		}
+		return n, nil
+	case string:
+		if n, ok := parseInteger(v); ok {
+			return n, nil
+		}
+		return encodeShortString(v)
+	}
+	return nil, fmt.Errorf("invalid value %v", value)
+}
+
+// parseInteger parses a decimal or a 0x, 0o or 0b prefixed integer.
+func parseInteger(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return big.NewInt(0), true
+	}
+	if len(s) > 2 && s[0] == '0' {
+		base := map[byte]int{'x': 16, 'X': 16, 'o': 8, 'O': 8, 'b': 2, 'B': 2}[s[1]]
+		if base != 0 {
+			return new(big.Int).SetString(s[2:], base)
+		}
+	}
+	return new(big.Int).SetString(s, 10)
+}
+
+// encodeShortString encodes a string of at most 31 ASCII characters as an integer.
+func encodeShortString(s string) (*big.Int, error) {
+	if len(s) > 31 {
+		return nil, fmt.Errorf("short string %q is longer than 31 characters", s)
+	}
+	for _, c := range []byte(s) {
+		if c > 0x7f {
+			return nil, fmt.Errorf("short string %q is not ASCII", s)
+		}
+	}
+	return new(big.Int).SetBytes([]byte(s)), nil
+}
@@ -0,0 +1,193 @@
+package typed
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
+)
+
+// loadTypedData reads a typed data of the tests directory.
+//
+// Parameters:
+// - t: the testing object
+// - name: the file name of the typed data
+// Returns:
+// - TypedData: the typed data, with its message
+func loadTypedData(t *testing.T, name string) TypedData {
+	content, err := os.ReadFile(filepath.Join("tests", name))
+	require.NoError(t, err)
+	var td TypedData
+	require.NoError(t, json.Unmarshal(content, &td))
+	return td
+}
+
+// TestGeneral_Revisions tests the encoding and the hashes of the typed data of both revisions.
+// The typed data are the __mocks__/typedData of starknet.js and the expected values are the ones
+// of its typedData tests.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGeneral_Revisions(t *testing.T) {
+	type testSetType struct {
+		Name                string
+		File                string
+		ExpectedRevision    Revision
+		ExpectedEncoding    string
+		ExpectedTypeHashes  map[string]string
+		ExpectedMessageHash string
+	}
+	testSet := []testSetType{
+		{
+			Name:             "struct array",
+			File:             "mail_StructArray.json",
+			ExpectedRevision: RevisionV0,
+			ExpectedEncoding: "Mail(from:Person,to:Person,posts_len:felt,posts:Post*)Person(name:felt,wallet:felt)Post(title:felt,content:felt)",
+			ExpectedTypeHashes: map[string]string{
+				"StarkNetDomain": "0x1bfc207425a47a5dfa1a50a4f5241203f50624ca5fdf5e18755765416b8e288",
+				"Post":           "0x1d71e69bf476486b43cdcfaf5a85c00bb2d954c042b281040e513080388356d",
+				"Mail":           "0x873b878e35e258fc99e3085d5aaad3a81a0c821f189c08b30def2cde55ff27",
+			},
+			ExpectedMessageHash: "0x5914ed2764eca2e6a41eb037feefd3d2e33d9af6225a9e7fe31ac943ff712c",
+		},
+		{
+			Name:             "merkletree",
+			File:             "session_MerkleTree.json",
+			ExpectedRevision: RevisionV0,
+			ExpectedEncoding: "Session(key:felt,expires:felt,root:merkletree)",
+			ExpectedTypeHashes: map[string]string{
+				"Session": "0x1aa0e1c56b45cf06a54534fa1707c54e520b842feb21d03b7deddb6f1e340c",
+				"Policy":  "0x2f0026e78543f036f33e26a8f5891b88c58dc1e20cbbfaf0bb53274da6fa568",
+			},
+			ExpectedMessageHash: "0x751fb7d98545f7649d0d0eadc80d770fcd88d8cfaa55590b284f4e1b701ef0a",
+		},
+		{
+			Name:             "base types",
+			File:             "example_baseTypes.json",
+			ExpectedRevision: RevisionV1,
+			ExpectedEncoding: `"Example"("n0":"felt","n1":"bool","n2":"string","n3":"selector","n4":"u128","n5":"i128","n6":"ContractAddress","n7":"ClassHash","n8":"timestamp","n9":"shortstring")`,
+			ExpectedTypeHashes: map[string]string{
+				"StarknetDomain": "0x1ff2f602e42168014d405a94f75e8a93d640751d71d16311266e140d8b0a210",
+				"Example":        "0x1f94cd0be8b4097a41486170fdf09a4cd23aefbc74bb2344718562994c2c111",
+			},
+			ExpectedMessageHash: "0xdb7829db8909c0c5496f5952bcfc4fc894341ce01842537fc4f448743480b6",
+		},
+		{
+			Name:             "preset types",
+			File:             "example_presetTypes.json",
+			ExpectedRevision: RevisionV1,
+			ExpectedEncoding: `"Example"("n0":"TokenAmount","n1":"NftId")"NftId"("collection_address":"ContractAddress","token_id":"u256")"TokenAmount"("token_address":"ContractAddress","amount":"u256")"u256"("low":"u128","high":"u128")`,
+			ExpectedTypeHashes: map[string]string{
+				"Example": "0x1a25a8bb84b761090b1fadaebe762c4b679b0d8883d2bedda695ea340839a55",
+			},
+			ExpectedMessageHash: "0x185b339d5c566a883561a88fb36da301051e2c0225deb325c91bb7aa2f3473a",
+		},
+		{
+			Name:                "enum",
+			File:                "example_enum.json",
+			ExpectedRevision:    RevisionV1,
+			ExpectedEncoding:    `"Example"("someEnum1":"EnumA","someEnum2":"EnumB")"EnumA"("Variant 1":(),"Variant 2":("u128","u128*"),"Variant 3":("u128"))"EnumB"("Variant 1":(),"Variant 2":("u128"))`,
+			ExpectedMessageHash: "0x6e61abaf480b1370bbf231f54e298c5f4872f40a6d2dd409ff30accee5bbd1e",
+		},
+		{
+			// the merkle tree of the revision 1 is hashed with Poseidon
+			Name:                "all in one",
+			File:                "allInOne.json",
+			ExpectedRevision:    RevisionV1,
+			ExpectedMessageHash: "0x8fa4e453de78c2762493760efd449a38eb46f85b2e02b116b77b3daa9075c8",
+		},
+	}
+	account := utils.HexToBN("0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826")
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			td := loadTypedData(t, test.File)
+			require.Equal(t, test.ExpectedRevision, td.Revision)
+
+			if test.ExpectedEncoding != "" {
+				enc, err := td.EncodeType(td.PrimaryType)
+				require.NoError(t, err)
+				require.Equal(t, test.ExpectedEncoding, enc)
+			}
+			for typeName, expected := range test.ExpectedTypeHashes {
+				hash, err := td.GetTypeHash(typeName)
+				require.NoError(t, err)
+				require.Equal(t, expected, utils.BigToHex(hash), typeName)
+			}
+
+			hash, err := td.GetMessageHash(account, td.Message, curve.Curve)
+			require.NoError(t, err)
+			require.Equal(t, test.ExpectedMessageHash, utils.BigToHex(hash))
+		})
+	}
+}
+
+// TestGeneral_GetStructHashLegacy tests that a Message is hashed as the legacy TypedMessage of the same mail.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGeneral_GetStructHashLegacy(t *testing.T) {
+	ttd := MockTypedData()
+	message := Message{
+		"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!",
+	}
+
+	hash, err := ttd.GetMessageHash(utils.HexToBN("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), message, curve.Curve)
+	require.NoError(t, err)
+	require.Equal(t, "0x6fcff244f63e38b9d88b9e3378d44757710d1b244282b435cb472053c8d78d0", utils.BigToHex(hash))
+}
+
+// TestGeneral_EncodeValueErrors tests the values rejected by the revision 1 types.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGeneral_EncodeValueErrors(t *testing.T) {
+	type testSetType struct {
+		Name  string
+		Type  string
+		Value interface{}
+	}
+	testSet := []testSetType{
+		{Name: "u128 overflow", Type: "u128", Value: new(big.Int).Lsh(big.NewInt(1), 128)},
+		{Name: "negative u128", Type: "u128", Value: "-1"},
+		{Name: "i128 underflow", Type: "i128", Value: "-170141183460469231731687303715884105729"},
+		{Name: "felt overflow", Type: "felt", Value: curve.Curve.P},
+		{Name: "bool as string", Type: "bool", Value: "true"},
+		{Name: "long shortstring", Type: "shortstring", Value: "a short string of more than 31 characters"},
+		{Name: "unsupported type", Type: "u64", Value: 1},
+		{Name: "missing", Type: "felt"},
+	}
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			types := map[string]TypeDef{
+				"StarknetDomain": {Definitions: []Definition{{Name: "name", Type: "shortstring"}}},
+				"Example":        {Definitions: []Definition{{Name: "value", Type: test.Type}}},
+			}
+			td, err := NewTypedData(types, "Example", Domain{Name: "Example", Revision: "1"})
+			require.NoError(t, err)
+
+			message := Message{}
+			if test.Value != nil {
+				message["value"] = test.Value
+			}
+			_, err = td.GetStructHash("Example", message, curve.Curve)
+			require.Error(t, err)
+		})
+	}
+}
@@ -3,9 +3,12 @@ package typed
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/curve"
@@ -17,12 +20,16 @@ type TypedData struct {
 	PrimaryType string
 	Domain      Domain
 	Message     TypedMessage
+	// Revision The SNIP-12 revision of the typed data, set from the domain by NewTypedData
+	Revision Revision
 }
 
 type Domain struct {
-	Name    string
-	Version string
-	ChainId string
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	ChainId string `json:"chainId"`
+	// Revision The SNIP-12 revision, "1" for revision 1 and empty for the legacy revision 0
+	Revision string `json:"revision,omitempty"`
 }
 
 type TypeDef struct {
@@ -31,8 +38,10 @@ type TypeDef struct {
 }
 
 type Definition struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Contains The enum type of an "enum" field or the leaf type of a "merkletree" field
+	Contains string `json:"contains,omitempty"`
 }
 
 type TypedMessage interface {
@@ -57,8 +66,10 @@ func (dm Domain) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
 		processStrToBig(dm.Name)
 	case "version":
 		processStrToBig(dm.Version)
-	case "chainId":
+	case "chainId", "chain_id":
 		processStrToBig(dm.ChainId)
+	case "revision":
+		processStrToBig(dm.Revision)
 	}
 	return fmtEnc
 }
@@ -70,7 +81,7 @@ func (dm Domain) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
 // Returns:
 // - *felt.Felt: a *felt.Felt with the value of str
 func strToFelt(str string) *felt.Felt {
-	var f = &felt.Zero
+	f := new(felt.Felt)
 	asciiRegexp := regexp.MustCompile(`^([[:graph:]]|[[:space:]]){1,31}$`)
 
 	if b, ok := new(big.Int).SetString(str, 0); ok {
@@ -91,6 +102,8 @@ func strToFelt(str string) *felt.Felt {
 
 // NewTypedData initializes a new TypedData object with the given types, primary type, and domain
 // for interacting and signing in accordance with https://github.com/0xs34n/starknet.js/tree/develop/src/utils/typedData
+// The SNIP-12 revision is the revision of the domain, whose type must be in the types:
+// StarkNetDomain for revision 0 and StarknetDomain for revision 1.
 // If the primary type is invalid, it returns an error with the message "invalid primary type: {pType}".
 // If there is an error encoding the type hash, it returns an error with the message "error encoding type hash: {enc.String()} {err}".
 //
@@ -107,6 +120,12 @@ func NewTypedData(types map[string]TypeDef, pType string, dom Domain) (td TypedD
 		PrimaryType: pType,
 		Domain:      dom,
 	}
+	if td.Revision, err = parseRevision(dom.Revision); err != nil {
+		return td, err
+	}
+	if _, ok := td.Types[td.Revision.domainType()]; !ok {
+		return td, fmt.Errorf("missing domain type %s for revision %d", td.Revision.domainType(), td.Revision)
+	}
 	if _, ok := td.Types[pType]; !ok {
 		return td, fmt.Errorf("invalid primary type: %s", pType)
 	}
@@ -135,7 +154,11 @@ func NewTypedData(types map[string]TypeDef, pType string, dom Domain) (td TypedD
 func (td TypedData) GetMessageHash(account *big.Int, msg TypedMessage, sc curve.StarkCurve) (hash *big.Int, err error) {
 	elements := []*big.Int{utils.UTF8StrToBig("StarkNet Message")}
 
-	domEnc, err := td.GetTypedMessageHash("StarkNetDomain", td.Domain, sc)
+	var domain TypedMessage = td.Domain
+	if td.Revision == RevisionV1 {
+		domain = td.Domain.message()
+	}
+	domEnc, err := td.GetTypedMessageHash(td.Revision.domainType(), domain, sc)
 	if err != nil {
 		return hash, fmt.Errorf("could not hash domain: %w", err)
 	}
@@ -148,11 +171,12 @@ func (td TypedData) GetMessageHash(account *big.Int, msg TypedMessage, sc curve.
 	}
 
 	elements = append(elements, msgEnc)
-	hash, err = sc.ComputeHashOnElements(elements)
-	return hash, err
+	return td.hashElements(elements, sc)
 }
 
 // GetTypedMessageHash calculates the hash of a typed message using the provided StarkCurve.
+// A Message is hashed with GetStructHash. For revision 1, the fields of the other
+// messages are hashed as encoded by their FmtDefinitionEncoding.
 //
 // Parameters:
 //  - inType: the type of the message
@@ -162,9 +186,20 @@ func (td TypedData) GetMessageHash(account *big.Int, msg TypedMessage, sc curve.
 //  - hash: the calculated hash
 //  - err: any error if any
 func (td TypedData) GetTypedMessageHash(inType string, msg TypedMessage, sc curve.StarkCurve) (hash *big.Int, err error) {
+	if data, ok := msg.(Message); ok {
+		return td.GetStructHash(inType, data, sc)
+	}
+
 	prim := td.Types[inType]
 	elements := []*big.Int{prim.Encoding}
 
+	if td.Revision == RevisionV1 {
+		for _, def := range prim.Definitions {
+			elements = append(elements, msg.FmtDefinitionEncoding(def.Name)...)
+		}
+		return td.hashElements(elements, sc)
+	}
+
 	for _, def := range prim.Definitions {
 		if def.Type == "felt" {
 			fmtDefinitions := msg.FmtDefinitionEncoding(def.Name)
@@ -206,7 +241,8 @@ func (td TypedData) GetTypeHash(inType string) (ret *big.Int, err error) {
 	return sel, nil
 }
 
-// EncodeType encodes the given inType using the TypedData struct.
+// EncodeType encodes the given inType using the TypedData struct: the type
+// followed by the types it depends on, sorted by name.
 //
 // Parameters:
 // - inType: the type to encode
@@ -214,35 +250,42 @@ func (td TypedData) GetTypeHash(inType string) (ret *big.Int, err error) {
 // - enc: the encoded type
 // - err: any error if any
 func (td TypedData) EncodeType(inType string) (enc string, err error) {
-	var typeDefs TypeDef
-	var ok bool
-	if typeDefs, ok = td.Types[inType]; !ok {
+	if _, ok := td.typeDef(inType); !ok {
 		return enc, fmt.Errorf("can't parse type %s from types %v", inType, td.Types)
 	}
-	var buf bytes.Buffer
-	customTypes := make(map[string]TypeDef)
-	buf.WriteString(inType)
-	buf.WriteString("(")
-	for i, def := range typeDefs.Definitions {
-		if def.Type != "felt" {
-			var customTypeDef TypeDef
-			if customTypeDef, ok = td.Types[def.Type]; !ok {
-				return enc, fmt.Errorf("can't parse type %s from types %v", def.Type, td.Types)
-			}
-			customTypes[def.Type] = customTypeDef
-		}
-		buf.WriteString(fmt.Sprintf("%s:%s", def.Name, def.Type))
-		if i != (len(typeDefs.Definitions) - 1) {
-			buf.WriteString(",")
-		}
+	deps := map[string]bool{inType: true}
+	td.collectDependencies(inType, deps)
+	delete(deps, inType)
+	types := []string{inType}
+	for dep := range deps {
+		types = append(types, dep)
 	}
-	buf.WriteString(")")
+	sort.Strings(types[1:])
 
-	for customTypeName, customType := range customTypes {
-		buf.WriteString(fmt.Sprintf("%s(", customTypeName))
-		for i, def := range customType.Definitions {
-			buf.WriteString(fmt.Sprintf("%s:%s", def.Name, def.Type))
-			if i != (len(customType.Definitions) - 1) {
+	var buf bytes.Buffer
+	for _, typeName := range types {
+		typeDef, _ := td.typeDef(typeName)
+		buf.WriteString(td.escape(typeName))
+		buf.WriteString("(")
+		for i, def := range typeDef.Definitions {
+			targetType := def.Type
+			if def.Type == "enum" && td.Revision == RevisionV1 {
+				targetType = def.Contains
+			}
+			if isEnumVariant(targetType) {
+				// the types of the parameters of an enum variant, e.g. (u128,felt*)
+				params := strings.Split(targetType[1:len(targetType)-1], ",")
+				for j, param := range params {
+					if param != "" {
+						params[j] = td.escape(param)
+					}
+				}
+				targetType = "(" + strings.Join(params, ",") + ")"
+			} else {
+				targetType = td.escape(targetType)
+			}
+			buf.WriteString(fmt.Sprintf("%s:%s", td.escape(def.Name), targetType))
+			if i != (len(typeDef.Definitions) - 1) {
 				buf.WriteString(",")
 			}
 		}
@@ -250,3 +293,133 @@ func (td TypedData) EncodeType(inType string) (enc string, err error) {
 	}
 	return buf.String(), nil
 }
+
+// collectDependencies adds the types the definitions of inType depend on to deps, recursively.
+func (td TypedData) collectDependencies(inType string, deps map[string]bool) {
+	typeDef, _ := td.typeDef(inType)
+	for _, def := range typeDef.Definitions {
+		depTypes := []string{def.Type}
+		if td.Revision == RevisionV1 {
+			if def.Type == "enum" {
+				depTypes = []string{def.Contains}
+			} else if isEnumVariant(def.Type) {
+				depTypes = strings.Split(def.Type[1:len(def.Type)-1], ",")
+			}
+		}
+		for _, dep := range depTypes {
+			dep = strings.TrimSuffix(dep, "*")
+			if _, ok := td.typeDef(dep); !ok || deps[dep] {
+				continue
+			}
+			deps[dep] = true
+			td.collectDependencies(dep, deps)
+		}
+	}
+}
+
+// typedDataJSON is the JSON layout of a TypedData.
+type typedDataJSON struct {
+	Types       map[string][]Definition `json:"types"`
+	PrimaryType string                  `json:"primaryType"`
+	Domain      Domain                  `json:"domain"`
+	Message     TypedMessage            `json:"message"`
+}
+
+// MarshalJSON marshals the TypedData with its types, primary type, domain and message.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON of the typed data
+// - error: an error if the marshaling fails
+func (td TypedData) MarshalJSON() ([]byte, error) {
+	types := make(map[string][]Definition, len(td.Types))
+	for name, typeDef := range td.Types {
+		types[name] = typeDef.Definitions
+	}
+	return json.Marshal(typedDataJSON{
+		Types:       types,
+		PrimaryType: td.PrimaryType,
+		Domain:      td.Domain,
+		Message:     td.Message,
+	})
+}
+
+// UnmarshalJSON unmarshals a typed data with the types, primaryType, domain and
+// message fields, as signed by the wallets, and initializes it as NewTypedData.
+// The message is unmarshaled as a Message, keeping the precision of the numbers.
+//
+// Parameters:
+// - data: the JSON of the typed data
+// Returns:
+// - error: an error if the unmarshaling or the initialization fails
+func (td *TypedData) UnmarshalJSON(data []byte) error {
+	var message Message
+	dec := typedDataJSON{Message: &message}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&dec); err != nil {
+		return err
+	}
+
+	types := make(map[string]TypeDef, len(dec.Types))
+	for name, defs := range dec.Types {
+		types[name] = TypeDef{Definitions: defs}
+	}
+	typedData, err := NewTypedData(types, dec.PrimaryType, dec.Domain)
+	if err != nil {
+		return err
+	}
+	typedData.Message = message
+	*td = typedData
+	return nil
+}
+
+// UnmarshalJSON unmarshals a domain whose chainId and revision may be numbers.
+// The chainId may also be named chain_id, as in some revision 0 typed data.
+//
+// Parameters:
+// - data: the JSON of the domain
+// Returns:
+// - error: an error if the unmarshaling fails
+func (dm *Domain) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	values := make(map[string]string, len(fields))
+	for _, name := range []string{"name", "version", "chainId", "chain_id", "revision"} {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case string:
+			values[name] = v
+		case json.Number:
+			values[name] = v.String()
+		case nil:
+		default:
+			return fmt.Errorf("invalid domain %s: %s", name, raw)
+		}
+	}
+	chainId, ok := values["chainId"]
+	if !ok {
+		chainId = values["chain_id"]
+	}
+	*dm = Domain{
+		Name:     values["name"],
+		Version:  values["version"],
+		ChainId:  chainId,
+		Revision: values["revision"],
+	}
+	return nil
+}
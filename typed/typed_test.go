@@ -1,12 +1,14 @@
 package typed
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/test-go/testify/require"
 )
 
 type Mail struct {
@@ -49,11 +51,11 @@ func (mail Mail) FmtDefinitionEncoding(field string) (fmtEnc []*big.Int) {
 // - ttd: the generated TypedData object
 func MockTypedData() (ttd TypedData) {
 	exampleTypes := make(map[string]TypeDef)
-	domDefs := []Definition{{"name", "felt"}, {"version", "felt"}, {"chainId", "felt"}}
+	domDefs := []Definition{{Name: "name", Type: "felt"}, {Name: "version", Type: "felt"}, {Name: "chainId", Type: "felt"}}
 	exampleTypes["StarkNetDomain"] = TypeDef{Definitions: domDefs}
-	mailDefs := []Definition{{"from", "Person"}, {"to", "Person"}, {"contents", "felt"}}
+	mailDefs := []Definition{{Name: "from", Type: "Person"}, {Name: "to", Type: "Person"}, {Name: "contents", Type: "felt"}}
 	exampleTypes["Mail"] = TypeDef{Definitions: mailDefs}
-	persDefs := []Definition{{"name", "felt"}, {"wallet", "felt"}}
+	persDefs := []Definition{{Name: "name", Type: "felt"}, {Name: "wallet", Type: "felt"}}
 	exampleTypes["Person"] = TypeDef{Definitions: persDefs}
 
 	dm := Domain{
@@ -289,3 +291,86 @@ func TestGeneral_EncodeType(t *testing.T) {
 		t.Errorf("type encoding: %v does not match expected %v\n", enc, exp)
 	}
 }
+
+// TestGeneral_UnmarshalJSON tests the hash of a typed data unmarshaled from JSON
+// and its round trip through MarshalJSON.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging test output
+// Returns:
+//
+//	none
+func TestGeneral_UnmarshalJSON(t *testing.T) {
+	type testSetType struct {
+		Name                string
+		TypedData           string
+		ExpectedRevision    Revision
+		ExpectedMessageHash string
+	}
+	testSet := []testSetType{
+		{
+			Name: "nested structs",
+			TypedData: `{
+				"types": {
+					"StarkNetDomain": [{"name": "name", "type": "felt"}, {"name": "version", "type": "felt"}, {"name": "chainId", "type": "felt"}],
+					"Person": [{"name": "name", "type": "felt"}, {"name": "wallet", "type": "felt"}],
+					"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "felt"}]
+				},
+				"primaryType": "Mail",
+				"domain": {"name": "StarkNet Mail", "version": "1", "chainId": 1},
+				"message": {
+					"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+					"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+					"contents": "Hello, Bob!"
+				}
+			}`,
+			ExpectedRevision:    RevisionV0,
+			ExpectedMessageHash: "0x6fcff244f63e38b9d88b9e3378d44757710d1b244282b435cb472053c8d78d0",
+		},
+		{
+			Name: "arrays and numeric revision",
+			TypedData: `{
+				"types": {
+					"StarknetDomain": [{"name": "name", "type": "shortstring"}, {"name": "version", "type": "shortstring"}, {"name": "chainId", "type": "shortstring"}, {"name": "revision", "type": "shortstring"}],
+					"Transfers": [{"name": "amounts", "type": "u256*"}, {"name": "recipients", "type": "ContractAddress*"}, {"name": "memo", "type": "string"}]
+				},
+				"primaryType": "Transfers",
+				"domain": {"name": "Payroll", "version": "1", "chainId": "SN_SEPOLIA", "revision": 1},
+				"message": {
+					"amounts": [{"low": "340282366920938463463374607431768211455", "high": "0"}, {"low": "0x2a", "high": "0x1"}],
+					"recipients": ["0x1", "0x2"],
+					"memo": "a memo longer than the 31 bytes of a short string"
+				}
+			}`,
+			ExpectedRevision:    RevisionV1,
+			ExpectedMessageHash: "0x5fb7c8b935dab53461f21144b63515bff6dc71ec4b8726320d588cc309fa340",
+		},
+	}
+	account := utils.HexToBN("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	// the chain id of a revision 0 domain may be named chain_id
+	var domain Domain
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "StarkNet Mail", "version": "1", "chain_id": 1}`), &domain))
+	require.Equal(t, Domain{Name: "StarkNet Mail", Version: "1", ChainId: "1"}, domain)
+	require.Equal(t, domain.FmtDefinitionEncoding("chainId"), domain.FmtDefinitionEncoding("chain_id"))
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			var td TypedData
+			require.NoError(t, json.Unmarshal([]byte(test.TypedData), &td))
+			require.Equal(t, test.ExpectedRevision, td.Revision)
+			hash, err := td.GetMessageHash(account, td.Message, curve.Curve)
+			require.NoError(t, err)
+			require.Equal(t, test.ExpectedMessageHash, utils.BigToHex(hash))
+
+			content, err := json.Marshal(td)
+			require.NoError(t, err)
+			var unmarshaled TypedData
+			require.NoError(t, json.Unmarshal(content, &unmarshaled))
+			require.Equal(t, td, unmarshaled)
+			unmarshaledHash, err := unmarshaled.GetMessageHash(account, unmarshaled.Message, curve.Curve)
+			require.NoError(t, err)
+			require.Equal(t, hash, unmarshaledHash)
+		})
+	}
+}